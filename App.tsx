@@ -3,9 +3,10 @@ import Login from './components/Login';
 import ServiceCard from './components/ServiceCard';
 import ConfigViewer from './components/ConfigViewer';
 import HostStats from './components/HostStats';
+import DockerDisk from './components/DockerDisk';
 import { Service } from './types';
-import { getServices, checkAuth, logout } from './services/api';
-import { LayoutGrid, LogOut, Search, Activity, Cpu, RefreshCw } from 'lucide-react';
+import { getServices, getBackendHealth, checkAuth, logout, BackendHealth } from './services/api';
+import { LayoutGrid, LogOut, Search, Activity, Cpu, RefreshCw, Unplug } from 'lucide-react';
 
 const POLL_INTERVAL = 10000; // 10 seconds
 
@@ -17,6 +18,7 @@ const App: React.FC = () => {
   const [services, setServices] = useState<Service[]>([]);
   const [isLoading, setIsLoading] = useState(false);
   const [error, setError] = useState<string | null>(null);
+  const [unreachable, setUnreachable] = useState<BackendHealth[]>([]);
 
   // Check authentication status on mount
   useEffect(() => {
@@ -32,8 +34,12 @@ const App: React.FC = () => {
 
     try {
       setIsLoading(true);
-      const data = await getServices();
+      const [data, health] = await Promise.all([
+        getServices(),
+        getBackendHealth().catch(() => ({ backends: [] as BackendHealth[] })),
+      ]);
       setServices(data.services);
+      setUnreachable(health.backends.filter(b => !b.connected));
       setError(null);
     } catch (err: any) {
       setError(err.message || 'Failed to fetch services');
@@ -161,6 +167,25 @@ const App: React.FC = () => {
         {/* Host Stats */}
         <HostStats />
 
+        {/* Docker Disk Usage */}
+        <DockerDisk />
+
+        {/* Unreachable Backends */}
+        {unreachable.map(b => (
+          <div
+            key={`${b.backend}-${b.name || 'local'}`}
+            className="mb-6 p-4 bg-slate-500/10 border border-slate-500/20 rounded-lg text-slate-300 flex items-center gap-3"
+            title={b.error}
+          >
+            <Unplug className="w-5 h-5 text-amber-500 shrink-0" />
+            <span>
+              {b.backend === 'docker' ? 'Docker daemon' : `${b.backend} backend`}
+              {b.name && ` "${b.name}"`} unreachable
+              {b.attempts ? `, reconnecting (attempt ${b.attempts})` : ''}
+            </span>
+          </div>
+        ))}
+
         {/* Error Message */}
         {error && (
           <div className="mb-6 p-4 bg-red-500/10 border border-red-500/20 rounded-lg text-red-400">
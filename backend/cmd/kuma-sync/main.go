@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/logger"
+	"home-run-backend/internal/services/kuma"
+
+	// Registers the built-in backends so config.Load accepts their services
+	_ "home-run-backend/internal/services"
+)
+
+func main() {
+	// Parse command line flags
+	configPath := flag.String("config", "config.yml", "Path to configuration file")
+	write := flag.Bool("write", false, "Write the changes to config.yml instead of only printing them")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		logger.Log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.UptimeKuma == nil {
+		logger.Log.Fatal("uptime_kuma is not configured")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	monitors, err := kuma.NewClient(cfg.UptimeKuma).Monitors(ctx)
+	if err != nil {
+		logger.Log.Fatalf("Failed to list Uptime Kuma monitors: %v", err)
+	}
+
+	result, err := kuma.SyncConfig(*configPath, monitors, !*write)
+	if err != nil {
+		logger.Log.Fatalf("Failed to sync configuration: %v", err)
+	}
+
+	fmt.Print(result.Diff)
+	fmt.Fprintf(os.Stderr, "%d monitors: %d added, %d updated\n", len(monitors), len(result.Added), len(result.Updated))
+	for _, id := range result.Missing {
+		fmt.Fprintf(os.Stderr, "kuma_monitor_id %d is configured but no longer exists in Uptime Kuma\n", id)
+	}
+	switch {
+	case result.Written:
+		fmt.Fprintf(os.Stderr, "Wrote %s; restart the server to apply\n", *configPath)
+	case result.Diff == "":
+		fmt.Fprintln(os.Stderr, "Configuration is up to date")
+	default:
+		fmt.Fprintln(os.Stderr, "Dry run, nothing written; run again with -write to apply")
+	}
+}
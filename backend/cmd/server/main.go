@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"home-run-backend/internal/api"
+	"home-run-backend/internal/audit"
 	"home-run-backend/internal/config"
 	"home-run-backend/internal/logger"
 	"home-run-backend/internal/services"
@@ -47,8 +48,15 @@ func main() {
 	// Initialize federation aggregator
 	aggregator := federation.NewAggregator(manager, cfg.RemoteHosts)
 
+	// Open audit log for container actions
+	auditLog, err := audit.New(cfg.Server.AuditLog)
+	if err != nil {
+		logger.Log.Fatalf("Failed to open audit log: %v", err)
+	}
+	defer auditLog.Close()
+
 	// Setup router
-	router := api.SetupRouter(cfg, manager, aggregator)
+	router := api.SetupRouter(cfg, manager, aggregator, auditLog)
 
 	// Create HTTP server
 	addr := fmt.Sprintf(":%d", cfg.Server.Port)
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"home-run-backend/internal/logger"
+	"home-run-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type CertificatesHandler struct {
+	manager *services.Manager
+}
+
+func NewCertificatesHandler(manager *services.Manager) *CertificatesHandler {
+	return &CertificatesHandler{
+		manager: manager,
+	}
+}
+
+// List returns the latest TLS certificate checks
+func (h *CertificatesHandler) List(c *gin.Context) {
+	certificates := h.manager.Certificates()
+
+	// Count certificates that are expiring or already expired
+	expiring := 0
+	for _, cert := range certificates {
+		if cert.Status == "EXPIRING" || cert.Status == "EXPIRED" {
+			expiring++
+		}
+	}
+
+	logger.WithFields(logrus.Fields{
+		"total":    len(certificates),
+		"expiring": expiring,
+	}).Debug("Listed certificates")
+
+	c.JSON(http.StatusOK, gin.H{
+		"certificates": certificates,
+		"total":        len(certificates),
+		"expiring":     expiring,
+	})
+}
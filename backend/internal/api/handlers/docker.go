@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"home-run-backend/internal/audit"
+	"home-run-backend/internal/auth"
+	"home-run-backend/internal/logger"
+	"home-run-backend/internal/services"
+	"home-run-backend/internal/services/docker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type DockerHandler struct {
+	manager *services.Manager
+	audit   *audit.Log
+}
+
+func NewDockerHandler(manager *services.Manager, auditLog *audit.Log) *DockerHandler {
+	return &DockerHandler{
+		manager: manager,
+		audit:   auditLog,
+	}
+}
+
+// pruneRequest selects the daemon and the kinds of data to prune
+type pruneRequest struct {
+	DockerHost string `json:"dockerHost"` // key in docker_hosts, empty for the local daemon
+	docker.PruneOptions
+}
+
+// Disk returns image, container, volume and build cache usage of every docker
+// daemon, with dangling images and unused volumes
+func (h *DockerHandler) Disk(c *gin.Context) {
+	usage, err := h.manager.DockerDiskUsage(c.Request.Context())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrDockerUnavailable) {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"daemons": usage,
+	})
+}
+
+// AutoHeal returns the automatic restarts of services with auto_heal, newest first
+func (h *DockerHandler) AutoHeal(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"events": h.manager.AutoHealEvents(),
+	})
+}
+
+// Prune removes stopped containers, dangling images, anonymous volumes and
+// build cache as selected. Every attempt is written to the audit log.
+func (h *DockerHandler) Prune(c *gin.Context) {
+	var req pruneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+		return
+	}
+
+	report, err := h.manager.DockerPrune(c.Request.Context(), req.DockerHost, req.PruneOptions)
+
+	entry := audit.Entry{
+		User:       auth.GetUser(c),
+		RemoteAddr: c.ClientIP(),
+		Action:     "prune",
+		Target:     pruneTarget(req),
+		Success:    err == nil,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	h.audit.Record(entry)
+
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrPruneNotAllowed):
+			status = http.StatusForbidden
+		case errors.Is(err, services.ErrDockerUnavailable):
+			status = http.StatusServiceUnavailable
+		case errors.Is(err, docker.ErrNothingToPrune):
+			status = http.StatusBadRequest
+		}
+
+		logger.WithFields(logrus.Fields{
+			"docker_host": req.DockerHost,
+			"error":       err.Error(),
+		}).Warn("Docker prune failed")
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// pruneTarget describes a prune for the audit log, e.g. "local: images,volumes"
+func pruneTarget(req pruneRequest) string {
+	host := req.DockerHost
+	if host == "" {
+		host = "local"
+	}
+
+	var kinds []string
+	for _, kind := range []struct {
+		name     string
+		selected bool
+	}{
+		{"containers", req.Containers},
+		{"images", req.Images},
+		{"volumes", req.Volumes},
+		{"build_cache", req.BuildCache},
+	} {
+		if kind.selected {
+			kinds = append(kinds, kind.name)
+		}
+	}
+	return host + ": " + strings.Join(kinds, ",")
+}
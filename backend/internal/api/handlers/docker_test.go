@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"home-run-backend/internal/audit"
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerHandler_WithoutDocker(t *testing.T) {
+	cfg := &config.Config{}
+	manager, err := services.NewManager(cfg)
+	require.NoError(t, err)
+
+	handler := NewDockerHandler(manager, nil)
+	router := setupTestRouter(cfg)
+	router.GET("/docker/disk", handler.Disk)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/docker/disk", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestDockerHandler_Prune(t *testing.T) {
+	tests := []struct {
+		name       string
+		allowPrune bool
+		body       string
+		status     int
+	}{
+		{"not allowed", false, `{"images":true}`, http.StatusForbidden},
+		{"no docker", true, `{"images":true}`, http.StatusServiceUnavailable},
+		{"invalid body", true, `not json`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Server: config.ServerConfig{AllowPrune: tt.allowPrune}}
+			manager, err := services.NewManager(cfg)
+			require.NoError(t, err)
+
+			auditLog, err := audit.New(filepath.Join(t.TempDir(), "audit.log"))
+			require.NoError(t, err)
+			defer auditLog.Close()
+
+			handler := NewDockerHandler(manager, auditLog)
+			router := setupTestRouter(cfg)
+			router.POST("/docker/disk/prune", handler.Prune)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("POST", "/docker/disk/prune", strings.NewReader(tt.body)))
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestDockerHandler_Prune_Audited(t *testing.T) {
+	cfg := &config.Config{}
+	manager, err := services.NewManager(cfg)
+	require.NoError(t, err)
+
+	auditPath := filepath.Join(t.TempDir(), "audit.log")
+	auditLog, err := audit.New(auditPath)
+	require.NoError(t, err)
+	defer auditLog.Close()
+
+	handler := NewDockerHandler(manager, auditLog)
+	router := setupTestRouter(cfg)
+	router.POST("/docker/disk/prune", handler.Prune)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/docker/disk/prune", strings.NewReader(`{"dockerHost":"nas","images":true,"buildCache":true}`)))
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	file, err := os.Open(auditPath)
+	require.NoError(t, err)
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	require.True(t, scanner.Scan())
+	var entry audit.Entry
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+	assert.Equal(t, "prune", entry.Action)
+	assert.Equal(t, "nas: images,build_cache", entry.Target)
+	assert.False(t, entry.Success)
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"home-run-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	manager *services.Manager
+}
+
+func NewHealthHandler(manager *services.Manager) *HealthHandler {
+	return &HealthHandler{
+		manager: manager,
+	}
+}
+
+// Backends returns whether each backend can reach its daemon, so the
+// dashboard can tell an unreachable daemon from failed services
+func (h *HealthHandler) Backends(c *gin.Context) {
+	backends := h.manager.BackendHealth()
+
+	healthy := true
+	for _, backend := range backends {
+		if !backend.Connected {
+			healthy = false
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"backends": backends,
+		"healthy":  healthy,
+	})
+}
@@ -3,63 +3,45 @@ package handlers
 import (
 	"net/http"
 
+	"home-run-backend/internal/history"
 	"home-run-backend/internal/logger"
-	"home-run-backend/internal/models"
+	"home-run-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/disk"
-	"github.com/shirou/gopsutil/v3/mem"
 )
 
-type HostHandler struct{}
-
-func NewHostHandler() *HostHandler {
-	return &HostHandler{}
+type HostHandler struct {
+	manager *services.Manager
 }
 
-// Stats returns system resource usage
-func (h *HostHandler) Stats(c *gin.Context) {
-	stats := models.HostStats{}
-
-	// Get CPU info
-	cpuPercent, err := cpu.Percent(0, false)
-	if err == nil && len(cpuPercent) > 0 {
-		stats.CPU.Usage = cpuPercent[0]
-	} else if err != nil {
-		logger.WithField("error", err.Error()).Warn("Failed to get CPU usage")
+func NewHostHandler(manager *services.Manager) *HostHandler {
+	return &HostHandler{
+		manager: manager,
 	}
+}
 
-	cpuInfo, err := cpu.Info()
-	if err == nil && len(cpuInfo) > 0 {
-		stats.CPU.Cores = len(cpuInfo)
-		totalThreads := 0
-		for _, info := range cpuInfo {
-			totalThreads += int(info.Cores)
-		}
-		stats.CPU.Threads = totalThreads
-	} else if err != nil {
-		logger.WithField("error", err.Error()).Warn("Failed to get CPU info")
-	}
+// Stats returns the latest sample of system resource usage
+func (h *HostHandler) Stats(c *gin.Context) {
+	logger.Log.Debug("Retrieved host stats")
+	c.JSON(http.StatusOK, h.manager.HostStats())
+}
 
-	// Get memory info
-	memInfo, err := mem.VirtualMemory()
+// History returns host cpu or memory usage over a range, averaged into
+// steps, with the same parameters as the service history
+func (h *HostHandler) History(c *gin.Context) {
+	metric, span, step, err := parseHistoryQuery(c)
+	var series *history.Series
 	if err == nil {
-		stats.Memory.UsedGB = float64(memInfo.Used) / (1024 * 1024 * 1024)
-		stats.Memory.TotalGB = float64(memInfo.Total) / (1024 * 1024 * 1024)
-	} else {
-		logger.WithField("error", err.Error()).Warn("Failed to get memory info")
+		series, err = h.manager.HostHistory(metric, span, step)
 	}
-
-	// Get disk info for root partition
-	diskInfo, err := disk.Usage("/")
-	if err == nil {
-		stats.Storage.UsedGB = float64(diskInfo.Used) / (1024 * 1024 * 1024)
-		stats.Storage.TotalGB = float64(diskInfo.Total) / (1024 * 1024 * 1024)
-	} else {
-		logger.WithField("error", err.Error()).Warn("Failed to get disk info")
+	if err != nil {
+		logger.WithField("error", err.Error()).Warn("Failed to query host history")
+		c.JSON(historyErrorStatus(err), gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
 	}
 
-	logger.Log.Debug("Retrieved host stats")
-	c.JSON(http.StatusOK, stats)
+	c.JSON(http.StatusOK, series)
 }
@@ -1,24 +1,38 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"home-run-backend/internal/config"
 	"home-run-backend/internal/models"
+	"home-run-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestHostHandler_Stats(t *testing.T) {
+func setupHostRouter(t *testing.T) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 
-	handler := NewHostHandler()
+	manager, err := services.NewManager(&config.Config{})
+	require.NoError(t, err)
+	manager.Start(context.Background())
+	t.Cleanup(manager.Stop)
+
+	handler := NewHostHandler(manager)
 	router := gin.New()
 	router.GET("/stats", handler.Stats)
+	router.GET("/history", handler.History)
+	return router
+}
+
+func TestHostHandler_Stats(t *testing.T) {
+	router := setupHostRouter(t)
 
 	req := httptest.NewRequest("GET", "/stats", nil)
 	w := httptest.NewRecorder()
@@ -42,3 +56,41 @@ func TestHostHandler_Stats(t *testing.T) {
 	// Storage stats
 	assert.GreaterOrEqual(t, stats.Storage.TotalGB, 0.0)
 }
+
+func TestHostHandler_History(t *testing.T) {
+	router := setupHostRouter(t)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"live", "/history?metric=memory&range=10m&step=5s", http.StatusOK},
+		{"defaults without history", "/history", http.StatusOK},
+		{"past the window without history", "/history?range=24h", http.StatusServiceUnavailable},
+		{"service-only metric", "/history?metric=latency", http.StatusBadRequest},
+		{"bad step", "/history?step=often", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+
+	// The collector sampled once on start
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/history?metric=memory&range=10m&step=5s", nil))
+	var series struct {
+		Source string `json:"source"`
+		Points []struct {
+			Value *float64 `json:"value"`
+		} `json:"points"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &series))
+	assert.Equal(t, "live", series.Source)
+	require.Len(t, series.Points, 120)
+	assert.NotNil(t, series.Points[119].Value)
+}
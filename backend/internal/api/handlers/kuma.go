@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"home-run-backend/internal/audit"
+	"home-run-backend/internal/auth"
+	"home-run-backend/internal/logger"
+	"home-run-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type KumaHandler struct {
+	manager *services.Manager
+	audit   *audit.Log
+}
+
+func NewKumaHandler(manager *services.Manager, auditLog *audit.Log) *KumaHandler {
+	return &KumaHandler{
+		manager: manager,
+		audit:   auditLog,
+	}
+}
+
+// kumaSyncRequest selects whether a sync only previews its diff. A sync is a
+// dry run unless dryRun is explicitly false.
+type kumaSyncRequest struct {
+	DryRun *bool `json:"dryRun"`
+}
+
+// Monitors lists every monitor of the Uptime Kuma instance, with the
+// configured service of each
+func (h *KumaHandler) Monitors(c *gin.Context) {
+	monitors, err := h.manager.KumaMonitors(c.Request.Context())
+	if err != nil {
+		c.JSON(kumaErrorStatus(err), gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"monitors": monitors,
+	})
+}
+
+// Sync adds a service to config.yml for every Kuma monitor without one and
+// updates the url and port of the others. Only a body with "dryRun": false
+// writes the file; writes need server.allow_kuma_sync and are recorded in the
+// audit log, while dry runs are always allowed.
+func (h *KumaHandler) Sync(c *gin.Context) {
+	// The body is optional, so an empty one is a dry run
+	var req kumaSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+		return
+	}
+
+	dryRun := req.DryRun == nil || *req.DryRun
+	result, err := h.manager.SyncKumaMonitors(c.Request.Context(), dryRun)
+
+	if !dryRun {
+		entry := audit.Entry{
+			User:       auth.GetUser(c),
+			RemoteAddr: c.ClientIP(),
+			Action:     "kuma_sync",
+			Target:     "config",
+			Success:    err == nil,
+		}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		h.audit.Record(entry)
+	}
+
+	if err != nil {
+		logger.WithField("error", err.Error()).Warn("Uptime Kuma sync failed")
+		c.JSON(kumaErrorStatus(err), gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func kumaErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrKumaNotConfigured):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrKumaSyncNotAllowed):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrBackendUnavailable):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"home-run-backend/internal/audit"
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/services"
+	"home-run-backend/internal/services/kuma"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const kumaSyncMetrics = `monitor_status{monitor_id="1",monitor_name="Nginx",monitor_type="http",monitor_url="https://example.com",monitor_hostname="null",monitor_port="null"} 1
+monitor_status{monitor_id="2",monitor_name="Plex",monitor_type="port",monitor_url="https://",monitor_hostname="nas.lan",monitor_port="32400"} 1
+`
+
+const kumaSyncConfig = `auth:
+  username: admin
+  password: admin
+  api_token: token
+
+uptime_kuma:
+  url: %s
+
+services:
+  - name: Nginx
+    url: https://example.com
+    port: 443
+    backend: uptime_kuma
+    kuma_monitor_id: 1
+`
+
+func setupKumaHandler(t *testing.T) (*KumaHandler, *config.Config) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(kumaSyncMetrics))
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yml")
+	content := fmt.Sprintf(kumaSyncConfig, server.URL)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+	manager, err := services.NewManager(cfg)
+	require.NoError(t, err)
+
+	auditLog, err := audit.New(filepath.Join(dir, "audit.log"))
+	require.NoError(t, err)
+	t.Cleanup(func() { auditLog.Close() })
+
+	return NewKumaHandler(manager, auditLog), cfg
+}
+
+func TestKumaHandler_Monitors(t *testing.T) {
+	handler, cfg := setupKumaHandler(t)
+	router := setupTestRouter(cfg)
+	router.GET("/kuma/monitors", handler.Monitors)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/kuma/monitors", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Monitors []kuma.Monitor `json:"monitors"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Monitors, 2)
+	assert.Equal(t, "Nginx", response.Monitors[0].Service)
+	assert.Empty(t, response.Monitors[1].Service)
+}
+
+func TestKumaHandler_Sync(t *testing.T) {
+	handler, cfg := setupKumaHandler(t)
+	router := setupTestRouter(cfg)
+	router.POST("/kuma/sync", handler.Sync)
+
+	sync := func(body string) (int, kuma.SyncResult) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/kuma/sync", strings.NewReader(body)))
+		var result kuma.SyncResult
+		_ = json.Unmarshal(w.Body.Bytes(), &result)
+		return w.Code, result
+	}
+
+	status, result := sync(`{"dryRun":true}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Contains(t, result.Diff, "+    kuma_monitor_id: 2")
+	assert.False(t, result.Written)
+
+	// Without dryRun the sync only previews, even with writes allowed
+	cfg.Server.AllowKumaSync = true
+	for _, body := range []string{``, `{}`} {
+		status, result = sync(body)
+		require.Equal(t, http.StatusOK, status)
+		assert.NotEmpty(t, result.Diff)
+		assert.False(t, result.Written)
+	}
+	unchanged, err := os.ReadFile(cfg.Path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(unchanged), "name: Plex")
+
+	// Writing needs server.allow_kuma_sync
+	cfg.Server.AllowKumaSync = false
+	status, _ = sync(`{"dryRun":false}`)
+	assert.Equal(t, http.StatusForbidden, status)
+
+	cfg.Server.AllowKumaSync = true
+	status, result = sync(`{"dryRun":false}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.True(t, result.Written)
+	content, err := os.ReadFile(cfg.Path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "  - name: Plex\n    url: http://nas.lan\n    port: 32400\n")
+
+	status, _ = sync(`not json`)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestKumaHandler_NotConfigured(t *testing.T) {
+	cfg := &config.Config{}
+	manager, err := services.NewManager(cfg)
+	require.NoError(t, err)
+
+	handler := NewKumaHandler(manager, nil)
+	router := setupTestRouter(cfg)
+	router.GET("/kuma/monitors", handler.Monitors)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/kuma/monitors", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
+	"home-run-backend/internal/audit"
+	"home-run-backend/internal/auth"
+	"home-run-backend/internal/history"
 	"home-run-backend/internal/logger"
 	"home-run-backend/internal/services"
+	"home-run-backend/internal/services/docker"
 	"home-run-backend/internal/services/federation"
 
 	"github.com/gin-gonic/gin"
@@ -15,12 +22,14 @@ import (
 type ServicesHandler struct {
 	manager    *services.Manager
 	aggregator *federation.Aggregator
+	audit      *audit.Log
 }
 
-func NewServicesHandler(manager *services.Manager, aggregator *federation.Aggregator) *ServicesHandler {
+func NewServicesHandler(manager *services.Manager, aggregator *federation.Aggregator, auditLog *audit.Log) *ServicesHandler {
 	return &ServicesHandler{
 		manager:    manager,
 		aggregator: aggregator,
+		audit:      auditLog,
 	}
 }
 
@@ -71,6 +80,65 @@ func (h *ServicesHandler) Get(c *gin.Context) {
 	c.JSON(http.StatusOK, svc)
 }
 
+// History returns a metric of a service over a range, averaged into steps:
+// ?metric=cpu|memory|latency|status&range=1h&step=1m. Without a step the
+// range is split into 60 points.
+func (h *ServicesHandler) History(c *gin.Context) {
+	serviceID := c.Param("id")
+
+	metric, span, step, err := parseHistoryQuery(c)
+	var series *history.Series
+	if err == nil {
+		series, err = h.manager.ServiceHistory(serviceID, metric, span, step)
+	}
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"service_id": serviceID,
+			"error":      err.Error(),
+		}).Warn("Failed to query service history")
+		c.JSON(historyErrorStatus(err), gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// parseHistoryQuery parses the metric, range and step parameters of a
+// history request, defaulting to cpu over 1h in 60 steps
+func parseHistoryQuery(c *gin.Context) (history.Metric, time.Duration, time.Duration, error) {
+	metric, err := history.ParseMetric(c.DefaultQuery("metric", "cpu"))
+	if err != nil {
+		return "", 0, 0, err
+	}
+	span, err := history.ParseSpan(c.DefaultQuery("range", "1h"))
+	if err != nil {
+		return "", 0, 0, err
+	}
+	step := span / 60
+	if value := c.Query("step"); value != "" {
+		if step, err = history.ParseSpan(value); err != nil {
+			return "", 0, 0, err
+		}
+	}
+	return metric, span, step, nil
+}
+
+// historyErrorStatus maps a history query error to an HTTP status
+func historyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrServiceNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, history.ErrInvalidQuery):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrHistoryDisabled):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
 // GetConfig returns the content of a service's config file
 func (h *ServicesHandler) GetConfig(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -112,3 +180,175 @@ func (h *ServicesHandler) GetConfig(c *gin.Context) {
 
 	c.JSON(http.StatusOK, config)
 }
+
+// Action starts, stops, restarts, pauses or unpauses a service's container
+// and returns its new state. Every attempt is written to the audit log.
+func (h *ServicesHandler) Action(c *gin.Context) {
+	ctx := c.Request.Context()
+	serviceID := c.Param("id")
+	action := c.Param("action")
+
+	info, err := h.manager.ServiceAction(ctx, serviceID, action)
+
+	entry := audit.Entry{
+		User:       auth.GetUser(c),
+		RemoteAddr: c.ClientIP(),
+		Action:     action,
+		ServiceID:  serviceID,
+		Success:    err == nil,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	h.audit.Record(entry)
+
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrServiceNotFound), errors.Is(err, docker.ErrContainerNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, services.ErrActionsNotAllowed):
+			status = http.StatusForbidden
+		case errors.Is(err, docker.ErrUnknownAction), errors.Is(err, services.ErrNotContainer):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrBackendUnavailable), errors.Is(err, services.ErrDockerUnavailable):
+			status = http.StatusServiceUnavailable
+		}
+
+		logger.WithFields(logrus.Fields{
+			"service_id": serviceID,
+			"action":     action,
+			"error":      err.Error(),
+		}).Warn("Service action failed")
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
+
+// Inspect returns a curated view of a service's container: image, ports,
+// mounts, networks, labels, restart policy and redacted environment
+func (h *ServicesHandler) Inspect(c *gin.Context) {
+	ctx := c.Request.Context()
+	serviceID := c.Param("id")
+
+	details, err := h.manager.ServiceInspect(ctx, serviceID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrServiceNotFound), errors.Is(err, docker.ErrContainerNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, services.ErrNotContainer):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrBackendUnavailable), errors.Is(err, services.ErrDockerUnavailable):
+			status = http.StatusServiceUnavailable
+		}
+
+		logger.WithFields(logrus.Fields{
+			"service_id": serviceID,
+			"error":      err.Error(),
+		}).Warn("Failed to inspect service")
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, details)
+}
+
+// defaultLogTail is the number of log lines returned when tail is not given
+const defaultLogTail = 100
+
+// Logs streams a service's container log as server-sent events: one "log"
+// event per line, then "end" when the log is finished, or "error" if reading fails.
+// Query parameters: tail (number or "all"), since, follow, grep and invert.
+func (h *ServicesHandler) Logs(c *gin.Context) {
+	ctx := c.Request.Context()
+	serviceID := c.Param("id")
+
+	opts := docker.LogOptions{
+		Tail:   defaultLogTail,
+		Since:  c.Query("since"),
+		Follow: c.Query("follow") == "true",
+		Grep:   c.Query("grep"),
+		Invert: c.Query("invert") == "true",
+	}
+	if tail := c.Query("tail"); tail == "all" {
+		opts.Tail = -1
+	} else if tail != "" {
+		n, err := strconv.Atoi(tail)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "tail must be a non-negative number or 'all'",
+			})
+			return
+		}
+		opts.Tail = n
+	}
+
+	stream, err := h.manager.ServiceLogs(ctx, serviceID, opts)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrServiceNotFound), errors.Is(err, docker.ErrContainerNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, services.ErrNotContainer), errors.Is(err, docker.ErrInvalidLogOptions):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrBackendUnavailable), errors.Is(err, services.ErrDockerUnavailable):
+			status = http.StatusServiceUnavailable
+		}
+
+		logger.WithFields(logrus.Fields{
+			"service_id": serviceID,
+			"error":      err.Error(),
+		}).Warn("Failed to open service logs")
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer stream.Close()
+
+	// Followed logs stay open past the server's write timeout
+	if opts.Follow {
+		_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	for {
+		line, err := stream.Next()
+		if errors.Is(err, io.EOF) {
+			c.SSEvent("end", gin.H{})
+			break
+		}
+		if err != nil {
+			// The client going away cancels the request context; that's not an error
+			if ctx.Err() == nil {
+				logger.WithFields(logrus.Fields{
+					"service_id": serviceID,
+					"error":      err.Error(),
+				}).Warn("Failed to read service logs")
+				c.SSEvent("error", gin.H{"error": err.Error()})
+			}
+			break
+		}
+
+		c.SSEvent("log", line)
+		if opts.Follow {
+			c.Writer.Flush()
+		}
+	}
+	c.Writer.Flush()
+}
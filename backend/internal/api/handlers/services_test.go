@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"home-run-backend/internal/audit"
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServicesHandler_Action(t *testing.T) {
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{
+			{Name: "Website", Backend: "http", URL: "http://127.0.0.1", Port: 1},
+		},
+	}
+	manager, err := services.NewManager(cfg)
+	require.NoError(t, err)
+
+	auditPath := filepath.Join(t.TempDir(), "audit.log")
+	auditLog, err := audit.New(auditPath)
+	require.NoError(t, err)
+	defer auditLog.Close()
+
+	handler := NewServicesHandler(manager, nil, auditLog)
+	router := setupTestRouter(cfg)
+	router.POST("/services/:id/:action", handler.Action)
+
+	all := manager.GetAll(context.Background())
+	require.Len(t, all, 1)
+	websiteID := all[0].ID
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"actions not allowed", "/services/" + websiteID + "/restart", http.StatusForbidden},
+		{"unknown service", "/services/missing/stop", http.StatusNotFound},
+		{"unknown action", "/services/" + websiteID + "/delete", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("POST", tt.path, nil))
+			assert.Equal(t, tt.status, w.Code)
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.False(t, response["success"].(bool))
+		})
+	}
+
+	// Every attempt is audited, including rejected ones
+	file, err := os.Open(auditPath)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var entries []audit.Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry audit.Entry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.Len(t, entries, len(tests))
+	assert.Equal(t, "restart", entries[0].Action)
+	assert.Equal(t, websiteID, entries[0].ServiceID)
+	assert.False(t, entries[0].Success)
+	assert.NotEmpty(t, entries[0].Error)
+}
+
+func TestServicesHandler_Logs_Errors(t *testing.T) {
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{
+			{Name: "Website", Backend: "http", URL: "http://127.0.0.1", Port: 1},
+		},
+	}
+	manager, err := services.NewManager(cfg)
+	require.NoError(t, err)
+
+	handler := NewServicesHandler(manager, nil, nil)
+	router := setupTestRouter(cfg)
+	router.GET("/services/:id/logs", handler.Logs)
+
+	all := manager.GetAll(context.Background())
+	require.Len(t, all, 1)
+	websiteID := all[0].ID
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"not a container", "/services/" + websiteID + "/logs", http.StatusBadRequest},
+		{"bad tail", "/services/" + websiteID + "/logs?tail=-5", http.StatusBadRequest},
+		{"unknown service", "/services/missing/logs", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestServicesHandler_Inspect_Errors(t *testing.T) {
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{
+			{Name: "Website", Backend: "http", URL: "http://127.0.0.1", Port: 1},
+		},
+	}
+	manager, err := services.NewManager(cfg)
+	require.NoError(t, err)
+
+	handler := NewServicesHandler(manager, nil, nil)
+	router := setupTestRouter(cfg)
+	router.GET("/services/:id/inspect", handler.Inspect)
+
+	all := manager.GetAll(context.Background())
+	require.Len(t, all, 1)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"not a container", "/services/" + all[0].ID + "/inspect", http.StatusBadRequest},
+		{"unknown service", "/services/missing/inspect", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestServicesHandler_BackendUnavailable(t *testing.T) {
+	services.RegisterBackend("broken", func(_ *config.Config, _ []config.ServiceConfig) (services.StatusBackend, error) {
+		return nil, assert.AnError
+	}, nil)
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{
+			{Name: "Broken", Backend: "broken", ContainerName: "broken", AllowActions: true},
+		},
+	}
+	manager, err := services.NewManager(cfg)
+	require.NoError(t, err)
+	defer manager.Stop()
+
+	handler := NewServicesHandler(manager, nil, nil)
+	router := setupTestRouter(cfg)
+	router.GET("/services/:id/inspect", handler.Inspect)
+
+	all := manager.GetAll(context.Background())
+	require.Len(t, all, 1)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/services/"+all[0].ID+"/inspect", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "broken backend is unavailable", response["error"])
+}
+
+func TestServicesHandler_ContainerNotFound(t *testing.T) {
+	// A daemon without any containers
+	daemon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_ping"):
+			w.Header().Set("Api-Version", "1.45")
+			_, _ = w.Write([]byte("OK"))
+		case strings.HasSuffix(r.URL.Path, "/containers/json"):
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer daemon.Close()
+
+	cfg := &config.Config{
+		DockerHosts: map[string]config.DockerHostConfig{
+			"nas": {Host: "tcp://" + strings.TrimPrefix(daemon.URL, "http://")},
+		},
+		Services: []config.ServiceConfig{
+			{Name: "Gone", Backend: "docker", ContainerName: "gone", DockerHost: "nas", AllowActions: true},
+		},
+	}
+	manager, err := services.NewManager(cfg)
+	require.NoError(t, err)
+	defer manager.Stop()
+
+	auditLog, err := audit.New(filepath.Join(t.TempDir(), "audit.log"))
+	require.NoError(t, err)
+	defer auditLog.Close()
+
+	handler := NewServicesHandler(manager, nil, auditLog)
+	router := setupTestRouter(cfg)
+	router.POST("/services/:id/:action", handler.Action)
+	router.GET("/services/:id/inspect", handler.Inspect)
+	router.GET("/services/:id/logs", handler.Logs)
+
+	all := manager.GetAll(context.Background())
+	require.Len(t, all, 1)
+	id := all[0].ID
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("POST", "/services/"+id+"/restart", nil),
+		httptest.NewRequest("GET", "/services/"+id+"/inspect", nil),
+		httptest.NewRequest("GET", "/services/"+id+"/logs", nil),
+	} {
+		t.Run(req.Method+" "+req.URL.Path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestServicesHandler_History(t *testing.T) {
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{
+			{Name: "Website", Backend: "http", URL: "http://127.0.0.1", Port: 1},
+		},
+		History: config.HistoryConfig{Path: filepath.Join(t.TempDir(), "history.db")},
+	}
+	manager, err := services.NewManager(cfg)
+	require.NoError(t, err)
+	defer manager.Stop()
+
+	handler := NewServicesHandler(manager, nil, nil)
+	router := setupTestRouter(cfg)
+	router.GET("/services/:id/history", handler.History)
+
+	all := manager.GetAll(context.Background())
+	require.Len(t, all, 1)
+	websiteID := all[0].ID
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"defaults", "/services/" + websiteID + "/history", http.StatusOK},
+		{"rollups", "/services/" + websiteID + "/history?metric=status&range=7d&step=1h", http.StatusOK},
+		{"unknown metric", "/services/" + websiteID + "/history?metric=disk", http.StatusBadRequest},
+		{"bad range", "/services/" + websiteID + "/history?range=forever", http.StatusBadRequest},
+		{"too many points", "/services/" + websiteID + "/history?range=24h&step=1s", http.StatusBadRequest},
+		{"unknown service", "/services/missing/history", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/services/"+websiteID+"/history", nil))
+	var series struct {
+		Metric string        `json:"metric"`
+		Step   string        `json:"step"`
+		Points []interface{} `json:"points"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &series))
+	assert.Equal(t, "cpu", series.Metric)
+	assert.Equal(t, "1m0s", series.Step)
+	assert.Len(t, series.Points, 60)
+}
+
+func TestServicesHandler_History_Disabled(t *testing.T) {
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{
+			{Name: "Website", Backend: "http", URL: "http://127.0.0.1", Port: 1},
+		},
+	}
+	manager, err := services.NewManager(cfg)
+	require.NoError(t, err)
+
+	handler := NewServicesHandler(manager, nil, nil)
+	router := setupTestRouter(cfg)
+	router.GET("/services/:id/history", handler.History)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/services/website/history", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
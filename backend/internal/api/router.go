@@ -2,6 +2,7 @@ package api
 
 import (
 	"home-run-backend/internal/api/handlers"
+	"home-run-backend/internal/audit"
 	"home-run-backend/internal/auth"
 	"home-run-backend/internal/config"
 	"home-run-backend/internal/services"
@@ -14,7 +15,7 @@ import (
 )
 
 // SetupRouter creates and configures the Gin router
-func SetupRouter(cfg *config.Config, manager *services.Manager, aggregator *federation.Aggregator) *gin.Engine {
+func SetupRouter(cfg *config.Config, manager *services.Manager, aggregator *federation.Aggregator, auditLog *audit.Log) *gin.Engine {
 	r := gin.Default()
 
 	// CORS configuration
@@ -42,9 +43,13 @@ func SetupRouter(cfg *config.Config, manager *services.Manager, aggregator *fede
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(cfg)
-	servicesHandler := handlers.NewServicesHandler(manager, aggregator)
-	hostHandler := handlers.NewHostHandler()
+	servicesHandler := handlers.NewServicesHandler(manager, aggregator, auditLog)
+	hostHandler := handlers.NewHostHandler(manager)
 	federationHandler := handlers.NewFederationHandler(aggregator)
+	certificatesHandler := handlers.NewCertificatesHandler(manager)
+	dockerHandler := handlers.NewDockerHandler(manager, auditLog)
+	healthHandler := handlers.NewHealthHandler(manager)
+	kumaHandler := handlers.NewKumaHandler(manager, auditLog)
 
 	// Health check (public)
 	r.GET("/health", func(c *gin.Context) {
@@ -69,9 +74,31 @@ func SetupRouter(cfg *config.Config, manager *services.Manager, aggregator *fede
 			protected.GET("/services", servicesHandler.List)
 			protected.GET("/services/:id", servicesHandler.Get)
 			protected.GET("/services/:id/configs/:index", servicesHandler.GetConfig)
+			protected.GET("/services/:id/logs", servicesHandler.Logs)
+			protected.GET("/services/:id/inspect", servicesHandler.Inspect)
+			protected.GET("/services/:id/history", servicesHandler.History)
+			protected.POST("/services/:id/:action", servicesHandler.Action) // start, stop, restart, pause, unpause
 
 			// Host stats
 			protected.GET("/host/stats", hostHandler.Stats)
+			protected.GET("/host/history", hostHandler.History)
+
+			// TLS certificates
+			protected.GET("/certificates", certificatesHandler.List)
+
+			// Backend connectivity
+			protected.GET("/health/backends", healthHandler.Backends)
+
+			// Auto-heal history
+			protected.GET("/docker/autoheal", dockerHandler.AutoHeal)
+
+			// Docker disk usage
+			protected.GET("/docker/disk", dockerHandler.Disk)
+			protected.POST("/docker/disk/prune", dockerHandler.Prune) // requires server.allow_prune
+
+			// Uptime Kuma monitor import
+			protected.GET("/kuma/monitors", kumaHandler.Monitors)
+			protected.POST("/kuma/sync", kumaHandler.Sync) // rewrites config.yml unless dryRun, requires server.allow_kuma_sync
 		}
 
 		// Federation endpoint (token-based)
@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"home-run-backend/internal/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Entry is a single audited action
+type Entry struct {
+	Time       time.Time `json:"time"`
+	User       string    `json:"user"`
+	RemoteAddr string    `json:"remoteAddr"`
+	Action     string    `json:"action"`
+	ServiceID  string    `json:"serviceId,omitempty"`
+	Target     string    `json:"target,omitempty"` // what a non-service action applied to
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Log records audited actions to the application log and, if configured,
+// appends them as JSON lines to a file
+type Log struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// New opens the audit log. With an empty path entries only go to the application log.
+func New(path string) (*Log, error) {
+	l := &Log{}
+	if path == "" {
+		return l, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	l.file = file
+	return l, nil
+}
+
+// Record writes an entry, stamping it with the current time if unset
+func (l *Log) Record(entry Entry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	logger.WithFields(logrus.Fields{
+		"audit":      true,
+		"user":       entry.User,
+		"remote":     entry.RemoteAddr,
+		"action":     entry.Action,
+		"service_id": entry.ServiceID,
+		"target":     entry.Target,
+		"success":    entry.Success,
+		"error":      entry.Error,
+	}).Info("Audit")
+
+	if l.file == nil {
+		return
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		logger.WithField("error", err.Error()).Error("Failed to write audit log")
+	}
+}
+
+// Close closes the audit log file
+func (l *Log) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
@@ -0,0 +1,18 @@
+package backoff
+
+import "time"
+
+// Exponential is a retry delay that doubles from Min up to Max
+type Exponential struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// Delay returns how long to wait after the given number of failures in a row
+func (e Exponential) Delay(attempts int) time.Duration {
+	delay := e.Min
+	for i := 1; i < attempts && delay < e.Max; i++ {
+		delay *= 2
+	}
+	return min(delay, e.Max)
+}
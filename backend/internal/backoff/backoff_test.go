@@ -0,0 +1,28 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExponential_Delay(t *testing.T) {
+	b := Exponential{Min: time.Second, Max: time.Minute}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Second},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{6, 32 * time.Second},
+		{7, time.Minute},
+		{100, time.Minute},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, b.Delay(tt.attempts), "attempts=%d", tt.attempts)
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BackendValidator checks the backend-specific fields of a service entry.
+// It receives the whole config so it can check global sections the backend depends on.
+type BackendValidator func(cfg *Config, index int, svc ServiceConfig) error
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]BackendValidator{}
+)
+
+// RegisterBackend makes a backend type valid in services[].backend. Backends
+// are registered along with their factory by services.RegisterBackend.
+// The validator may be nil if the backend has no required fields.
+func RegisterBackend(name string, validator BackendValidator) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	backends[name] = validator
+}
+
+// Backends returns the names of all registered backend types
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateBackend dispatches a service entry to its backend validator
+func validateBackend(cfg *Config, index int, svc ServiceConfig) error {
+	backendsMu.RLock()
+	validator, ok := backends[svc.Backend]
+	backendsMu.RUnlock()
+
+	if !ok {
+		var quoted []string
+		for _, name := range Backends() {
+			quoted = append(quoted, "'"+name+"'")
+		}
+		return fmt.Errorf("services[%d].backend must be one of %s, got '%s'", index, strings.Join(quoted, ", "), svc.Backend)
+	}
+	if validator == nil {
+		return nil
+	}
+	return validator(cfg, index, svc)
+}
@@ -1,12 +1,22 @@
 package config
 
+import "time"
+
 // Config represents the application configuration
 type Config struct {
-	Server      ServerConfig      `yaml:"server"`
-	Auth        AuthConfig        `yaml:"auth"`
-	UptimeKuma  *UptimeKumaConfig `yaml:"uptime_kuma,omitempty"`
-	Services    []ServiceConfig   `yaml:"services"`
-	RemoteHosts []RemoteHost      `yaml:"remote_hosts,omitempty"`
+	Server          ServerConfig                `yaml:"server"`
+	Auth            AuthConfig                  `yaml:"auth"`
+	UptimeKuma      *UptimeKumaConfig           `yaml:"uptime_kuma,omitempty"`
+	Podman          *PodmanConfig               `yaml:"podman,omitempty"`
+	DockerHosts     map[string]DockerHostConfig `yaml:"docker_hosts,omitempty"`
+	DockerDiscovery DockerDiscoveryConfig       `yaml:"docker_discovery,omitempty"`
+	Services        []ServiceConfig             `yaml:"services"`
+	RemoteHosts     []RemoteHost                `yaml:"remote_hosts,omitempty"`
+	Certificates    CertificatesConfig          `yaml:"certificates,omitempty"`
+	ImageUpdates    ImageUpdatesConfig          `yaml:"image_updates,omitempty"`
+	History         HistoryConfig               `yaml:"history,omitempty"`
+
+	Path string `yaml:"-"` // file the configuration was loaded from
 }
 
 // ServerConfig contains server settings
@@ -14,6 +24,9 @@ type ServerConfig struct {
 	Port            int    `yaml:"port"`
 	SessionSecret   string `yaml:"session_secret"`
 	CORSAllowOrigin string `yaml:"cors_allow_origin"`
+	AuditLog        string `yaml:"audit_log,omitempty"`       // JSON lines file for container actions
+	AllowPrune      bool   `yaml:"allow_prune,omitempty"`     // allow pruning unused docker data from the dashboard
+	AllowKumaSync   bool   `yaml:"allow_kuma_sync,omitempty"` // allow writing Uptime Kuma monitors to the config file from the dashboard
 }
 
 // AuthConfig contains authentication settings
@@ -25,21 +38,124 @@ type AuthConfig struct {
 
 // UptimeKumaConfig contains Uptime Kuma integration settings
 type UptimeKumaConfig struct {
-	URL      string `yaml:"url"`
+	URL      string        `yaml:"url"`
+	Username string        `yaml:"username,omitempty"` // also logs in to the Socket.IO API for uptime data
+	Password string        `yaml:"password,omitempty"`
+	APIKey   string        `yaml:"api_key,omitempty"`  // /metrics only
+	Interval time.Duration `yaml:"interval,omitempty"` // between /metrics scrapes, default 30s
+}
+
+// DockerHostConfig defines a remote Docker daemon
+type DockerHostConfig struct {
+	Host      string `yaml:"host"` // unix:///path, tcp://host:2376 or ssh://user@host
+	TLSCACert string `yaml:"tls_ca_cert,omitempty"`
+	TLSCert   string `yaml:"tls_cert,omitempty"`
+	TLSKey    string `yaml:"tls_key,omitempty"`
+}
+
+// DockerDiscoveryConfig controls discovery of containers labelled home-run.enable=true.
+// When enabled, the local daemon and every docker_hosts entry are searched.
+type DockerDiscoveryConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// ImageUpdatesConfig controls checking registries for newer images of the
+// containers behind docker services. Containers are never updated.
+type ImageUpdatesConfig struct {
+	Enabled    bool                      `yaml:"enabled"`
+	Interval   time.Duration             `yaml:"interval"`             // how often registries are queried
+	Registries map[string]RegistryConfig `yaml:"registries,omitempty"` // keyed by registry host, e.g. ghcr.io or docker.io
+}
+
+// HistoryConfig controls the on-disk history of service status, CPU, memory
+// and latency. Samples are kept for 24h, then as 5-minute rollups for 30 days.
+type HistoryConfig struct {
+	Path     string        `yaml:"path,omitempty"`     // database file, history is off without one
+	Interval time.Duration `yaml:"interval,omitempty"` // between samples, default 30s
+}
+
+// RegistryConfig holds credentials for a container registry
+type RegistryConfig struct {
 	Username string `yaml:"username,omitempty"`
-	Password string `yaml:"password,omitempty"`
-	APIKey   string `yaml:"api_key,omitempty"`
+	Password string `yaml:"password,omitempty"` // password or access token
+	Insecure bool   `yaml:"insecure,omitempty"` // plain http, for local registries
+}
+
+// PodmanConfig contains Podman integration settings
+type PodmanConfig struct {
+	Socket string `yaml:"socket,omitempty"` // default: rootless socket under $XDG_RUNTIME_DIR
 }
 
 // ServiceConfig defines a service to monitor
 type ServiceConfig struct {
-	Name          string   `yaml:"name"`
-	URL           string   `yaml:"url"`
-	Port          int      `yaml:"port"`
-	Backend       string   `yaml:"backend"` // docker, uptime_kuma
-	ContainerName string   `yaml:"container_name,omitempty"`
-	KumaMonitorID int      `yaml:"kuma_monitor_id,omitempty"`
-	Configs       []string `yaml:"configs,omitempty"`
+	Name           string   `yaml:"name"`
+	URL            string   `yaml:"url"`
+	Port           int      `yaml:"port"`
+	Backend        string   `yaml:"backend"` // any type registered with services.RegisterBackend
+	ContainerName  string   `yaml:"container_name,omitempty"`
+	DockerHost     string   `yaml:"docker_host,omitempty"`     // key in docker_hosts, default: local daemon
+	ComposeProject string   `yaml:"compose_project,omitempty"` // docker compose project, instead of container_name
+	KumaMonitorID  int      `yaml:"kuma_monitor_id,omitempty"`
+	Unit           string   `yaml:"unit,omitempty"` // systemd unit, e.g. jellyfin.service
+	Pod            string   `yaml:"pod,omitempty"`  // podman pod, instead of container_name
+	Configs        []string `yaml:"configs,omitempty"`
+
+	// Process matching (process backend)
+	Process *ProcessMatchConfig `yaml:"process,omitempty"`
+
+	// Allow start/stop/restart/pause/unpause from the dashboard (docker backend)
+	AllowActions bool `yaml:"allow_actions,omitempty"`
+
+	// Restart the container when it becomes unhealthy or exits unexpectedly (docker backend)
+	AutoHeal *AutoHealConfig `yaml:"auto_heal,omitempty"`
+
+	// Active probe settings (http and tcp backends)
+	Interval time.Duration    `yaml:"interval,omitempty"`
+	Timeout  time.Duration    `yaml:"timeout,omitempty"`
+	HTTP     *HTTPProbeConfig `yaml:"http,omitempty"`
+	TLS      bool             `yaml:"tls,omitempty"` // tcp backend: complete a TLS handshake
+}
+
+// AutoHealConfig limits automatic restarts of a service's container
+type AutoHealConfig struct {
+	MaxRestarts       int           `yaml:"max_restarts,omitempty"`        // per window, default 3
+	Window            time.Duration `yaml:"window,omitempty"`              // default 1h
+	Cooldown          time.Duration `yaml:"cooldown,omitempty"`            // between restarts, default 2m
+	OnlyWhenUnhealthy bool          `yaml:"only_when_unhealthy,omitempty"` // leave containers that exited alone
+}
+
+// HTTPProbeConfig defines the request and assertions of an HTTP probe
+type HTTPProbeConfig struct {
+	Method             string            `yaml:"method,omitempty"`
+	ExpectedStatus     []int             `yaml:"expected_status,omitempty"` // default: any 2xx/3xx
+	BodyContains       string            `yaml:"body_contains,omitempty"`
+	BodyRegex          string            `yaml:"body_regex,omitempty"`
+	Headers            map[string]string `yaml:"headers,omitempty"`
+	InsecureSkipVerify bool              `yaml:"tls_skip_verify,omitempty"`
+}
+
+// ProcessMatchConfig selects the processes of a process-backed service
+type ProcessMatchConfig struct {
+	Name         string `yaml:"name,omitempty"`
+	CmdlineRegex string `yaml:"cmdline_regex,omitempty"`
+	PIDFile      string `yaml:"pidfile,omitempty"`
+}
+
+// DefaultWarningDays is used when certificates.warning_days is not set
+const DefaultWarningDays = 14
+
+// CertificatesConfig controls TLS certificate expiry tracking.
+// Certificates of all services with an https url are tracked, plus any tls_check entries.
+type CertificatesConfig struct {
+	WarningDays *int             `yaml:"warning_days"` // 0 disables expiry warnings
+	Interval    time.Duration    `yaml:"interval"`
+	TLSCheck    []TLSCheckConfig `yaml:"tls_check,omitempty"`
+}
+
+// TLSCheckConfig defines an extra TLS endpoint whose certificate is tracked
+type TLSCheckConfig struct {
+	Name    string `yaml:"name"`
+	Address string `yaml:"address"` // host:port
 }
 
 // RemoteHost defines a remote instance for federation
@@ -3,7 +3,10 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"time"
 
 	"home-run-backend/internal/logger"
 
@@ -33,6 +36,8 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
 
+	cfg.Path = path
+
 	// Apply defaults
 	applyDefaults(&cfg)
 
@@ -65,6 +70,35 @@ func applyDefaults(cfg *Config) {
 	if cfg.Server.CORSAllowOrigin == "" {
 		cfg.Server.CORSAllowOrigin = "*"
 	}
+	if cfg.Certificates.WarningDays == nil {
+		days := DefaultWarningDays
+		cfg.Certificates.WarningDays = &days
+	}
+	if cfg.Certificates.Interval == 0 {
+		cfg.Certificates.Interval = 6 * time.Hour
+	}
+	if cfg.ImageUpdates.Interval == 0 {
+		cfg.ImageUpdates.Interval = 6 * time.Hour
+	}
+	if cfg.History.Interval == 0 {
+		cfg.History.Interval = 30 * time.Second
+	}
+	if cfg.UptimeKuma != nil && cfg.UptimeKuma.Interval == 0 {
+		cfg.UptimeKuma.Interval = 30 * time.Second
+	}
+	for _, svc := range cfg.Services {
+		if heal := svc.AutoHeal; heal != nil {
+			if heal.MaxRestarts == 0 {
+				heal.MaxRestarts = 3
+			}
+			if heal.Window == 0 {
+				heal.Window = time.Hour
+			}
+			if heal.Cooldown == 0 {
+				heal.Cooldown = 2 * time.Minute
+			}
+		}
+	}
 }
 
 func validate(cfg *Config) error {
@@ -84,19 +118,14 @@ func validate(cfg *Config) error {
 		if svc.Name == "" {
 			return fmt.Errorf("services[%d].name is required", i)
 		}
-		if svc.Backend != "docker" && svc.Backend != "uptime_kuma" {
-			return fmt.Errorf("services[%d].backend must be 'docker' or 'uptime_kuma', got '%s'", i, svc.Backend)
+		if err := validateBackend(cfg, i, svc); err != nil {
+			return err
 		}
-		if svc.Backend == "docker" && svc.ContainerName == "" {
-			return fmt.Errorf("services[%d].container_name is required for docker backend", i)
+		if svc.AllowActions && svc.Backend != "docker" {
+			return fmt.Errorf("services[%d].allow_actions is only supported by the docker backend", i)
 		}
-		if svc.Backend == "uptime_kuma" {
-			if cfg.UptimeKuma == nil {
-				return fmt.Errorf("services[%d] uses uptime_kuma backend but uptime_kuma config is missing", i)
-			}
-			if svc.KumaMonitorID == 0 {
-				return fmt.Errorf("services[%d].kuma_monitor_id is required for uptime_kuma backend", i)
-			}
+		if svc.AutoHeal != nil && svc.Backend != "docker" {
+			return fmt.Errorf("services[%d].auto_heal is only supported by the docker backend", i)
 		}
 	}
 
@@ -105,6 +134,30 @@ func validate(cfg *Config) error {
 		return errors.New("uptime_kuma.url is required when uptime_kuma is configured")
 	}
 
+	// Validate Docker hosts
+	for name, host := range cfg.DockerHosts {
+		if err := validateDockerHost(name, host); err != nil {
+			return err
+		}
+	}
+
+	// Validate certificate checks
+	if (cfg.Certificates.WarningDays != nil && *cfg.Certificates.WarningDays < 0) || cfg.Certificates.Interval < 0 {
+		return errors.New("certificates.warning_days and certificates.interval must not be negative")
+	}
+	for i, check := range cfg.Certificates.TLSCheck {
+		if check.Name == "" {
+			return fmt.Errorf("certificates.tls_check[%d].name is required", i)
+		}
+		if _, _, err := net.SplitHostPort(check.Address); err != nil {
+			return fmt.Errorf("certificates.tls_check[%d].address must be host:port, got '%s'", i, check.Address)
+		}
+	}
+
+	if cfg.History.Interval < 0 {
+		return errors.New("history.interval must not be negative")
+	}
+
 	// Validate remote hosts
 	for i, host := range cfg.RemoteHosts {
 		if host.Name == "" {
@@ -120,3 +173,24 @@ func validate(cfg *Config) error {
 
 	return nil
 }
+
+func validateDockerHost(name string, host DockerHostConfig) error {
+	u, err := url.Parse(host.Host)
+	if err != nil || host.Host == "" {
+		return fmt.Errorf("docker_hosts.%s.host is required", name)
+	}
+	switch u.Scheme {
+	case "unix", "tcp", "ssh":
+	default:
+		return fmt.Errorf("docker_hosts.%s.host must be a unix://, tcp:// or ssh:// URL, got '%s'", name, host.Host)
+	}
+
+	hasTLS := host.TLSCACert != "" || host.TLSCert != "" || host.TLSKey != ""
+	if hasTLS && u.Scheme != "tcp" {
+		return fmt.Errorf("docker_hosts.%s TLS certificates are only supported for tcp:// hosts", name)
+	}
+	if (host.TLSCert == "") != (host.TLSKey == "") {
+		return fmt.Errorf("docker_hosts.%s.tls_cert and tls_key must be set together", name)
+	}
+	return nil
+}
@@ -1,14 +1,23 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// The built-in backends register themselves with their validators from the
+// services package. These tests only need their names.
+func init() {
+	RegisterBackend("docker", nil)
+	RegisterBackend("http", nil)
+}
+
 func TestLoad_ValidConfig(t *testing.T) {
 	yamlContent := `
 server:
@@ -123,6 +132,134 @@ func TestValidate_InvalidBackend(t *testing.T) {
 	assert.Contains(t, err.Error(), "backend must be")
 }
 
+func TestValidate_RegisteredBackend(t *testing.T) {
+	RegisterBackend("custom", func(_ *Config, index int, svc ServiceConfig) error {
+		if svc.URL == "" {
+			return fmt.Errorf("services[%d].url is required for custom backend", index)
+		}
+		return nil
+	})
+
+	cfg := &Config{
+		Auth: AuthConfig{
+			Username: "admin",
+			Password: "password",
+			APIToken: "token",
+		},
+		Services: []ServiceConfig{
+			{
+				Name:    "Test",
+				Backend: "custom",
+			},
+		},
+	}
+
+	err := validate(cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "url is required for custom backend")
+
+	cfg.Services[0].URL = "http://localhost"
+	assert.NoError(t, validate(cfg))
+	assert.Contains(t, Backends(), "custom")
+}
+
+func TestValidate_DockerHosts(t *testing.T) {
+	tests := []struct {
+		name  string
+		hosts map[string]DockerHostConfig
+		ref   string
+		err   string
+	}{
+		{"local daemon", nil, "", ""},
+		{"unix socket", map[string]DockerHostConfig{"nas": {Host: "unix:///mnt/nas/docker.sock"}}, "nas", ""},
+		{"tcp with tls", map[string]DockerHostConfig{"vm": {Host: "tcp://vm:2376", TLSCACert: "ca.pem", TLSCert: "cert.pem", TLSKey: "key.pem"}}, "vm", ""},
+		{"ssh", map[string]DockerHostConfig{"vm": {Host: "ssh://admin@vm"}}, "vm", ""},
+		{"missing host", map[string]DockerHostConfig{"nas": {}}, "nas", "host is required"},
+		{"bad scheme", map[string]DockerHostConfig{"nas": {Host: "http://nas"}}, "nas", "must be a unix://, tcp:// or ssh:// URL"},
+		{"tls on ssh", map[string]DockerHostConfig{"vm": {Host: "ssh://vm", TLSCACert: "ca.pem"}}, "vm", "only supported for tcp://"},
+		{"cert without key", map[string]DockerHostConfig{"vm": {Host: "tcp://vm:2376", TLSCert: "cert.pem"}}, "vm", "must be set together"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Auth:        AuthConfig{Username: "admin", Password: "password", APIToken: "token"},
+				DockerHosts: tt.hosts,
+				Services: []ServiceConfig{
+					{Name: "Plex", Backend: "docker", ContainerName: "plex", DockerHost: tt.ref},
+				},
+			}
+			err := validate(cfg)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidate_AllowActions(t *testing.T) {
+	tests := []struct {
+		name string
+		svc  ServiceConfig
+		err  string
+	}{
+		{"docker", ServiceConfig{Name: "Plex", Backend: "docker", ContainerName: "plex", AllowActions: true}, ""},
+		{"http", ServiceConfig{Name: "Site", Backend: "http", URL: "https://example.com", AllowActions: true}, "only supported by the docker backend"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Auth:     AuthConfig{Username: "admin", Password: "password", APIToken: "token"},
+				Services: []ServiceConfig{tt.svc},
+			}
+			err := validate(cfg)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestLoad_HTTPProbeConfig(t *testing.T) {
+	yamlContent := `
+auth:
+  username: "admin"
+  password: "password"
+  api_token: "test-token"
+
+services:
+  - name: "Website"
+    backend: "http"
+    url: "https://example.com/health"
+    interval: 15s
+    timeout: 3s
+    http:
+      method: HEAD
+      expected_status: [200, 204]
+      headers:
+        Authorization: "Bearer abc"
+`
+
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(yamlContent), 0644))
+
+	cfg, err := Load(configPath)
+	require.NoError(t, err)
+
+	svc := cfg.Services[0]
+	assert.Equal(t, 15*time.Second, svc.Interval)
+	assert.Equal(t, 3*time.Second, svc.Timeout)
+	require.NotNil(t, svc.HTTP)
+	assert.Equal(t, "HEAD", svc.HTTP.Method)
+	assert.Equal(t, []int{200, 204}, svc.HTTP.ExpectedStatus)
+	assert.Equal(t, "Bearer abc", svc.HTTP.Headers["Authorization"])
+}
+
 func TestApplyDefaults(t *testing.T) {
 	cfg := &Config{}
 	applyDefaults(cfg)
@@ -130,4 +267,62 @@ func TestApplyDefaults(t *testing.T) {
 	assert.Equal(t, 8080, cfg.Server.Port)
 	assert.NotEmpty(t, cfg.Server.SessionSecret)
 	assert.Equal(t, "*", cfg.Server.CORSAllowOrigin)
+	assert.Equal(t, 6*time.Hour, cfg.ImageUpdates.Interval)
+	require.NotNil(t, cfg.Certificates.WarningDays)
+	assert.Equal(t, DefaultWarningDays, *cfg.Certificates.WarningDays)
+	assert.Equal(t, 30*time.Second, cfg.History.Interval)
+	assert.Empty(t, cfg.History.Path)
+	assert.Nil(t, cfg.UptimeKuma)
+
+	cfg = &Config{UptimeKuma: &UptimeKumaConfig{URL: "http://kuma:3001"}}
+	applyDefaults(cfg)
+	assert.Equal(t, 30*time.Second, cfg.UptimeKuma.Interval)
+
+	// warning_days: 0 disables expiry warnings instead of falling back to the default
+	disabled := 0
+	cfg = &Config{Certificates: CertificatesConfig{WarningDays: &disabled}}
+	applyDefaults(cfg)
+	assert.Zero(t, *cfg.Certificates.WarningDays)
+}
+
+func TestApplyDefaults_AutoHeal(t *testing.T) {
+	cfg := &Config{
+		Services: []ServiceConfig{
+			{Name: "Plex", Backend: "docker", ContainerName: "plex", AutoHeal: &AutoHealConfig{Cooldown: time.Minute}},
+			{Name: "Sonarr", Backend: "docker", ContainerName: "sonarr"},
+		},
+	}
+	applyDefaults(cfg)
+
+	heal := cfg.Services[0].AutoHeal
+	assert.Equal(t, 3, heal.MaxRestarts)
+	assert.Equal(t, time.Hour, heal.Window)
+	assert.Equal(t, time.Minute, heal.Cooldown)
+	assert.Nil(t, cfg.Services[1].AutoHeal)
+}
+
+func TestValidate_AutoHeal(t *testing.T) {
+	tests := []struct {
+		name string
+		svc  ServiceConfig
+		err  string
+	}{
+		{"docker", ServiceConfig{Name: "Plex", Backend: "docker", ContainerName: "plex", AutoHeal: &AutoHealConfig{}}, ""},
+		{"http", ServiceConfig{Name: "Site", Backend: "http", URL: "https://example.com", AutoHeal: &AutoHealConfig{}}, "only supported by the docker backend"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Auth:     AuthConfig{Username: "admin", Password: "password", APIToken: "token"},
+				Services: []ServiceConfig{tt.svc},
+			}
+			err := validate(cfg)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
 }
@@ -0,0 +1,222 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Metric is a value tracked over time
+type Metric string
+
+// Metrics
+const (
+	MetricCPU     Metric = "cpu"     // percent
+	MetricMemory  Metric = "memory"  // MB
+	MetricLatency Metric = "latency" // ms
+	MetricStatus  Metric = "status"  // percent of samples RUNNING
+)
+
+// maxPoints bounds the points of one query
+const maxPoints = 1000
+
+// ErrInvalidQuery is returned for unknown metrics and unusable ranges or steps
+var ErrInvalidQuery = errors.New("invalid history query")
+
+// Unit returns the unit of a metric's values
+func (m Metric) Unit() string {
+	switch m {
+	case MetricCPU, MetricStatus:
+		return "%"
+	case MetricMemory:
+		return "MB"
+	case MetricLatency:
+		return "ms"
+	}
+	return ""
+}
+
+// ParseMetric validates a metric name
+func ParseMetric(name string) (Metric, error) {
+	switch metric := Metric(name); metric {
+	case MetricCPU, MetricMemory, MetricLatency, MetricStatus:
+		return metric, nil
+	}
+	return "", fmt.Errorf("%w: unknown metric '%s' (cpu, memory, latency or status)", ErrInvalidQuery, name)
+}
+
+// ParseSpan parses a range or step: a Go duration such as 90m, or a number
+// of days such as 7d
+func ParseSpan(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	} else if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("%w: '%s' is not a duration like 15m, 24h or 7d", ErrInvalidQuery, value)
+}
+
+// Point is the average of a metric over one step. Value is nil if nothing
+// was recorded in the step.
+type Point struct {
+	Time  time.Time `json:"time"`
+	Value *float64  `json:"value"`
+}
+
+// Series is the history of one metric of one service
+type Series struct {
+	Metric Metric    `json:"metric"`
+	Unit   string    `json:"unit"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Step   string    `json:"step"`
+	Source string    `json:"source"` // raw, rollup_5m or live
+	Points []Point   `json:"points"`
+}
+
+// Query averages a metric of a service into steps over [to-span, to). Spans
+// within RawRetention are served from raw samples, longer ones from rollups,
+// in which case step is at least RollupInterval.
+func (s *Store) Query(serviceID string, metric Metric, span, step time.Duration, to time.Time) (*Series, error) {
+	if span > RollupRetention {
+		return nil, fmt.Errorf("%w: range must be between 0 and %s", ErrInvalidQuery, RollupRetention)
+	}
+	useRaw := span <= RawRetention
+	if !useRaw && step < RollupInterval {
+		step = RollupInterval
+	}
+	b, err := newSeriesBuilder(metric, span, step, to)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.View(func(tx *bolt.Tx) error {
+		if useRaw {
+			b.series.Source = string(rawBucket)
+			return walk(tx, rawBucket, serviceID, b.series.From, to, func(at time.Time, value []byte) error {
+				var record rawRecord
+				if err := json.Unmarshal(value, &record); err != nil {
+					return err
+				}
+				sum, count := record.value(metric)
+				b.add(at, sum, count)
+				return nil
+			})
+		}
+
+		b.series.Source = string(rollupBucket)
+		return walk(tx, rollupBucket, serviceID, b.series.From, to, func(at time.Time, value []byte) error {
+			var r rollup
+			if err := json.Unmarshal(value, &r); err != nil {
+				return err
+			}
+			sum, count := r.value(metric)
+			b.add(at, sum, count)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return b.finish(), nil
+}
+
+// seriesBuilder averages values into the steps of a series
+type seriesBuilder struct {
+	series *Series
+	step   time.Duration
+	sums   []float64
+	counts []float64
+}
+
+// newSeriesBuilder starts a series of a metric over [to-span, to)
+func newSeriesBuilder(metric Metric, span, step time.Duration, to time.Time) (*seriesBuilder, error) {
+	if span <= 0 {
+		return nil, fmt.Errorf("%w: range must be positive", ErrInvalidQuery)
+	}
+	if step <= 0 {
+		return nil, fmt.Errorf("%w: step must be positive", ErrInvalidQuery)
+	}
+	n := int((span + step - 1) / step)
+	if n > maxPoints {
+		return nil, fmt.Errorf("%w: %s in steps of %s is more than %d points", ErrInvalidQuery, span, step, maxPoints)
+	}
+
+	return &seriesBuilder{
+		series: &Series{
+			Metric: metric,
+			Unit:   metric.Unit(),
+			From:   to.Add(-span),
+			To:     to,
+			Step:   step.String(),
+			Points: make([]Point, n),
+		},
+		step:   step,
+		sums:   make([]float64, n),
+		counts: make([]float64, n),
+	}, nil
+}
+
+// add adds sum over count values at a time to its step
+func (b *seriesBuilder) add(at time.Time, sum, count float64) {
+	i := int(at.Sub(b.series.From) / b.step)
+	if i >= 0 && i < len(b.sums) && count > 0 {
+		b.sums[i] += sum
+		b.counts[i] += count
+	}
+}
+
+// finish averages every step
+func (b *seriesBuilder) finish() *Series {
+	for i := range b.series.Points {
+		b.series.Points[i].Time = b.series.From.Add(time.Duration(i) * b.step)
+		if b.counts[i] > 0 {
+			avg := b.sums[i] / b.counts[i]
+			b.series.Points[i].Value = &avg
+		}
+	}
+	return b.series
+}
+
+// value returns a sample's contribution to a metric as sum and count
+func (r rawRecord) value(metric Metric) (float64, float64) {
+	switch metric {
+	case MetricCPU:
+		return r.CPU, 1
+	case MetricMemory:
+		return r.Memory, 1
+	case MetricLatency:
+		if r.Latency > 0 {
+			return r.Latency, 1
+		}
+	case MetricStatus:
+		if r.Status == "RUNNING" {
+			return 100, 1
+		}
+		return 0, 1
+	}
+	return 0, 0
+}
+
+// value returns a rollup's contribution to a metric as sum and count
+func (r rollup) value(metric Metric) (float64, float64) {
+	switch metric {
+	case MetricCPU:
+		return r.CPU, float64(r.Count)
+	case MetricMemory:
+		return r.Memory, float64(r.Count)
+	case MetricLatency:
+		return r.Latency, float64(r.LatencyCount)
+	case MetricStatus:
+		return 100 * float64(r.Running), float64(r.Count)
+	}
+	return 0, 0
+}
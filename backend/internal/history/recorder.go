@@ -0,0 +1,88 @@
+package history
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"home-run-backend/internal/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Recorder samples every service into a store once per interval
+type Recorder struct {
+	store    *Store
+	interval time.Duration
+	source   func(ctx context.Context) []Sample
+	mu       sync.Mutex
+	running  bool
+	stopCh   chan struct{}
+}
+
+// NewRecorder creates a recorder that stores what source returns every interval
+func NewRecorder(store *Store, interval time.Duration, source func(ctx context.Context) []Sample) *Recorder {
+	return &Recorder{
+		store:    store,
+		interval: interval,
+		source:   source,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start starts recording in the background
+func (r *Recorder) Start(ctx context.Context) {
+	r.mu.Lock()
+	if r.running {
+		r.mu.Unlock()
+		return
+	}
+	r.running = true
+	r.mu.Unlock()
+
+	logger.WithField("interval", r.interval).Info("Starting history recorder")
+
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.stopCh:
+				return
+			case <-ticker.C:
+				r.record(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops recording
+func (r *Recorder) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running {
+		close(r.stopCh)
+		r.running = false
+	}
+}
+
+// record stores one sample of every service
+func (r *Recorder) record(ctx context.Context) {
+	now := time.Now()
+	samples := r.source(ctx)
+	for i := range samples {
+		samples[i].Time = now
+	}
+
+	if err := r.store.Record(samples, now); err != nil {
+		logger.WithFields(logrus.Fields{
+			"samples": len(samples),
+			"error":   err.Error(),
+		}).Error("Failed to record history")
+		return
+	}
+	logger.WithField("samples", len(samples)).Debug("Recorded history")
+}
@@ -0,0 +1,62 @@
+package history
+
+import (
+	"sync"
+	"time"
+)
+
+// Ring keeps the most recent samples of one source in memory
+type Ring struct {
+	mu      sync.RWMutex
+	samples []Sample
+	next    int
+	full    bool
+}
+
+// NewRing creates a ring that holds up to size samples
+func NewRing(size int) *Ring {
+	return &Ring{samples: make([]Sample, size)}
+}
+
+// Add adds a sample, replacing the oldest one once the ring is full
+func (r *Ring) Add(sample Sample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples[r.next] = sample
+	r.next = (r.next + 1) % len(r.samples)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Since returns the samples taken at or after from, oldest first
+func (r *Ring) Since(from time.Time) []Sample {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ordered := r.samples[:r.next]
+	if r.full {
+		ordered = append(append([]Sample(nil), r.samples[r.next:]...), r.samples[:r.next]...)
+	}
+	var result []Sample
+	for _, sample := range ordered {
+		if !sample.Time.Before(from) {
+			result = append(result, sample)
+		}
+	}
+	return result
+}
+
+// Query averages a metric of the samples into steps over [to-span, to)
+func (r *Ring) Query(metric Metric, span, step time.Duration, to time.Time) (*Series, error) {
+	b, err := newSeriesBuilder(metric, span, step, to)
+	if err != nil {
+		return nil, err
+	}
+	b.series.Source = "live"
+	for _, sample := range r.Since(b.series.From) {
+		sum, count := sample.record().value(metric)
+		b.add(sample.Time, sum, count)
+	}
+	return b.finish(), nil
+}
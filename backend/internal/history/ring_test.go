@@ -0,0 +1,35 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRing(t *testing.T) {
+	ring := NewRing(3)
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	assert.Empty(t, ring.Since(start))
+
+	// Five samples, one per second: the first two are overwritten
+	for i := 0; i < 5; i++ {
+		ring.Add(Sample{Time: start.Add(time.Duration(i) * time.Second), CPU: float64(i)})
+	}
+
+	var cpu []float64
+	for _, sample := range ring.Since(start) {
+		cpu = append(cpu, sample.CPU)
+	}
+	assert.Equal(t, []float64{2, 3, 4}, cpu)
+	assert.Len(t, ring.Since(start.Add(4*time.Second)), 1)
+
+	series, err := ring.Query(MetricCPU, 6*time.Second, 2*time.Second, start.Add(5*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, "live", series.Source)
+	assert.Equal(t, []any{nil, 2.0, 3.5}, values(series)) // steps start at -1s
+
+	_, err = ring.Query(MetricCPU, time.Hour, time.Millisecond, start)
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
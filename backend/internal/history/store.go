@@ -0,0 +1,227 @@
+package history
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// RawRetention is how long every sample is kept
+	RawRetention = 24 * time.Hour
+	// RollupInterval is the width of the rollups kept after that
+	RollupInterval = 5 * time.Minute
+	// RollupRetention is how long rollups are kept
+	RollupRetention = 30 * 24 * time.Hour
+)
+
+// HostID is the service ID the host itself is recorded under. Service IDs
+// are hex hashes, so it can't collide with one.
+const HostID = "host"
+
+var (
+	rawBucket    = []byte("raw")
+	rollupBucket = []byte("rollup_5m")
+)
+
+// Sample is the state of a service at one point in time
+type Sample struct {
+	ServiceID string
+	Time      time.Time
+	Status    string  // RUNNING, STOPPED, ERROR, ...
+	CPU       float64 // percent
+	Memory    float64 // MB
+	Latency   float64 // ms, 0 if not measured
+}
+
+// rawRecord is a stored sample, keyed by service and time
+type rawRecord struct {
+	Status  string  `json:"s"`
+	CPU     float64 `json:"c"`
+	Memory  float64 `json:"m"`
+	Latency float64 `json:"l,omitempty"`
+}
+
+// record returns the stored form of a sample
+func (s Sample) record() rawRecord {
+	return rawRecord{
+		Status:  s.Status,
+		CPU:     s.CPU,
+		Memory:  s.Memory,
+		Latency: s.Latency,
+	}
+}
+
+// rollup sums the samples of one RollupInterval
+type rollup struct {
+	Count        int     `json:"n"`
+	Running      int     `json:"r"` // samples with status RUNNING
+	CPU          float64 `json:"c"`
+	Memory       float64 `json:"m"`
+	Latency      float64 `json:"l,omitempty"`
+	LatencyCount int     `json:"ln,omitempty"`
+}
+
+// Store keeps samples of every service in a bbolt database: raw for
+// RawRetention, then as RollupInterval rollups for RollupRetention
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens or creates the database at path
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{rawBucket, rollupBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize history database: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Record stores samples, adds them to their rollups and drops data past its
+// retention relative to now
+func (s *Store) Record(samples []Sample, now time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(rawBucket)
+		rollups := tx.Bucket(rollupBucket)
+
+		for _, sample := range samples {
+			value, err := json.Marshal(sample.record())
+			if err != nil {
+				return err
+			}
+			bucket, err := raw.CreateBucketIfNotExists([]byte(sample.ServiceID))
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(timeKey(sample.Time), value); err != nil {
+				return err
+			}
+
+			if err := addToRollup(rollups, sample); err != nil {
+				return err
+			}
+		}
+
+		if err := prune(raw, now.Add(-RawRetention)); err != nil {
+			return err
+		}
+		return prune(rollups, now.Add(-RollupRetention))
+	})
+}
+
+// addToRollup adds a sample to the rollup of its interval
+func addToRollup(rollups *bolt.Bucket, sample Sample) error {
+	bucket, err := rollups.CreateBucketIfNotExists([]byte(sample.ServiceID))
+	if err != nil {
+		return err
+	}
+
+	key := timeKey(sample.Time.Truncate(RollupInterval))
+	var r rollup
+	if existing := bucket.Get(key); existing != nil {
+		if err := json.Unmarshal(existing, &r); err != nil {
+			return fmt.Errorf("corrupt rollup for %s: %w", sample.ServiceID, err)
+		}
+	}
+
+	r.Count++
+	if sample.Status == "RUNNING" {
+		r.Running++
+	}
+	r.CPU += sample.CPU
+	r.Memory += sample.Memory
+	if sample.Latency > 0 {
+		r.Latency += sample.Latency
+		r.LatencyCount++
+	}
+
+	value, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, value)
+}
+
+// prune deletes entries before cutoff from every service bucket in parent,
+// and services left without entries
+func prune(parent *bolt.Bucket, cutoff time.Time) error {
+	var empty [][]byte
+	err := parent.ForEachBucket(func(name []byte) error {
+		bucket := parent.Bucket(name)
+		limit := timeKey(cutoff)
+		c := bucket.Cursor()
+		// Deleting moves the cursor, so start over from the oldest entry each time
+		k, _ := c.First()
+		for ; k != nil && bytes.Compare(k, limit) < 0; k, _ = c.First() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+		if k == nil {
+			empty = append(empty, append([]byte(nil), name...))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range empty {
+		if err := parent.DeleteBucket(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// timeKey encodes a time so keys sort chronologically
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	return key
+}
+
+func keyTime(key []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(key)))
+}
+
+// walk calls fn with every entry of a service's bucket in [from, to)
+func walk(tx *bolt.Tx, parent []byte, serviceID string, from, to time.Time, fn func(time.Time, []byte) error) error {
+	bucket := tx.Bucket(parent).Bucket([]byte(serviceID))
+	if bucket == nil {
+		return nil
+	}
+	c := bucket.Cursor()
+	limit := to.UnixNano()
+	for k, v := c.Seek(timeKey(from)); k != nil; k, v = c.Next() {
+		at := keyTime(k)
+		if at.UnixNano() >= limit {
+			return nil
+		}
+		if err := fn(at, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+func openTestStore(t *testing.T) *Store {
+	store, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func values(series *Series) []any {
+	result := make([]any, len(series.Points))
+	for i, point := range series.Points {
+		if point.Value != nil {
+			result[i] = *point.Value
+		}
+	}
+	return result
+}
+
+func TestStore_QueryRaw(t *testing.T) {
+	store := openTestStore(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	var samples []Sample
+	for i, cpu := range []float64{10, 20, 30, 40} {
+		status := "RUNNING"
+		if i == 3 {
+			status = "STOPPED"
+		}
+		samples = append(samples, Sample{
+			ServiceID: "svc",
+			Time:      now.Add(time.Duration(i-4) * 30 * time.Second), // 12:58:00 to 12:59:30
+			Status:    status,
+			CPU:       cpu,
+			Memory:    100 * cpu,
+			Latency:   cpu / 10,
+		})
+	}
+	samples = append(samples, Sample{ServiceID: "other", Time: now.Add(-time.Minute), CPU: 99})
+	require.NoError(t, store.Record(samples, now))
+
+	series, err := store.Query("svc", MetricCPU, 3*time.Minute, time.Minute, now)
+	require.NoError(t, err)
+	assert.Equal(t, "raw", series.Source)
+	assert.Equal(t, "%", series.Unit)
+	assert.Equal(t, now.Add(-3*time.Minute), series.Points[0].Time)
+	assert.Equal(t, []any{nil, 15.0, 35.0}, values(series))
+
+	series, err = store.Query("svc", MetricStatus, 3*time.Minute, time.Minute, now)
+	require.NoError(t, err)
+	assert.Equal(t, []any{nil, 100.0, 50.0}, values(series))
+
+	series, err = store.Query("svc", MetricMemory, 2*time.Minute, 2*time.Minute, now)
+	require.NoError(t, err)
+	assert.Equal(t, []any{2500.0}, values(series))
+
+	series, err = store.Query("missing", MetricCPU, time.Hour, 30*time.Minute, now)
+	require.NoError(t, err)
+	assert.Equal(t, []any{nil, nil}, values(series))
+}
+
+func TestStore_QueryRollups(t *testing.T) {
+	store := openTestStore(t)
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	// Ten minutes of samples: running with cpu 0-4, then failing with cpu
+	// 5-9; latency on every other sample
+	for i := 0; i < 10; i++ {
+		at := start.Add(time.Duration(i) * time.Minute)
+		sample := Sample{ServiceID: "svc", Time: at, Status: "RUNNING", CPU: float64(i), Memory: 512}
+		if i >= 5 {
+			sample.Status = "ERROR"
+		}
+		if i%2 == 0 {
+			sample.Latency = float64(10 * i)
+		}
+		require.NoError(t, store.Record([]Sample{sample}, at))
+	}
+	now := start.Add(48 * time.Hour)
+
+	// Beyond RawRetention the step is raised to RollupInterval
+	series, err := store.Query("svc", MetricCPU, 48*time.Hour, time.Minute, now)
+	require.NoError(t, err)
+	assert.Equal(t, "rollup_5m", series.Source)
+	assert.Equal(t, "5m0s", series.Step)
+	assert.Len(t, series.Points, 576)
+	assert.Equal(t, []any{2.0, 7.0, nil}, values(series)[:3])
+
+	series, err = store.Query("svc", MetricStatus, 48*time.Hour, 5*time.Minute, now)
+	require.NoError(t, err)
+	assert.Equal(t, []any{100.0, 0.0}, values(series)[:2])
+
+	series, err = store.Query("svc", MetricLatency, 48*time.Hour, 10*time.Minute, now)
+	require.NoError(t, err)
+	assert.Equal(t, []any{50.0, nil}, values(series)[:2]) // a latency of 0 is not measured
+}
+
+func TestStore_Retention(t *testing.T) {
+	store := openTestStore(t)
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, store.Record([]Sample{{ServiceID: "gone", Time: start, CPU: 1}}, start))
+	require.NoError(t, store.Record([]Sample{{ServiceID: "svc", Time: start, CPU: 1}}, start))
+
+	// A day later the raw samples are pruned, rollups remain
+	later := start.Add(RawRetention + time.Minute)
+	require.NoError(t, store.Record([]Sample{{ServiceID: "svc", Time: later, CPU: 2}}, later))
+	assert.Equal(t, 1, count(t, store, rawBucket, "svc"))
+	assert.Equal(t, 0, count(t, store, rawBucket, "gone"))
+	assert.Equal(t, 2, count(t, store, rollupBucket, "svc"))
+	assert.Equal(t, 1, count(t, store, rollupBucket, "gone"))
+
+	// After 30 days the first rollups go as well
+	end := start.Add(RollupRetention + time.Minute)
+	require.NoError(t, store.Record([]Sample{{ServiceID: "svc", Time: end, CPU: 3}}, end))
+	assert.Equal(t, 2, count(t, store, rollupBucket, "svc"))
+	assert.Equal(t, 0, count(t, store, rollupBucket, "gone"))
+
+	require.NoError(t, store.db.View(func(tx *bolt.Tx) error {
+		assert.Nil(t, tx.Bucket(rollupBucket).Bucket([]byte("gone")))
+		return nil
+	}))
+}
+
+func count(t *testing.T, store *Store, parent []byte, serviceID string) int {
+	n := 0
+	require.NoError(t, store.db.View(func(tx *bolt.Tx) error {
+		return walk(tx, parent, serviceID, time.Unix(0, 0), time.Unix(1<<32, 0), func(time.Time, []byte) error {
+			n++
+			return nil
+		})
+	}))
+	return n
+}
+
+func TestStore_InvalidQuery(t *testing.T) {
+	store := openTestStore(t)
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		span time.Duration
+		step time.Duration
+	}{
+		{"no range", 0, time.Minute},
+		{"range past retention", 31 * 24 * time.Hour, time.Hour},
+		{"no step", time.Hour, 0},
+		{"too many points", 24 * time.Hour, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := store.Query("svc", MetricCPU, tt.span, tt.step, now)
+			assert.ErrorIs(t, err, ErrInvalidQuery)
+		})
+	}
+}
+
+func TestParseSpan(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"1h", time.Hour, false},
+		{"90s", 90 * time.Second, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"0d", 0, true},
+		{"-5m", 0, true},
+		{"soon", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			span, err := ParseSpan(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidQuery)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, span)
+		})
+	}
+}
+
+func TestParseMetric(t *testing.T) {
+	metric, err := ParseMetric("latency")
+	require.NoError(t, err)
+	assert.Equal(t, MetricLatency, metric)
+	assert.Equal(t, "ms", metric.Unit())
+
+	_, err = ParseMetric("disk")
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
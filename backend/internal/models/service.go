@@ -2,16 +2,80 @@ package models
 
 // Service represents a monitored service
 type Service struct {
-	ID          string          `json:"id"`
-	Name        string          `json:"name"`
-	Status      string          `json:"status"` // RUNNING, STOPPED, ERROR, MAINTENANCE
-	Port        int             `json:"port"`
-	URL         string          `json:"url"`
-	Configs     []ServiceConfig `json:"configs"`
-	Uptime      string          `json:"uptime"`
-	CPUUsage    float64         `json:"cpuUsage"`    // Percent
-	MemoryUsage float64         `json:"memoryUsage"` // MB
-	Host        string          `json:"host,omitempty"`
+	ID                string          `json:"id"`
+	Name              string          `json:"name"`
+	Status            string          `json:"status"` // RUNNING, STOPPED, ERROR, MAINTENANCE, WARNING, UNKNOWN
+	Port              int             `json:"port"`
+	URL               string          `json:"url"`
+	Configs           []ServiceConfig `json:"configs"`
+	Uptime            string          `json:"uptime"`
+	CPUUsage          float64         `json:"cpuUsage"`                    // Percent
+	MemoryUsage       float64         `json:"memoryUsage"`                 // MB
+	MemoryLimit       float64         `json:"memoryLimit,omitempty"`       // MB, containers
+	MemoryPercent     float64         `json:"memoryPercent,omitempty"`     // of the limit, containers
+	NetworkRx         float64         `json:"networkRx,omitempty"`         // bytes/s, containers
+	NetworkTx         float64         `json:"networkTx,omitempty"`         // bytes/s, containers
+	BlockRead         float64         `json:"blockRead,omitempty"`         // bytes/s, containers
+	BlockWrite        float64         `json:"blockWrite,omitempty"`        // bytes/s, containers
+	PIDs              uint64          `json:"pids,omitempty"`              // containers
+	MainPID           uint32          `json:"mainPid,omitempty"`           // systemd units, 0 if not running
+	Latency           float64         `json:"latency,omitempty"`           // ms, for probed services and Kuma monitors
+	ConnectLatency    float64         `json:"connectLatency,omitempty"`    // ms, tcp probes
+	HandshakeLatency  float64         `json:"handshakeLatency,omitempty"`  // ms, tcp probes with tls
+	CertExpiresInDays *int            `json:"certExpiresInDays,omitempty"` // for https services
+	Uptime24h         *float64        `json:"uptime24h,omitempty"`         // percent, Uptime Kuma monitors
+	Uptime30d         *float64        `json:"uptime30d,omitempty"`         // percent, Uptime Kuma monitors
+	AvgLatency        float64         `json:"avgLatency,omitempty"`        // ms over 24h, Uptime Kuma monitors
+	Heartbeats        []Heartbeat     `json:"heartbeats,omitempty"`        // recent Uptime Kuma checks, oldest first
+	AllowActions      bool            `json:"allowActions,omitempty"`      // container actions enabled
+	Health            *Health         `json:"health,omitempty"`            // container healthcheck
+	RestartCount      int             `json:"restartCount,omitempty"`      // automatic container restarts
+	OOMKilled         bool            `json:"oomKilled,omitempty"`         // last exit was an out-of-memory kill
+	Containers        []Container     `json:"containers,omitempty"`        // members of a compose project
+	UpdateAvailable   bool            `json:"updateAvailable,omitempty"`   // registry has a newer image for the tag
+	Host              string          `json:"host,omitempty"`
+}
+
+// Health is the state of a container's healthcheck
+type Health struct {
+	Status        string        `json:"status"` // healthy, unhealthy, starting
+	FailingStreak int           `json:"failingStreak"`
+	Log           []HealthProbe `json:"log,omitempty"` // most recent runs, oldest first
+}
+
+// HealthProbe is the result of one healthcheck run
+type HealthProbe struct {
+	Start    string `json:"start"`
+	ExitCode int    `json:"exitCode"`
+	Output   string `json:"output"`
+}
+
+// Heartbeat is one check of an Uptime Kuma monitor
+type Heartbeat struct {
+	Time    string  `json:"time"` // RFC 3339
+	Status  string  `json:"status"`
+	Latency float64 `json:"latency,omitempty"` // ms
+	Message string  `json:"message,omitempty"`
+}
+
+// Container is one container of a service made of several
+type Container struct {
+	Name    string `json:"name"`
+	Service string `json:"service,omitempty"` // compose service
+	Status  string `json:"status"`
+}
+
+// BackendHealth is whether a backend can reach the daemon it reads status
+// from. Services of an unreachable backend are reported as UNKNOWN.
+type BackendHealth struct {
+	Backend   string `json:"backend"`
+	Name      string `json:"name,omitempty"`    // docker_hosts key, empty for the local daemon
+	Address   string `json:"address,omitempty"` // daemon address
+	Connected bool   `json:"connected"`
+	Error     string `json:"error,omitempty"`
+	Attempts  int    `json:"attempts,omitempty"`  // failed reconnects since it was last reachable
+	Since     string `json:"since,omitempty"`     // when connected last changed
+	LastCheck string `json:"lastCheck,omitempty"` // RFC 3339
 }
 
 // ServiceConfig represents a configuration file for a service
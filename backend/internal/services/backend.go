@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/models"
+)
+
+// StatusBackend populates live status for services of one backend type
+type StatusBackend interface {
+	// Populate fills in status, uptime and resource usage for svc
+	Populate(ctx context.Context, svc *models.Service, cfg config.ServiceConfig)
+}
+
+// BackgroundBackend is implemented by backends that collect data in the background
+type BackgroundBackend interface {
+	Start(ctx context.Context)
+	Stop()
+}
+
+// DiscoveryBackend is implemented by backends that find services at runtime.
+// Discovered services are listed after the configured ones.
+type DiscoveryBackend interface {
+	Discovered() []config.ServiceConfig
+}
+
+// ConfigBackend is implemented by backends that know config files of a
+// service beyond those listed in its configs
+type ConfigBackend interface {
+	ConfigFiles(cfg config.ServiceConfig) []string
+}
+
+// HealthBackend is implemented by backends that depend on daemons which may
+// become unreachable and reconnect
+type HealthBackend interface {
+	Health() []models.BackendHealth
+}
+
+// BackendFactory creates a backend for the services configured with its type.
+// Returning an error disables the backend; its services are reported as ERROR.
+type BackendFactory func(cfg *config.Config, services []config.ServiceConfig) (StatusBackend, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]BackendFactory{}
+)
+
+// RegisterBackend adds a backend type to the registry, replacing any existing
+// factory with the same name, and makes it valid in services[].backend. The
+// validator checks the backend's fields of each service entry at load time;
+// it may be nil if the backend has no required fields.
+func RegisterBackend(name string, factory BackendFactory, validator config.BackendValidator) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = factory
+	config.RegisterBackend(name, validator)
+}
+
+// lookupBackend returns the factory registered for a backend type
+func lookupBackend(name string) (BackendFactory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	factory, ok := registry[name]
+	return factory, ok
+}
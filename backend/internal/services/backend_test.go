@@ -0,0 +1,96 @@
+package services
+
+import (
+	"testing"
+
+	"home-run-backend/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// validatorCase is a service entry and the error its backend validator should return
+type validatorCase struct {
+	name string
+	svc  config.ServiceConfig
+	err  string
+}
+
+func runValidatorCases(t *testing.T, cfg *config.Config, validator config.BackendValidator, tests []validatorCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator(cfg, 0, tt.svc)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidateHTTPService(t *testing.T) {
+	runValidatorCases(t, &config.Config{}, validateHTTPService, []validatorCase{
+		{"valid", config.ServiceConfig{URL: "https://example.com"}, ""},
+		{"missing url", config.ServiceConfig{}, "must be an http(s) URL"},
+		{"wrong scheme", config.ServiceConfig{URL: "tcp://example.com"}, "must be an http(s) URL"},
+		{"bad status", config.ServiceConfig{URL: "http://localhost", HTTP: &config.HTTPProbeConfig{ExpectedStatus: []int{42}}}, "invalid code 42"},
+		{"bad regex", config.ServiceConfig{URL: "http://localhost", HTTP: &config.HTTPProbeConfig{BodyRegex: "("}}, "body_regex is invalid"},
+		{"negative timeout", config.ServiceConfig{URL: "http://localhost", Timeout: -1}, "must not be negative"},
+	})
+}
+
+func TestValidateTCPService(t *testing.T) {
+	runValidatorCases(t, &config.Config{}, validateTCPService, []validatorCase{
+		{"valid", config.ServiceConfig{URL: "http://localhost", Port: 5432}, ""},
+		{"bare host", config.ServiceConfig{URL: "redis.lan", Port: 6379, TLS: true}, ""},
+		{"missing port", config.ServiceConfig{URL: "http://localhost"}, "port must be between"},
+		{"missing host", config.ServiceConfig{Port: 5432}, "must contain a host"},
+	})
+}
+
+func TestValidateDockerService(t *testing.T) {
+	cfg := &config.Config{DockerHosts: map[string]config.DockerHostConfig{"nas": {Host: "ssh://nas"}}}
+
+	runValidatorCases(t, cfg, validateDockerService, []validatorCase{
+		{"container", config.ServiceConfig{ContainerName: "plex", AllowActions: true}, ""},
+		{"project", config.ServiceConfig{ComposeProject: "maps"}, ""},
+		{"neither", config.ServiceConfig{}, "exactly one of container_name or compose_project"},
+		{"both", config.ServiceConfig{ContainerName: "maps-web-1", ComposeProject: "maps"}, "exactly one of container_name or compose_project"},
+		{"project actions", config.ServiceConfig{ComposeProject: "maps", AllowActions: true}, "not supported for compose_project"},
+		{"project auto heal", config.ServiceConfig{ComposeProject: "maps", AutoHeal: &config.AutoHealConfig{}}, "not supported for compose_project"},
+		{"negative auto heal", config.ServiceConfig{ContainerName: "plex", AutoHeal: &config.AutoHealConfig{MaxRestarts: -1}}, "must not be negative"},
+		{"defined docker host", config.ServiceConfig{ContainerName: "plex", DockerHost: "nas"}, ""},
+		{"undefined docker host", config.ServiceConfig{ContainerName: "plex", DockerHost: "vm"}, "is not defined in docker_hosts"},
+	})
+}
+
+func TestValidatePodmanService(t *testing.T) {
+	runValidatorCases(t, &config.Config{}, validatePodmanService, []validatorCase{
+		{"container", config.ServiceConfig{ContainerName: "web"}, ""},
+		{"pod", config.ServiceConfig{Pod: "media"}, ""},
+		{"neither", config.ServiceConfig{}, "exactly one of container_name or pod"},
+	})
+}
+
+func TestValidateKumaService(t *testing.T) {
+	runValidatorCases(t, &config.Config{}, validateKumaService, []validatorCase{
+		{"not configured", config.ServiceConfig{KumaMonitorID: 1}, "uptime_kuma config is missing"},
+	})
+	runValidatorCases(t, &config.Config{UptimeKuma: &config.UptimeKumaConfig{URL: "http://kuma"}}, validateKumaService, []validatorCase{
+		{"valid", config.ServiceConfig{KumaMonitorID: 1}, ""},
+		{"missing monitor", config.ServiceConfig{}, "kuma_monitor_id is required"},
+	})
+}
+
+func TestValidateSystemdAndProcessServices(t *testing.T) {
+	runValidatorCases(t, &config.Config{}, validateSystemdService, []validatorCase{
+		{"unit", config.ServiceConfig{Unit: "nginx.service"}, ""},
+		{"missing unit", config.ServiceConfig{}, "unit is required"},
+	})
+	runValidatorCases(t, &config.Config{}, validateProcessService, []validatorCase{
+		{"name", config.ServiceConfig{Process: &config.ProcessMatchConfig{Name: "nginx"}}, ""},
+		{"no matcher", config.ServiceConfig{Process: &config.ProcessMatchConfig{}}, "needs a name, cmdline_regex or pidfile"},
+		{"bad regex", config.ServiceConfig{Process: &config.ProcessMatchConfig{CmdlineRegex: "("}}, "cmdline_regex is invalid"},
+	})
+}
@@ -0,0 +1,92 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"net"
+	"time"
+)
+
+// Info describes the leaf certificate presented by a TLS endpoint
+type Info struct {
+	Name            string    `json:"name"`
+	Address         string    `json:"address"`
+	ServiceID       string    `json:"serviceId,omitempty"`
+	Subject         string    `json:"subject,omitempty"`
+	Issuer          string    `json:"issuer,omitempty"`
+	DNSNames        []string  `json:"dnsNames,omitempty"`
+	NotBefore       time.Time `json:"notBefore,omitempty"`
+	NotAfter        time.Time `json:"notAfter,omitempty"`
+	DaysUntilExpiry int       `json:"daysUntilExpiry"`
+	Trusted         bool      `json:"trusted"`
+	Status          string    `json:"status"` // VALID, EXPIRING, EXPIRED, ERROR
+	Error           string    `json:"error,omitempty"`
+	CheckedAt       time.Time `json:"checkedAt"`
+}
+
+// Fetch performs a TLS handshake with address and returns its leaf certificate.
+// Untrusted certificates are still returned, with Trusted set to false.
+func Fetch(ctx context.Context, address, serverName string, timeout time.Duration) (*x509.Certificate, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	dialer := tls.Dialer{
+		// Verification is done below so that untrusted certificates can still be inspected
+		Config: &tls.Config{ServerName: serverName, InsecureSkipVerify: true},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, false, fmt.Errorf("tls handshake failed: %w", err)
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return nil, false, fmt.Errorf("no certificate presented by %s", address)
+	}
+
+	leaf := state.PeerCertificates[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, verifyErr := leaf.Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Intermediates: intermediates,
+	})
+
+	return leaf, verifyErr == nil, nil
+}
+
+// Evaluate classifies a certificate by its remaining lifetime
+func Evaluate(info *Info, now time.Time, warningDays int) {
+	remaining := info.NotAfter.Sub(now)
+	info.DaysUntilExpiry = int(math.Floor(remaining.Hours() / 24))
+
+	switch {
+	case remaining <= 0:
+		info.Status = "EXPIRED"
+	case IsExpiring(info.DaysUntilExpiry, warningDays):
+		info.Status = "EXPIRING"
+	default:
+		info.Status = "VALID"
+	}
+}
+
+// IsExpiring reports whether a certificate with the given whole days left is
+// within the certificates.warning_days threshold
+func IsExpiring(daysUntilExpiry, warningDays int) bool {
+	return daysUntilExpiry < warningDays
+}
+
+// serverName returns the host part of an address for SNI
+func serverName(address string) string {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	return host
+}
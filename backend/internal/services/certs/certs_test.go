@@ -0,0 +1,114 @@
+package certs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		notAfter time.Time
+		status   string
+		days     int
+	}{
+		{"valid", now.Add(90 * 24 * time.Hour), "VALID", 90},
+		{"at threshold", now.Add(14*24*time.Hour + time.Hour), "VALID", 14},
+		{"expiring", now.Add(13*24*time.Hour + time.Hour), "EXPIRING", 13},
+		{"expires today", now.Add(time.Hour), "EXPIRING", 0},
+		{"expired", now.Add(-36 * time.Hour), "EXPIRED", -2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := Info{NotAfter: tt.notAfter}
+			Evaluate(&info, now, 14)
+			assert.Equal(t, tt.status, info.Status)
+			assert.Equal(t, tt.days, info.DaysUntilExpiry)
+		})
+	}
+}
+
+func TestIsExpiring(t *testing.T) {
+	assert.False(t, IsExpiring(15, 14))
+	assert.False(t, IsExpiring(14, 14))
+	assert.True(t, IsExpiring(13, 14))
+	assert.True(t, IsExpiring(0, 14))
+}
+
+func TestMonitor_Check(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	address := strings.TrimPrefix(server.URL, "https://")
+	monitor := NewMonitor(nil, time.Hour, 14)
+
+	info := monitor.Check(context.Background(), Target{Name: "test", Address: address})
+	require.Empty(t, info.Error)
+
+	leaf := server.Certificate()
+	assert.Equal(t, leaf.NotAfter, info.NotAfter)
+	assert.Equal(t, leaf.DNSNames, info.DNSNames)
+	assert.NotEmpty(t, info.Issuer)
+	assert.False(t, info.Trusted) // self-signed test certificate
+	assert.NotEmpty(t, info.Status)
+}
+
+func TestMonitor_CheckUnreachable(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	address := strings.TrimPrefix(server.URL, "https://")
+	server.Close()
+
+	monitor := NewMonitor(nil, time.Hour, 14)
+	info := monitor.Check(context.Background(), Target{Name: "down", Address: address})
+	assert.Equal(t, "ERROR", info.Status)
+	assert.Contains(t, info.Error, "tls handshake failed")
+}
+
+func TestMonitor_List(t *testing.T) {
+	now := time.Now()
+	monitor := NewMonitor(nil, time.Hour, 14)
+	monitor.results["later"] = Info{Name: "later", NotAfter: now.Add(48 * time.Hour)}
+	monitor.results["broken"] = Info{Name: "broken", Status: "ERROR"}
+	monitor.results["sooner"] = Info{Name: "sooner", NotAfter: now.Add(24 * time.Hour)}
+
+	list := monitor.List()
+	require.Len(t, list, 3)
+	assert.Equal(t, "sooner", list[0].Name)
+	assert.Equal(t, "later", list[1].Name)
+	assert.Equal(t, "broken", list[2].Name)
+}
+
+func TestIsExpiring_Disabled(t *testing.T) {
+	assert.False(t, IsExpiring(0, 0))
+	assert.False(t, IsExpiring(13, 0))
+}
+
+func TestMonitor_ForgetsRemovedTargets(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	targets := []Target{
+		{Name: "kept", Address: strings.TrimPrefix(server.URL, "https://"), ServiceID: "kept"},
+		{Name: "removed", Address: "127.0.0.1:1", ServiceID: "removed"},
+	}
+	monitor := NewMonitor(func() []Target { return targets }, time.Hour, 14)
+
+	monitor.checkAll(context.Background())
+	require.Len(t, monitor.List(), 2)
+
+	targets = targets[:1]
+	monitor.checkAll(context.Background())
+	require.Len(t, monitor.List(), 1)
+	_, ok := monitor.ForService("removed")
+	assert.False(t, ok)
+}
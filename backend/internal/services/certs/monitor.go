@@ -0,0 +1,199 @@
+package certs
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"home-run-backend/internal/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Target is a TLS endpoint whose certificate is tracked
+type Target struct {
+	Name      string
+	Address   string // host:port
+	ServiceID string // set for targets derived from a service
+}
+
+// key identifies a target's result. Services and tls_check entries are kept
+// apart, so a check named like a service can't replace its result.
+func (t Target) key() string {
+	if t.ServiceID != "" {
+		return "service/" + t.ServiceID
+	}
+	return "check/" + t.Name
+}
+
+// Monitor periodically checks the certificates of a set of targets
+type Monitor struct {
+	targets     func() []Target
+	interval    time.Duration
+	timeout     time.Duration
+	warningDays int
+	results     map[string]Info // keyed by Target.key
+	mu          sync.RWMutex
+	running     bool
+	stopCh      chan struct{}
+}
+
+// DefaultInterval is used when the monitor has no explicit interval
+const DefaultInterval = 6 * time.Hour
+
+// NewMonitor creates a certificate monitor. targets is called on every round,
+// so endpoints that appear or disappear at runtime are picked up.
+func NewMonitor(targets func() []Target, interval time.Duration, warningDays int) *Monitor {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Monitor{
+		targets:     targets,
+		interval:    interval,
+		timeout:     10 * time.Second,
+		warningDays: warningDays,
+		results:     make(map[string]Info),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// WarningDays returns the expiry threshold in days
+func (m *Monitor) WarningDays() int {
+	return m.warningDays
+}
+
+// Start begins checking certificates in the background
+func (m *Monitor) Start(ctx context.Context) {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return
+	}
+	m.running = true
+	m.mu.Unlock()
+
+	logger.WithField("interval", m.interval).Info("Starting certificate monitor")
+
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			m.checkAll(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the certificate monitor
+func (m *Monitor) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.running {
+		close(m.stopCh)
+		m.running = false
+	}
+}
+
+// ForService returns the latest certificate info of a service's https url
+func (m *Monitor) ForService(serviceID string) (Info, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	info, ok := m.results[Target{ServiceID: serviceID}.key()]
+	return info, ok
+}
+
+// List returns the latest certificate info for all checked targets, soonest expiry first
+func (m *Monitor) List() []Info {
+	m.mu.RLock()
+	result := make([]Info, 0, len(m.results))
+	for _, info := range m.results {
+		result = append(result, info)
+	}
+	m.mu.RUnlock()
+
+	// Failed checks have no expiry and sort last
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].NotAfter, result[j].NotAfter
+		if a.IsZero() != b.IsZero() {
+			return b.IsZero()
+		}
+		if a.Equal(b) {
+			return result[i].Name < result[j].Name
+		}
+		return a.Before(b)
+	})
+	return result
+}
+
+// checkAll checks every current target once and forgets targets that are gone
+func (m *Monitor) checkAll(ctx context.Context) {
+	targets := m.targets()
+	current := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		info := m.Check(ctx, target)
+		current[target.key()] = true
+
+		m.mu.Lock()
+		m.results[target.key()] = info
+		m.mu.Unlock()
+	}
+
+	m.mu.Lock()
+	for key := range m.results {
+		if !current[key] {
+			delete(m.results, key)
+		}
+	}
+	m.mu.Unlock()
+}
+
+// Check fetches and evaluates the certificate of a single target
+func (m *Monitor) Check(ctx context.Context, target Target) Info {
+	info := Info{
+		Name:      target.Name,
+		Address:   target.Address,
+		ServiceID: target.ServiceID,
+		CheckedAt: time.Now(),
+	}
+
+	cert, trusted, err := Fetch(ctx, target.Address, serverName(target.Address), m.timeout)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"target": target.Name,
+			"error":  err.Error(),
+		}).Warn("Failed to check certificate")
+		info.Status = "ERROR"
+		info.Error = err.Error()
+		return info
+	}
+
+	info.Subject = cert.Subject.CommonName
+	info.Issuer = cert.Issuer.CommonName
+	if info.Issuer == "" {
+		info.Issuer = cert.Issuer.String()
+	}
+	info.DNSNames = cert.DNSNames
+	info.NotBefore = cert.NotBefore
+	info.NotAfter = cert.NotAfter
+	info.Trusted = trusted
+	Evaluate(&info, info.CheckedAt, m.warningDays)
+
+	if info.Status != "VALID" {
+		logger.WithFields(logrus.Fields{
+			"target":    target.Name,
+			"not_after": info.NotAfter,
+			"days_left": info.DaysUntilExpiry,
+		}).Warn("Certificate is expiring")
+	}
+
+	return info
+}
@@ -0,0 +1,66 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"home-run-backend/internal/logger"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/sirupsen/logrus"
+)
+
+// Container lifecycle actions
+const (
+	ActionStart   = "start"
+	ActionStop    = "stop"
+	ActionRestart = "restart"
+	ActionPause   = "pause"
+	ActionUnpause = "unpause"
+)
+
+// Actions lists the supported lifecycle actions
+var Actions = []string{ActionStart, ActionStop, ActionRestart, ActionPause, ActionUnpause}
+
+// ErrUnknownAction is returned for actions not in Actions
+var ErrUnknownAction = errors.New("unknown container action")
+
+// ContainerAction runs a lifecycle action on a container by name and returns
+// its state afterwards
+func (c *Client) ContainerAction(ctx context.Context, containerName, action string) (*ContainerInfo, error) {
+	containers, err := c.ListContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, ok := containers[containerName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrContainerNotFound, containerName)
+	}
+
+	switch action {
+	case ActionStart:
+		err = c.cli.ContainerStart(ctx, info.ID, container.StartOptions{})
+	case ActionStop:
+		err = c.cli.ContainerStop(ctx, info.ID, container.StopOptions{})
+	case ActionRestart:
+		err = c.cli.ContainerRestart(ctx, info.ID, container.StopOptions{})
+	case ActionPause:
+		err = c.cli.ContainerPause(ctx, info.ID)
+	case ActionUnpause:
+		err = c.cli.ContainerUnpause(ctx, info.ID)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAction, action)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s container: %w", action, err)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"container": containerName,
+		"id":        info.ID,
+		"action":    action,
+	}).Info("Container action completed")
+
+	return c.GetContainerInfo(ctx, containerName)
+}
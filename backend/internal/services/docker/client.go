@@ -3,10 +3,13 @@ package docker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
+	"home-run-backend/internal/config"
 	"home-run-backend/internal/logger"
 
 	"github.com/docker/docker/api/types/container"
@@ -16,96 +19,265 @@ import (
 
 // ContainerInfo holds container status information
 type ContainerInfo struct {
-	ID        string
-	Status    string // RUNNING, STOPPED, ERROR, MAINTENANCE
-	State     string // raw docker state
-	StartedAt time.Time
+	ID            string        `json:"id"`
+	Status        string        `json:"status"`           // RUNNING, STOPPED, ERROR, MAINTENANCE
+	State         string        `json:"state"`            // raw docker state
+	Health        string        `json:"health,omitempty"` // healthy, unhealthy, starting; empty without a healthcheck
+	FailingStreak int           `json:"failingStreak,omitempty"`
+	HealthLog     []HealthProbe `json:"healthLog,omitempty"` // most recent healthcheck runs, oldest first
+	RestartCount  int           `json:"restartCount"`        // restarts by the restart policy
+	OOMKilled     bool          `json:"oomKilled"`           // last exit was an out-of-memory kill
+	StartedAt     time.Time     `json:"startedAt"`
+
+	// From the labels docker compose sets on the containers it creates
+	ComposeProject     string   `json:"composeProject,omitempty"`
+	ComposeService     string   `json:"composeService,omitempty"`
+	ComposeConfigFiles []string `json:"composeConfigFiles,omitempty"`
+}
+
+// clearHealth forgets healthcheck results from a previous run of the container
+func (i *ContainerInfo) clearHealth() {
+	i.Health, i.FailingStreak, i.HealthLog = "", 0, nil
+}
+
+// HealthProbe is the result of one healthcheck run
+type HealthProbe struct {
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	ExitCode int       `json:"exitCode"`
+	Output   string    `json:"output"`
 }
 
-// Stats holds container resource usage
+// crashLoopWindow is how long after an automatic restart a container counts as degraded
+const crashLoopWindow = 5 * time.Minute
+
+// ServiceStatus returns the status to report for the container. Running
+// containers are degraded to WARNING while their healthcheck fails, or shortly
+// after the restart policy brought them back up.
+func (i ContainerInfo) ServiceStatus(now time.Time) string {
+	if i.Status != "RUNNING" {
+		return i.Status
+	}
+	if i.Health == "unhealthy" {
+		return "WARNING"
+	}
+	if i.RestartCount > 0 && !i.StartedAt.IsZero() && now.Sub(i.StartedAt) < crashLoopWindow {
+		return "WARNING"
+	}
+	return i.Status
+}
+
+// Stats holds a container resource usage sample. Network and block I/O are
+// cumulative byte counters; see Usage for rates.
 type Stats struct {
-	CPUPercent float64
-	MemoryMB   float64
+	CPUPercent      float64
+	MemoryMB        float64
+	MemoryLimitMB   float64
+	PIDs            uint64
+	NetworkRxBytes  uint64 // all networks
+	NetworkTxBytes  uint64
+	BlockReadBytes  uint64
+	BlockWriteBytes uint64
+	Read            time.Time
 }
 
+// ErrContainerNotFound is returned for operations on a container the daemon doesn't have
+var ErrContainerNotFound = errors.New("container not found")
+
 // Client wraps the Docker API client
 type Client struct {
-	cli *client.Client
+	cli       *client.Client
+	mu        sync.RWMutex
+	health    Health
+	done      chan struct{} // closed by Close to stop Watch
+	closeOnce sync.Once
+}
+
+// OpenClient creates a client from the environment (DOCKER_HOST etc.). It
+// doesn't fail while the daemon is unreachable; run Watch to reconnect.
+func OpenClient() (*Client, error) {
+	return openClient("", client.FromEnv)
+}
+
+// OpenClientFromConfig creates a client for a configured daemon: a unix socket,
+// tcp with optional TLS client certificates, or ssh. Like OpenClient, it
+// doesn't fail while the daemon is unreachable.
+func OpenClientFromConfig(host config.DockerHostConfig) (*Client, error) {
+	opts, err := hostOpts(host)
+	if err != nil {
+		return nil, err
+	}
+	return openClient(host.Host, opts...)
+}
+
+// hostOpts returns the client options for a configured daemon
+func hostOpts(host config.DockerHostConfig) ([]client.Opt, error) {
+	if strings.HasPrefix(host.Host, "ssh://") {
+		dialer, err := sshDialer(host.Host)
+		if err != nil {
+			return nil, err
+		}
+		// The host is only used for the request URL; connections go through ssh
+		return []client.Opt{client.WithHost("http://docker.example.com"), client.WithDialContext(dialer)}, nil
+	}
+
+	opts := []client.Opt{client.WithHost(host.Host)}
+	if host.TLSCACert != "" || host.TLSCert != "" {
+		opts = append(opts, client.WithTLSClientConfig(host.TLSCACert, host.TLSCert, host.TLSKey))
+	}
+	return opts, nil
 }
 
-// NewClient creates a new Docker client
-func NewClient() (*Client, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+// openClient creates a client and pings the daemon once to learn its health.
+// host is the configured daemon address reported in Health; when empty, the
+// address the client resolved (from the environment) is used.
+func openClient(host string, opts ...client.Opt) (*Client, error) {
+	cli, err := client.NewClientWithOpts(append(opts, client.WithAPIVersionNegotiation())...)
 	if err != nil {
 		logger.WithField("error", err.Error()).Warn("Failed to create Docker client")
 		return nil, fmt.Errorf("failed to create docker client: %w", err)
 	}
+	if host == "" {
+		host = cli.DaemonHost()
+	}
 
-	// Test connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	_, err = cli.Ping(ctx)
-	if err != nil {
-		logger.WithField("error", err.Error()).Warn("Failed to connect to Docker daemon")
-		return nil, fmt.Errorf("failed to connect to docker daemon: %w", err)
+	c := &Client{
+		cli:    cli,
+		health: Health{Host: host},
+		done:   make(chan struct{}),
 	}
 
-	logger.Log.Info("Docker client initialized successfully")
-	return &Client{cli: cli}, nil
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err := c.Ping(ctx); err == nil {
+		logger.WithField("host", host).Info("Docker client initialized successfully")
+	}
+	return c, nil
 }
 
-// Close closes the Docker client
+// Close stops Watch and closes the Docker client
 func (c *Client) Close() error {
+	c.closeOnce.Do(func() { close(c.done) })
 	return c.cli.Close()
 }
 
 // GetContainerInfo retrieves status information for a container by name
 func (c *Client) GetContainerInfo(ctx context.Context, containerName string) (*ContainerInfo, error) {
-	containers, err := c.cli.ContainerList(ctx, container.ListOptions{All: true})
+	containers, err := c.ListContainers(ctx)
 	if err != nil {
 		logger.WithFields(logrus.Fields{
 			"container": containerName,
 			"error":     err.Error(),
 		}).Error("Failed to list containers")
-		return nil, fmt.Errorf("failed to list containers: %w", err)
+		return nil, err
 	}
 
-	// Normalize container name (Docker prepends /)
-	searchName := containerName
-	if !strings.HasPrefix(searchName, "/") {
-		searchName = "/" + containerName
+	info, ok := containers[strings.TrimPrefix(containerName, "/")]
+	if !ok {
+		logger.WithField("container", containerName).Warn("Container not found")
+		return nil, fmt.Errorf("%w: %s", ErrContainerNotFound, containerName)
 	}
 
+	// Get detailed inspection for uptime
+	if err := c.inspectState(ctx, &info); err != nil {
+		logger.WithFields(logrus.Fields{
+			"container": containerName,
+			"error":     err.Error(),
+		}).Debug("Failed to inspect container")
+	}
+
+	logger.WithFields(logrus.Fields{
+		"container": containerName,
+		"id":        info.ID,
+		"status":    info.Status,
+	}).Debug("Retrieved container info")
+	return &info, nil
+}
+
+// ListContainers returns the status of every container on the daemon keyed by
+// name, using a single API call. Fields that need an inspection are not filled in.
+func (c *Client) ListContainers(ctx context.Context) (map[string]ContainerInfo, error) {
+	containers, err := c.cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	result := make(map[string]ContainerInfo, len(containers))
 	for _, ctr := range containers {
+		info := ContainerInfo{
+			ID:     shortID(ctr.ID),
+			Status: mapDockerState(ctr.State),
+			State:  ctr.State,
+		}
+		applyComposeLabels(&info, ctr.Labels)
+		// Docker prepends / to container names
 		for _, name := range ctr.Names {
-			if name == searchName || name == containerName {
-				info := &ContainerInfo{
-					ID:     ctr.ID[:12],
-					Status: mapDockerState(ctr.State),
-					State:  ctr.State,
-				}
-
-				// Get detailed inspection for uptime
-				inspect, err := c.cli.ContainerInspect(ctx, ctr.ID)
-				if err == nil && inspect.State != nil && inspect.State.StartedAt != "" {
-					startedAt, err := time.Parse(time.RFC3339Nano, inspect.State.StartedAt)
-					if err == nil {
-						info.StartedAt = startedAt
-					}
-				}
-
-				logger.WithFields(logrus.Fields{
-					"container": containerName,
-					"id":        info.ID,
-					"status":    info.Status,
-				}).Debug("Retrieved container info")
-				return info, nil
-			}
+			result[strings.TrimPrefix(name, "/")] = info
 		}
 	}
+	return result, nil
+}
 
-	logger.WithField("container", containerName).Warn("Container not found")
-	return nil, fmt.Errorf("container '%s' not found", containerName)
+// restartPolicyRestarts reports whether a container's restart policy makes
+// docker restart it after a non-zero exit by itself
+func (c *Client) restartPolicyRestarts(ctx context.Context, name string) (bool, error) {
+	inspect, err := c.cli.ContainerInspect(ctx, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if inspect.ContainerJSONBase == nil || inspect.HostConfig == nil {
+		return false, nil
+	}
+
+	policy := inspect.HostConfig.RestartPolicy
+	switch policy.Name {
+	case container.RestartPolicyAlways, container.RestartPolicyUnlessStopped:
+		return true, nil
+	case container.RestartPolicyOnFailure:
+		// Docker counts the restart before it reports the exit
+		restarting := inspect.State != nil && inspect.State.Restarting
+		return policy.MaximumRetryCount == 0 || inspect.RestartCount < policy.MaximumRetryCount || restarting, nil
+	}
+	return false, nil
+}
+
+// inspectState fills in start time, health, restart count and OOM kill from
+// a container inspection
+func (c *Client) inspectState(ctx context.Context, info *ContainerInfo) error {
+	inspect, err := c.cli.ContainerInspect(ctx, info.ID)
+	if err != nil {
+		return fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if inspect.ContainerJSONBase == nil || inspect.State == nil {
+		return nil
+	}
+
+	info.RestartCount = inspect.RestartCount
+	info.OOMKilled = inspect.State.OOMKilled
+	info.StartedAt = time.Time{}
+	if inspect.State.StartedAt != "" {
+		if startedAt, err := time.Parse(time.RFC3339Nano, inspect.State.StartedAt); err == nil {
+			info.StartedAt = startedAt
+		}
+	}
+
+	info.clearHealth()
+	if health := inspect.State.Health; health != nil {
+		info.Health = health.Status
+		info.FailingStreak = health.FailingStreak
+		for _, result := range health.Log {
+			if result == nil {
+				continue
+			}
+			info.HealthLog = append(info.HealthLog, HealthProbe{
+				Start:    result.Start,
+				End:      result.End,
+				ExitCode: result.ExitCode,
+				Output:   strings.TrimSpace(result.Output),
+			})
+		}
+	}
+	return nil
 }
 
 // GetContainerStats retrieves resource usage for a container
@@ -121,10 +293,35 @@ func (c *Client) GetContainerStats(ctx context.Context, containerID string) (*St
 		return nil, fmt.Errorf("failed to decode stats: %w", err)
 	}
 
-	return &Stats{
-		CPUPercent: calculateCPUPercent(&stats),
-		MemoryMB:   float64(stats.MemoryStats.Usage) / (1024 * 1024),
-	}, nil
+	return statsFromResponse(&stats), nil
+}
+
+// statsFromResponse extracts a usage sample from a stats response
+func statsFromResponse(stats *container.StatsResponse) *Stats {
+	result := &Stats{
+		CPUPercent:    calculateCPUPercent(stats),
+		MemoryMB:      float64(stats.MemoryStats.Usage) / (1024 * 1024),
+		MemoryLimitMB: float64(stats.MemoryStats.Limit) / (1024 * 1024),
+		PIDs:          stats.PidsStats.Current,
+		Read:          stats.Read,
+	}
+
+	for _, network := range stats.Networks {
+		result.NetworkRxBytes += network.RxBytes
+		result.NetworkTxBytes += network.TxBytes
+	}
+
+	// cgroup v1 reports "Read"/"Write", v2 "read"/"write"
+	for _, entry := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			result.BlockReadBytes += entry.Value
+		case "write":
+			result.BlockWriteBytes += entry.Value
+		}
+	}
+
+	return result
 }
 
 // calculateCPUPercent calculates CPU usage percentage
@@ -144,6 +341,14 @@ func calculateCPUPercent(stats *container.StatsResponse) float64 {
 	return 0
 }
 
+// shortID truncates a container ID to the 12 characters docker displays
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
 // mapDockerState converts Docker state to our status enum
 func mapDockerState(state string) string {
 	switch strings.ToLower(state) {
@@ -1,11 +1,27 @@
 package docker
 
 import (
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"home-run-backend/internal/config"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// openTestClient opens a client for a fake daemon and checks that it answered the ping
+func openTestClient(t *testing.T, server *httptest.Server) *Client {
+	t.Helper()
+	c, err := OpenClientFromConfig(config.DockerHostConfig{Host: "tcp://" + strings.TrimPrefix(server.URL, "http://")})
+	require.NoError(t, err)
+	require.True(t, c.Connected(), c.Health().Error)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
 func TestMapDockerState(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -37,3 +53,39 @@ func TestCalculateCPUPercent_ZeroValues(t *testing.T) {
 		_ = mapDockerState("running")
 	})
 }
+
+func TestContainerInfo_ServiceStatus(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		info     ContainerInfo
+		expected string
+	}{
+		{"running", ContainerInfo{Status: "RUNNING", Health: "healthy", StartedAt: now.Add(-time.Hour)}, "RUNNING"},
+		{"unhealthy", ContainerInfo{Status: "RUNNING", Health: "unhealthy", StartedAt: now.Add(-time.Hour)}, "WARNING"},
+		{"health starting", ContainerInfo{Status: "RUNNING", Health: "starting", StartedAt: now}, "RUNNING"},
+		{"recently restarted by policy", ContainerInfo{Status: "RUNNING", RestartCount: 3, StartedAt: now.Add(-time.Minute)}, "WARNING"},
+		{"restarted long ago", ContainerInfo{Status: "RUNNING", RestartCount: 3, StartedAt: now.Add(-time.Hour)}, "RUNNING"},
+		{"stopped after oom", ContainerInfo{Status: "STOPPED", OOMKilled: true}, "STOPPED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.info.ServiceStatus(now))
+		})
+	}
+}
+
+func TestOpenClientFromConfig_SSHHost(t *testing.T) {
+	t.Setenv("PATH", "") // no ssh binary, so the first ping fails at once
+
+	c, err := OpenClientFromConfig(config.DockerHostConfig{Host: "ssh://admin@nas.lan"})
+	require.NoError(t, err)
+	defer c.Close()
+
+	health := c.Health()
+	assert.Equal(t, "ssh://admin@nas.lan", health.Host)
+	assert.False(t, health.Connected)
+	assert.NotEmpty(t, health.Error)
+}
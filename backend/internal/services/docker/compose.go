@@ -0,0 +1,167 @@
+package docker
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"home-run-backend/internal/config"
+)
+
+// Labels docker compose sets on the containers it creates
+const (
+	LabelComposeProject     = "com.docker.compose.project"
+	LabelComposeService     = "com.docker.compose.service"
+	LabelComposeConfigFiles = "com.docker.compose.project.config_files"
+)
+
+// ProjectContainer is a container that belongs to a compose project
+type ProjectContainer struct {
+	Name    string
+	Service string // compose service
+	Status  string
+}
+
+// ProjectInfo holds the rolled-up status of a compose project
+type ProjectInfo struct {
+	Name        string
+	Status      string // RUNNING, STOPPED, WARNING (partially up or degraded), MAINTENANCE
+	Running     int
+	Total       int
+	StartedAt   time.Time // earliest start among running containers
+	ConfigFiles []string  // compose files on the docker host
+	Containers  []ProjectContainer
+	Usage       // summed over the containers, without memory limit
+}
+
+// applyComposeLabels records which compose project a container belongs to
+func applyComposeLabels(info *ContainerInfo, labels map[string]string) {
+	info.ComposeProject = labels[LabelComposeProject]
+	info.ComposeService = labels[LabelComposeService]
+	info.ComposeConfigFiles = nil
+	for _, file := range strings.Split(labels[LabelComposeConfigFiles], ",") {
+		if file = strings.TrimSpace(file); file != "" {
+			info.ComposeConfigFiles = append(info.ComposeConfigFiles, file)
+		}
+	}
+}
+
+// projectMembers returns the names of the containers in a compose project, sorted
+func projectMembers(project string, containers map[string]ContainerInfo) []string {
+	var names []string
+	for name, info := range containers {
+		// Legacy links list a container under extra names like web/db
+		if info.ComposeProject == project && !strings.Contains(name, "/") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// expandProjects replaces compose project services by one target per
+// container in the project, returning the members of each project
+func expandProjects(targets []config.ServiceConfig, containers map[string]ContainerInfo) ([]config.ServiceConfig, map[string][]string) {
+	result := make([]config.ServiceConfig, 0, len(targets))
+	projects := make(map[string][]string)
+	seen := make(map[string]bool, len(targets))
+
+	add := func(svc config.ServiceConfig) {
+		if !seen[svc.ContainerName] {
+			seen[svc.ContainerName] = true
+			result = append(result, svc)
+		}
+	}
+
+	for _, svc := range targets {
+		if svc.ComposeProject == "" {
+			add(svc)
+			continue
+		}
+		members := projectMembers(svc.ComposeProject, containers)
+		projects[svc.ComposeProject] = members
+		for _, name := range members {
+			add(config.ServiceConfig{
+				Name:          name,
+				Backend:       svc.Backend,
+				ContainerName: name,
+				DockerHost:    svc.DockerHost,
+			})
+		}
+	}
+	return result, projects
+}
+
+// rollUpProject derives project status from the cached state of its containers.
+// Containers missing from the cache count as ERROR.
+func rollUpProject(project string, members []string, cached map[string]*CachedStats, now time.Time) *ProjectInfo {
+	info := &ProjectInfo{Name: project}
+	paused, degraded := 0, false
+
+	for _, name := range members {
+		stats, ok := cached[name]
+		if !ok || stats == nil {
+			stats = &CachedStats{ContainerInfo: ContainerInfo{Status: "ERROR"}}
+		}
+
+		status := stats.ServiceStatus(now)
+		info.Containers = append(info.Containers, ProjectContainer{
+			Name:    name,
+			Service: stats.ComposeService,
+			Status:  status,
+		})
+		info.Total++
+		if len(info.ConfigFiles) == 0 {
+			info.ConfigFiles = stats.ComposeConfigFiles
+		}
+
+		switch stats.Status {
+		case "RUNNING":
+			info.Running++
+			degraded = degraded || status != "RUNNING"
+			if info.StartedAt.IsZero() || stats.StartedAt.Before(info.StartedAt) {
+				info.StartedAt = stats.StartedAt
+			}
+			info.CPUPercent += stats.CPUPercent
+			info.MemoryMB += stats.MemoryMB
+			info.PIDs += stats.PIDs
+			info.NetRxBytesPerSec += stats.NetRxBytesPerSec
+			info.NetTxBytesPerSec += stats.NetTxBytesPerSec
+			info.BlockReadBytesPerSec += stats.BlockReadBytesPerSec
+			info.BlockWriteBytesPerSec += stats.BlockWriteBytesPerSec
+		case "MAINTENANCE":
+			paused++
+		}
+	}
+
+	switch {
+	case info.Total > 0 && info.Running == info.Total && !degraded:
+		info.Status = "RUNNING"
+	case info.Total > 0 && paused == info.Total:
+		info.Status = "MAINTENANCE"
+	case info.Running > 0:
+		info.Status = "WARNING"
+	default:
+		// docker compose down removes the containers
+		info.Status = "STOPPED"
+	}
+
+	return info
+}
+
+// GetProjectInfo retrieves the containers of a compose project and rolls up
+// their status. Health and restarts need an inspection and are not considered.
+func (c *Client) GetProjectInfo(ctx context.Context, project string) (*ProjectInfo, error) {
+	containers, err := c.ListContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members := projectMembers(project, containers)
+	cached := make(map[string]*CachedStats, len(members))
+	for _, name := range members {
+		cached[name] = &CachedStats{ContainerInfo: containers[name]}
+	}
+	return rollUpProject(project, members, cached, time.Now()), nil
+}
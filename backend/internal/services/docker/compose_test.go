@@ -0,0 +1,120 @@
+package docker
+
+import (
+	"testing"
+	"time"
+
+	"home-run-backend/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyComposeLabels(t *testing.T) {
+	var info ContainerInfo
+	applyComposeLabels(&info, map[string]string{
+		LabelComposeProject:     "maps",
+		LabelComposeService:     "web",
+		LabelComposeConfigFiles: "/srv/maps/compose.yml, /srv/maps/compose.override.yml",
+	})
+
+	assert.Equal(t, "maps", info.ComposeProject)
+	assert.Equal(t, "web", info.ComposeService)
+	assert.Equal(t, []string{"/srv/maps/compose.yml", "/srv/maps/compose.override.yml"}, info.ComposeConfigFiles)
+
+	applyComposeLabels(&info, nil)
+	assert.Empty(t, info.ComposeProject)
+	assert.Nil(t, info.ComposeConfigFiles)
+}
+
+func TestExpandProjects(t *testing.T) {
+	containers := map[string]ContainerInfo{
+		"maps-web-1":   {ID: "a", ComposeProject: "maps"},
+		"maps-tiles-1": {ID: "b", ComposeProject: "maps"},
+		"maps-web-1/x": {ID: "a", ComposeProject: "maps"},
+		"bazarr":       {ID: "c"},
+	}
+	targets := []config.ServiceConfig{
+		{Name: "Maps Web", Backend: "docker", ContainerName: "maps-web-1"},
+		{Name: "Maps", Backend: "docker", ComposeProject: "maps"},
+		{Name: "Empty", Backend: "docker", ComposeProject: "gone"},
+	}
+
+	expanded, projects := expandProjects(targets, containers)
+
+	var names []string
+	for _, svc := range expanded {
+		names = append(names, svc.ContainerName)
+		assert.Empty(t, svc.ComposeProject)
+	}
+	assert.Equal(t, []string{"maps-web-1", "maps-tiles-1"}, names)
+	assert.Equal(t, []string{"maps-tiles-1", "maps-web-1"}, projects["maps"])
+	assert.Contains(t, projects, "gone")
+	assert.Empty(t, projects["gone"])
+}
+
+func TestRollUpProject(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	running := func(started time.Time) *CachedStats {
+		return &CachedStats{
+			ContainerInfo: ContainerInfo{Status: "RUNNING", StartedAt: started, ComposeConfigFiles: []string{"/srv/maps/compose.yml"}},
+			Usage:         Usage{CPUPercent: 10, MemoryMB: 100, MemoryLimitMB: 1024, PIDs: 5},
+		}
+	}
+	stopped := &CachedStats{ContainerInfo: ContainerInfo{Status: "STOPPED"}}
+	paused := &CachedStats{ContainerInfo: ContainerInfo{Status: "MAINTENANCE"}}
+	unhealthy := running(now.Add(-time.Hour))
+	unhealthy.Health = "unhealthy"
+
+	tests := []struct {
+		name    string
+		members []string
+		cached  map[string]*CachedStats
+		status  string
+		running int
+	}{
+		{"all up", []string{"web", "tiles"}, map[string]*CachedStats{"web": running(now), "tiles": running(now)}, "RUNNING", 2},
+		{"partially up", []string{"web", "tiles"}, map[string]*CachedStats{"web": running(now), "tiles": stopped}, "WARNING", 1},
+		{"degraded", []string{"web", "tiles"}, map[string]*CachedStats{"web": running(now), "tiles": unhealthy}, "WARNING", 2},
+		{"down", []string{"web", "tiles"}, map[string]*CachedStats{"web": stopped, "tiles": stopped}, "STOPPED", 0},
+		{"paused", []string{"web"}, map[string]*CachedStats{"web": paused}, "MAINTENANCE", 0},
+		{"removed", nil, nil, "STOPPED", 0},
+		{"not cached", []string{"web", "tiles"}, map[string]*CachedStats{"web": running(now)}, "WARNING", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := rollUpProject("maps", tt.members, tt.cached, now)
+			assert.Equal(t, tt.status, info.Status)
+			assert.Equal(t, tt.running, info.Running)
+			assert.Equal(t, len(tt.members), info.Total)
+			assert.Len(t, info.Containers, len(tt.members))
+		})
+	}
+}
+
+func TestRollUpProject_Usage(t *testing.T) {
+	now := time.Now()
+	cached := map[string]*CachedStats{
+		"web": {
+			ContainerInfo: ContainerInfo{Status: "RUNNING", StartedAt: now.Add(-time.Hour), ComposeService: "web", ComposeConfigFiles: []string{"/srv/maps/compose.yml"}},
+			Usage:         Usage{CPUPercent: 10, MemoryMB: 100, MemoryLimitMB: 512, PIDs: 5, NetRxBytesPerSec: 1000},
+		},
+		"tiles": {
+			ContainerInfo: ContainerInfo{Status: "RUNNING", StartedAt: now.Add(-2 * time.Hour), ComposeService: "tiles"},
+			Usage:         Usage{CPUPercent: 5, MemoryMB: 50, MemoryLimitMB: 512, PIDs: 3, NetRxBytesPerSec: 500},
+		},
+	}
+
+	info := rollUpProject("maps", []string{"tiles", "web"}, cached, now)
+
+	assert.InDelta(t, 15, info.CPUPercent, 0.001)
+	assert.InDelta(t, 150, info.MemoryMB, 0.001)
+	assert.Zero(t, info.MemoryLimitMB)
+	assert.EqualValues(t, 8, info.PIDs)
+	assert.InDelta(t, 1500, info.NetRxBytesPerSec, 0.001)
+	assert.True(t, now.Add(-2*time.Hour).Equal(info.StartedAt))
+	assert.Equal(t, []string{"/srv/maps/compose.yml"}, info.ConfigFiles)
+	require.Len(t, info.Containers, 2)
+	assert.Equal(t, ProjectContainer{Name: "tiles", Service: "tiles", Status: "RUNNING"}, info.Containers[0])
+}
@@ -0,0 +1,108 @@
+package docker
+
+import (
+	"context"
+	"time"
+
+	"home-run-backend/internal/backoff"
+	"home-run-backend/internal/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// pingTimeout bounds a single health ping
+	pingTimeout = 5 * time.Second
+	// healthInterval is how often a connected daemon is pinged
+	healthInterval = 30 * time.Second
+)
+
+// reconnectBackoff spaces out pings of an unreachable daemon
+var reconnectBackoff = backoff.Exponential{Min: time.Second, Max: time.Minute}
+
+// Health is whether the daemon answered the last ping
+type Health struct {
+	Host      string    `json:"host"` // daemon address
+	Connected bool      `json:"connected"`
+	Error     string    `json:"error,omitempty"`    // why the last ping failed
+	Attempts  int       `json:"attempts,omitempty"` // failed pings since the daemon was last reachable
+	Since     time.Time `json:"since"`              // when Connected last changed
+	LastCheck time.Time `json:"lastCheck"`
+}
+
+// Health returns the result of the last ping
+func (c *Client) Health() Health {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.health
+}
+
+// Connected reports whether the daemon answered the last ping
+func (c *Client) Connected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.health.Connected
+}
+
+// Ping checks that the daemon is reachable and records the result in Health
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.cli.Ping(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	was := c.health
+	c.health.LastCheck = now
+	if err != nil {
+		c.health.Connected = false
+		c.health.Error = err.Error()
+		c.health.Attempts++
+		if was.Connected || was.Since.IsZero() {
+			c.health.Since = now
+			logger.WithFields(logrus.Fields{
+				"host":  c.health.Host,
+				"error": err.Error(),
+			}).Warn("Docker daemon unreachable")
+		}
+		return err
+	}
+
+	c.health.Connected = true
+	c.health.Error = ""
+	c.health.Attempts = 0
+	if !was.Connected {
+		c.health.Since = now
+		if !was.Since.IsZero() {
+			logger.WithFields(logrus.Fields{
+				"host":     c.health.Host,
+				"attempts": was.Attempts,
+			}).Info("Docker daemon reconnected")
+		}
+	}
+	return nil
+}
+
+// Watch pings the daemon until ctx is cancelled or the client is closed:
+// every healthInterval while it is reachable, and with exponential backoff
+// while it is not. Requests made once it is back reconnect on their own.
+func (c *Client) Watch(ctx context.Context) {
+	for {
+		pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+		err := c.Ping(pingCtx)
+		cancel()
+
+		delay := healthInterval
+		if err != nil {
+			delay = reconnectBackoff.Delay(c.Health().Attempts)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.done:
+			return
+		case <-time.After(delay):
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package docker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"home-run-backend/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyDaemon answers pings while up and drops connections while down
+func flakyDaemon(t *testing.T, up *atomic.Bool) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up.Load() {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			conn.Close()
+			return
+		}
+		w.Header().Set("Api-Version", "1.45")
+		_, _ = w.Write([]byte("OK"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_ReconnectsAfterOutage(t *testing.T) {
+	var up atomic.Bool
+	server := flakyDaemon(t, &up)
+	host := "tcp://" + strings.TrimPrefix(server.URL, "http://")
+
+	// An unreachable daemon is not an error
+	c, err := OpenClientFromConfig(config.DockerHostConfig{Host: host})
+	require.NoError(t, err)
+	defer c.Close()
+
+	health := c.Health()
+	assert.False(t, health.Connected)
+	assert.Equal(t, 1, health.Attempts)
+	assert.NotEmpty(t, health.Error)
+	assert.False(t, health.Since.IsZero())
+
+	require.Error(t, c.Ping(context.Background()))
+	assert.Equal(t, 2, c.Health().Attempts)
+	assert.Equal(t, health.Since, c.Health().Since, "still down since the first failure")
+
+	up.Store(true)
+	require.NoError(t, c.Ping(context.Background()))
+	health = c.Health()
+	assert.True(t, health.Connected)
+	assert.Zero(t, health.Attempts)
+	assert.Empty(t, health.Error)
+
+	up.Store(false)
+	require.Error(t, c.Ping(context.Background()))
+	assert.False(t, c.Connected())
+	assert.Equal(t, 1, c.Health().Attempts)
+}
+
+func TestClient_WatchStopsOnClose(t *testing.T) {
+	var up atomic.Bool
+	up.Store(true)
+	server := flakyDaemon(t, &up)
+
+	c := openTestClient(t, server)
+
+	done := make(chan struct{})
+	go func() {
+		c.Watch(context.Background())
+		close(done)
+	}()
+
+	c.Close()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Watch did not return after Close")
+	}
+}
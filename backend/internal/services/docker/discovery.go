@@ -0,0 +1,127 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/logger"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/sirupsen/logrus"
+)
+
+// Labels read from containers during auto-discovery
+const (
+	LabelEnable  = "home-run.enable"
+	LabelName    = "home-run.name"
+	LabelPort    = "home-run.port"
+	LabelURL     = "home-run.url"
+	LabelConfigs = "home-run.configs"
+)
+
+// defaultDiscoveryURL is used for discovered containers without a home-run.url label
+const defaultDiscoveryURL = "http://localhost"
+
+// DiscoverServices lists containers labelled home-run.enable=true and turns them
+// into docker service entries for the given docker_host. Containers that a
+// configured service already covers are skipped.
+func (c *Client) DiscoverServices(ctx context.Context, dockerHost string, configured []config.ServiceConfig) ([]config.ServiceConfig, error) {
+	containers, err := c.cli.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", LabelEnable+"=true")),
+	})
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"docker_host": dockerHost,
+			"error":       err.Error(),
+		}).Warn("Failed to list labelled containers")
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	var services []config.ServiceConfig
+	for _, ctr := range containers {
+		if len(ctr.Names) == 0 {
+			continue
+		}
+		name := strings.TrimPrefix(ctr.Names[0], "/")
+		if isConfigured(configured, name, ctr.Labels[LabelComposeProject]) {
+			continue
+		}
+		services = append(services, serviceFromLabels(dockerHost, name, ctr.Labels, ctr.Ports))
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+	return services, nil
+}
+
+// isConfigured reports whether a container is covered by a configured service,
+// either by name or as a member of a configured compose project
+func isConfigured(services []config.ServiceConfig, containerName, project string) bool {
+	for _, svc := range services {
+		if svc.ContainerName == containerName || (project != "" && svc.ComposeProject == project) {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceFromLabels builds a service entry from a container's home-run.* labels.
+// Config paths are read from the local disk, so the home-run.configs label is
+// ignored for containers on a remote docker_host.
+func serviceFromLabels(dockerHost, containerName string, labels map[string]string, ports []types.Port) config.ServiceConfig {
+	svc := config.ServiceConfig{
+		Name:          labels[LabelName],
+		URL:           labels[LabelURL],
+		Backend:       "docker",
+		ContainerName: containerName,
+		DockerHost:    dockerHost,
+	}
+	if svc.Name == "" {
+		svc.Name = containerName
+	}
+	if svc.URL == "" {
+		svc.URL = defaultDiscoveryURL
+	}
+
+	if port, err := strconv.Atoi(labels[LabelPort]); err == nil {
+		svc.Port = port
+	} else {
+		svc.Port = firstPort(ports)
+	}
+
+	if dockerHost != "" {
+		return svc
+	}
+	for _, path := range strings.Split(labels[LabelConfigs], ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			svc.Configs = append(svc.Configs, path)
+		}
+	}
+
+	return svc
+}
+
+// firstPort returns the lowest published port, or the lowest exposed port if none is published
+func firstPort(ports []types.Port) int {
+	published, exposed := 0, 0
+	for _, p := range ports {
+		if p.PublicPort != 0 && (published == 0 || int(p.PublicPort) < published) {
+			published = int(p.PublicPort)
+		}
+		if p.PrivatePort != 0 && (exposed == 0 || int(p.PrivatePort) < exposed) {
+			exposed = int(p.PrivatePort)
+		}
+	}
+	if published != 0 {
+		return published
+	}
+	return exposed
+}
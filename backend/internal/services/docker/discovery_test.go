@@ -0,0 +1,67 @@
+package docker
+
+import (
+	"testing"
+
+	"home-run-backend/internal/config"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceFromLabels(t *testing.T) {
+	svc := serviceFromLabels("", "jellyfin", map[string]string{
+		LabelEnable:  "true",
+		LabelName:    "Jellyfin",
+		LabelPort:    "8096",
+		LabelURL:     "https://media.lan",
+		LabelConfigs: "/opt/jellyfin/system.xml, /opt/jellyfin/network.xml,",
+	}, nil)
+
+	assert.Equal(t, "Jellyfin", svc.Name)
+	assert.Equal(t, "https://media.lan", svc.URL)
+	assert.Equal(t, 8096, svc.Port)
+	assert.Equal(t, "docker", svc.Backend)
+	assert.Equal(t, "jellyfin", svc.ContainerName)
+	assert.Equal(t, []string{"/opt/jellyfin/system.xml", "/opt/jellyfin/network.xml"}, svc.Configs)
+}
+
+func TestServiceFromLabels_Defaults(t *testing.T) {
+	svc := serviceFromLabels("", "sonarr", map[string]string{LabelEnable: "true"}, []types.Port{
+		{PrivatePort: 9898},
+		{PrivatePort: 8989, PublicPort: 18989},
+	})
+
+	assert.Equal(t, "sonarr", svc.Name)
+	assert.Equal(t, defaultDiscoveryURL, svc.URL)
+	assert.Equal(t, 18989, svc.Port)
+	assert.Empty(t, svc.Configs)
+}
+
+func TestServiceFromLabels_RemoteHostIgnoresConfigs(t *testing.T) {
+	svc := serviceFromLabels("ssh://nas", "jellyfin", map[string]string{
+		LabelEnable:  "true",
+		LabelConfigs: "/etc/shadow",
+	}, nil)
+
+	assert.Equal(t, "ssh://nas", svc.DockerHost)
+	assert.Empty(t, svc.Configs)
+}
+
+func TestFirstPort(t *testing.T) {
+	assert.Equal(t, 0, firstPort(nil))
+	assert.Equal(t, 6379, firstPort([]types.Port{{PrivatePort: 16379}, {PrivatePort: 6379}}))
+	assert.Equal(t, 80, firstPort([]types.Port{{PrivatePort: 8080, PublicPort: 443}, {PrivatePort: 8081, PublicPort: 80}}))
+}
+
+func TestIsConfigured(t *testing.T) {
+	static := []config.ServiceConfig{
+		{Name: "Bazarr", ContainerName: "bazarr"},
+		{Name: "Maps", ComposeProject: "maps"},
+	}
+
+	assert.True(t, isConfigured(static, "bazarr", ""))
+	assert.True(t, isConfigured(static, "maps-web-1", "maps"))
+	assert.False(t, isConfigured(static, "radarr", ""))
+	assert.False(t, isConfigured(static, "radarr", "media"))
+}
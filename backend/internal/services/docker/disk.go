@@ -0,0 +1,282 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/logger"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrNothingToPrune is returned when a prune selects no kind of data
+var ErrNothingToPrune = errors.New("nothing selected to prune")
+
+// DiskUsage is the space a daemon uses, by kind of data
+type DiskUsage struct {
+	DockerHost     string          `json:"dockerHost"` // key in docker_hosts, empty for the local daemon
+	Images         DiskCategory    `json:"images"`
+	Containers     DiskCategory    `json:"containers"`
+	Volumes        DiskCategory    `json:"volumes"`
+	BuildCache     DiskCategory    `json:"buildCache"`
+	DanglingImages []DanglingImage `json:"danglingImages"`
+	UnusedVolumes  []UnusedVolume  `json:"unusedVolumes"`
+}
+
+// DiskCategory is the usage of one kind of data, as shown by docker system df
+type DiskCategory struct {
+	Count            int   `json:"count"`
+	Active           int   `json:"active"` // in use by a container
+	SizeBytes        int64 `json:"sizeBytes"`
+	ReclaimableBytes int64 `json:"reclaimableBytes"`
+}
+
+// DanglingImage is an untagged image no tag points to anymore
+type DanglingImage struct {
+	ID        string    `json:"id"`
+	SizeBytes int64     `json:"sizeBytes"`
+	Created   time.Time `json:"created"`
+}
+
+// UnusedVolume is a volume no container uses
+type UnusedVolume struct {
+	Name      string `json:"name"`
+	Driver    string `json:"driver"`
+	SizeBytes int64  `json:"sizeBytes"` // -1 if the driver doesn't report it
+	Anonymous bool   `json:"anonymous"` // created for a single container; only these are pruned
+}
+
+// PruneOptions selects what to prune
+type PruneOptions struct {
+	Containers bool `json:"containers"` // stopped containers no service monitors
+	Images     bool `json:"images"`     // dangling images
+	Volumes    bool `json:"volumes"`    // unused anonymous volumes
+	BuildCache bool `json:"buildCache"` // unused build cache
+}
+
+// PruneReport is what a prune removed
+type PruneReport struct {
+	Containers     int    `json:"containers"`
+	Images         int    `json:"images"`
+	Volumes        int    `json:"volumes"`
+	BuildCache     int    `json:"buildCache"`
+	ReclaimedBytes uint64 `json:"reclaimedBytes"`
+}
+
+// anonymousVolumeLabel marks volumes docker created for a container's VOLUME
+const anonymousVolumeLabel = "com.docker.volume.anonymous"
+
+// DiskUsage returns the daemon's disk usage from /system/df
+func (c *Client) DiskUsage(ctx context.Context) (*DiskUsage, error) {
+	df, err := c.cli.DiskUsage(ctx, types.DiskUsageOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disk usage: %w", err)
+	}
+	return diskUsageFrom(df), nil
+}
+
+// diskUsageFrom summarises a /system/df response the way docker system df does
+func diskUsageFrom(df types.DiskUsage) *DiskUsage {
+	usage := &DiskUsage{
+		DanglingImages: []DanglingImage{},
+		UnusedVolumes:  []UnusedVolume{},
+	}
+
+	// Layers are shared between images, so the total is the layer size rather than a sum
+	usage.Images.SizeBytes = df.LayersSize
+	for _, img := range df.Images {
+		if img == nil {
+			continue
+		}
+		usage.Images.Count++
+		if img.Containers > 0 {
+			usage.Images.Active++
+		} else if img.SharedSize != -1 && img.Size > img.SharedSize {
+			usage.Images.ReclaimableBytes += img.Size - img.SharedSize
+		}
+		if isDangling(img.RepoTags) {
+			usage.DanglingImages = append(usage.DanglingImages, DanglingImage{
+				ID:        shortID(strings.TrimPrefix(img.ID, "sha256:")),
+				SizeBytes: img.Size,
+				Created:   time.Unix(img.Created, 0).UTC(),
+			})
+		}
+	}
+
+	for _, ctr := range df.Containers {
+		if ctr == nil {
+			continue
+		}
+		usage.Containers.Count++
+		usage.Containers.SizeBytes += ctr.SizeRw
+		if ctr.State == "running" {
+			usage.Containers.Active++
+		} else {
+			usage.Containers.ReclaimableBytes += ctr.SizeRw
+		}
+	}
+
+	for _, vol := range df.Volumes {
+		if vol == nil {
+			continue
+		}
+		usage.Volumes.Count++
+		size, refs := int64(-1), int64(0)
+		if vol.UsageData != nil {
+			size, refs = vol.UsageData.Size, vol.UsageData.RefCount
+		}
+		if size > 0 {
+			usage.Volumes.SizeBytes += size
+		}
+		if refs > 0 {
+			usage.Volumes.Active++
+			continue
+		}
+		if size > 0 {
+			usage.Volumes.ReclaimableBytes += size
+		}
+		_, anonymous := vol.Labels[anonymousVolumeLabel]
+		usage.UnusedVolumes = append(usage.UnusedVolumes, UnusedVolume{
+			Name:      vol.Name,
+			Driver:    vol.Driver,
+			SizeBytes: size,
+			Anonymous: anonymous,
+		})
+	}
+
+	for _, cache := range df.BuildCache {
+		if cache == nil {
+			continue
+		}
+		usage.BuildCache.Count++
+		if cache.Shared {
+			continue
+		}
+		usage.BuildCache.SizeBytes += cache.Size
+		if cache.InUse {
+			usage.BuildCache.Active++
+		} else {
+			usage.BuildCache.ReclaimableBytes += cache.Size
+		}
+	}
+
+	// Largest first, that's what is worth removing
+	sort.Slice(usage.DanglingImages, func(i, j int) bool {
+		return usage.DanglingImages[i].SizeBytes > usage.DanglingImages[j].SizeBytes
+	})
+	sort.Slice(usage.UnusedVolumes, func(i, j int) bool {
+		a, b := usage.UnusedVolumes[i], usage.UnusedVolumes[j]
+		if a.SizeBytes != b.SizeBytes {
+			return a.SizeBytes > b.SizeBytes
+		}
+		return a.Name < b.Name
+	})
+
+	return usage
+}
+
+// prunableContainers returns the stopped containers that no configured or
+// discovered service monitors
+func prunableContainers(stopped []types.Container, monitored []config.ServiceConfig) []types.Container {
+	var result []types.Container
+	for _, ctr := range stopped {
+		if isConfigured(monitored, containerName(ctr), ctr.Labels[LabelComposeProject]) {
+			continue
+		}
+		result = append(result, ctr)
+	}
+	return result
+}
+
+// containerName returns a listed container's name without the leading slash
+func containerName(ctr types.Container) string {
+	if len(ctr.Names) == 0 {
+		return ctr.ID
+	}
+	return strings.TrimPrefix(ctr.Names[0], "/")
+}
+
+// isDangling reports whether an image has no tag
+func isDangling(repoTags []string) bool {
+	for _, tag := range repoTags {
+		if tag != "<none>:<none>" {
+			return false
+		}
+	}
+	return true
+}
+
+// Prune removes the selected kinds of unused data. Only dangling images and
+// anonymous volumes are removed, never tagged images or named volumes, and
+// stopped containers of monitored services are kept.
+func (c *Client) Prune(ctx context.Context, opts PruneOptions, monitored []config.ServiceConfig) (*PruneReport, error) {
+	if !opts.Containers && !opts.Images && !opts.Volumes && !opts.BuildCache {
+		return nil, ErrNothingToPrune
+	}
+
+	report := &PruneReport{}
+	if opts.Containers {
+		// ContainersPrune can't exclude containers, so remove them one by one
+		stopped, err := c.cli.ContainerList(ctx, container.ListOptions{
+			All:  true,
+			Size: true,
+			Filters: filters.NewArgs(
+				filters.Arg("status", "created"),
+				filters.Arg("status", "exited"),
+				filters.Arg("status", "dead"),
+			),
+		})
+		if err != nil {
+			return report, fmt.Errorf("failed to list stopped containers: %w", err)
+		}
+		for _, ctr := range prunableContainers(stopped, monitored) {
+			if err := c.cli.ContainerRemove(ctx, ctr.ID, container.RemoveOptions{}); err != nil {
+				return report, fmt.Errorf("failed to remove container %s: %w", containerName(ctr), err)
+			}
+			report.Containers++
+			report.ReclaimedBytes += uint64(max(ctr.SizeRw, 0))
+		}
+	}
+	if opts.Images {
+		res, err := c.cli.ImagesPrune(ctx, filters.NewArgs(filters.Arg("dangling", "true")))
+		if err != nil {
+			return report, fmt.Errorf("failed to prune images: %w", err)
+		}
+		report.Images = len(res.ImagesDeleted)
+		report.ReclaimedBytes += res.SpaceReclaimed
+	}
+	if opts.Volumes {
+		// Older daemons prune named volumes too unless filtered
+		res, err := c.cli.VolumesPrune(ctx, filters.NewArgs(filters.Arg("label", anonymousVolumeLabel)))
+		if err != nil {
+			return report, fmt.Errorf("failed to prune volumes: %w", err)
+		}
+		report.Volumes = len(res.VolumesDeleted)
+		report.ReclaimedBytes += res.SpaceReclaimed
+	}
+	if opts.BuildCache {
+		res, err := c.cli.BuildCachePrune(ctx, types.BuildCachePruneOptions{})
+		if err != nil {
+			return report, fmt.Errorf("failed to prune build cache: %w", err)
+		}
+		report.BuildCache = len(res.CachesDeleted)
+		report.ReclaimedBytes += res.SpaceReclaimed
+	}
+
+	logger.WithFields(logrus.Fields{
+		"containers":  report.Containers,
+		"images":      report.Images,
+		"volumes":     report.Volumes,
+		"build_cache": report.BuildCache,
+		"reclaimed":   report.ReclaimedBytes,
+	}).Info("Pruned docker data")
+	return report, nil
+}
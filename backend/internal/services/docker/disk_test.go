@@ -0,0 +1,92 @@
+package docker
+
+import (
+	"context"
+	"testing"
+
+	"home-run-backend/internal/config"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskUsageFrom(t *testing.T) {
+	df := types.DiskUsage{
+		LayersSize: 3000,
+		Images: []*image.Summary{
+			{ID: "sha256:aaaaaaaaaaaaaaaa", RepoTags: []string{"nginx:1.27"}, Size: 1000, SharedSize: 200, Containers: 1},
+			{ID: "sha256:bbbbbbbbbbbbbbbb", RepoTags: []string{"app:old"}, Size: 800, SharedSize: 200, Containers: 0},
+			{ID: "sha256:cccccccccccccccc", RepoTags: nil, Size: 500, SharedSize: 0, Containers: 0, Created: 1700000000},
+			{ID: "sha256:dddddddddddddddd", RepoTags: []string{"<none>:<none>"}, Size: 700, SharedSize: -1, Containers: 0},
+		},
+		Containers: []*types.Container{
+			{State: "running", SizeRw: 10},
+			{State: "exited", SizeRw: 40},
+		},
+		Volumes: []*volume.Volume{
+			{Name: "db-data", Driver: "local", UsageData: &volume.UsageData{Size: 5000, RefCount: 1}},
+			{Name: "old-data", Driver: "local", UsageData: &volume.UsageData{Size: 300, RefCount: 0}},
+			{Name: "0123abcd", Driver: "local", Labels: map[string]string{anonymousVolumeLabel: ""}, UsageData: &volume.UsageData{Size: 900, RefCount: 0}},
+			{Name: "nfs", Driver: "nfs", UsageData: &volume.UsageData{Size: -1, RefCount: 0}},
+		},
+		BuildCache: []*types.BuildCache{
+			{Size: 100, InUse: true},
+			{Size: 250},
+			{Size: 999, Shared: true},
+		},
+	}
+
+	usage := diskUsageFrom(df)
+
+	assert.Equal(t, DiskCategory{Count: 4, Active: 1, SizeBytes: 3000, ReclaimableBytes: 600 + 500}, usage.Images)
+	assert.Equal(t, DiskCategory{Count: 2, Active: 1, SizeBytes: 50, ReclaimableBytes: 40}, usage.Containers)
+	assert.Equal(t, DiskCategory{Count: 4, Active: 1, SizeBytes: 6200, ReclaimableBytes: 1200}, usage.Volumes)
+	assert.Equal(t, DiskCategory{Count: 3, Active: 1, SizeBytes: 350, ReclaimableBytes: 250}, usage.BuildCache)
+
+	require.Len(t, usage.DanglingImages, 2)
+	assert.Equal(t, "dddddddddddd", usage.DanglingImages[0].ID)
+	assert.Equal(t, "cccccccccccc", usage.DanglingImages[1].ID)
+	assert.EqualValues(t, 1700000000, usage.DanglingImages[1].Created.Unix())
+
+	require.Len(t, usage.UnusedVolumes, 3)
+	assert.Equal(t, UnusedVolume{Name: "0123abcd", Driver: "local", SizeBytes: 900, Anonymous: true}, usage.UnusedVolumes[0])
+	assert.Equal(t, "old-data", usage.UnusedVolumes[1].Name)
+	assert.False(t, usage.UnusedVolumes[1].Anonymous)
+	assert.EqualValues(t, -1, usage.UnusedVolumes[2].SizeBytes)
+}
+
+func TestDiskUsageFrom_Empty(t *testing.T) {
+	usage := diskUsageFrom(types.DiskUsage{})
+	assert.NotNil(t, usage.DanglingImages)
+	assert.NotNil(t, usage.UnusedVolumes)
+	assert.Zero(t, usage.Images.Count)
+}
+
+func TestPrune_NothingSelected(t *testing.T) {
+	_, err := (&Client{}).Prune(context.Background(), PruneOptions{}, nil)
+	assert.ErrorIs(t, err, ErrNothingToPrune)
+}
+
+func TestPrunableContainers(t *testing.T) {
+	stopped := []types.Container{
+		{ID: "1", Names: []string{"/plex"}},
+		{ID: "2", Names: []string{"/maps-web-1"}, Labels: map[string]string{LabelComposeProject: "maps"}},
+		{ID: "3", Names: []string{"/bazarr"}},
+		{ID: "4", Names: []string{"/old-job"}},
+		{ID: "5", Names: []string{"/other-web-1"}, Labels: map[string]string{LabelComposeProject: "other"}},
+	}
+	monitored := []config.ServiceConfig{
+		{Name: "Plex", ContainerName: "plex"},
+		{Name: "Maps", ComposeProject: "maps"},
+		{Name: "bazarr", ContainerName: "bazarr"}, // discovered
+	}
+
+	var ids []string
+	for _, ctr := range prunableContainers(stopped, monitored) {
+		ids = append(ids, ctr.ID)
+	}
+	assert.Equal(t, []string{"4", "5"}, ids)
+}
@@ -0,0 +1,162 @@
+package docker
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"home-run-backend/internal/logger"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/sirupsen/logrus"
+)
+
+// eventRetryDelay is how long to wait before resubscribing to a failed event stream
+const eventRetryDelay = 5 * time.Second
+
+// watchedActions are the container events that change a container's status
+var watchedActions = []events.Action{
+	events.ActionStart,
+	events.ActionRestart,
+	events.ActionDie,
+	events.ActionKill, // tells requested exits from crashes for auto-heal
+	events.ActionOOM,
+	events.ActionPause,
+	events.ActionUnPause,
+	events.ActionDestroy,
+	events.ActionHealthStatus,
+}
+
+// Events subscribes to container lifecycle events on the daemon
+func (c *Client) Events(ctx context.Context) (<-chan events.Message, <-chan error) {
+	args := filters.NewArgs(filters.Arg("type", string(events.ContainerEventType)))
+	for _, action := range watchedActions {
+		args.Add("event", string(action))
+	}
+	return c.cli.Events(ctx, events.ListOptions{Filters: args})
+}
+
+// watchEvents applies container events to the cache as they arrive,
+// resubscribing and resyncing whenever the stream is interrupted
+func (sc *StatsCollector) watchEvents(ctx context.Context) {
+	for {
+		msgs, errs := sc.client.Events(ctx)
+		if !sc.consumeEvents(ctx, msgs, errs) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventRetryDelay):
+		}
+
+		// Pick up anything that changed while we were disconnected
+		sc.collectAll(ctx)
+	}
+}
+
+// consumeEvents handles messages until the stream fails, returning false
+// once the collector is stopped
+func (sc *StatsCollector) consumeEvents(ctx context.Context, msgs <-chan events.Message, errs <-chan error) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case msg := <-msgs:
+			sc.handleEvent(msg)
+		case err := <-errs:
+			if ctx.Err() != nil {
+				return false
+			}
+			if !sc.client.Connected() {
+				logger.WithField("error", err.Error()).Debug("Docker event stream unavailable, daemon unreachable")
+				return true
+			}
+			logger.WithField("error", err.Error()).Warn("Docker event stream interrupted, reconnecting")
+			return true
+		}
+	}
+}
+
+// handleEvent updates the cached status of a tracked container
+func (sc *StatsCollector) handleEvent(msg events.Message) {
+	name := msg.Actor.Attributes["name"]
+	sc.healer.observeEvent(msg)
+
+	if msg.Action == events.ActionOOM {
+		logger.WithField("container", name).Warn("Container ran out of memory")
+	}
+
+	sc.mu.Lock()
+	prev, tracked := sc.containers[name]
+	if !tracked {
+		sc.mu.Unlock()
+		return
+	}
+	info, changed := applyEvent(prev.info, msg)
+	if changed {
+		// Restart count and probe output are only available from an inspection
+		sc.containers[name] = containerState{info: info, updated: time.Now(), stale: true}
+	}
+	sc.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	cached := &CachedStats{
+		ContainerInfo: info,
+		LastUpdate:    time.Now(),
+	}
+	// Keep the last resource usage until the next poll refreshes it
+	if old := sc.GetCachedStats(name); old != nil && info.Status == "RUNNING" {
+		cached.Usage = old.Usage
+	}
+	sc.cache.Set(name, cached)
+
+	logger.WithFields(logrus.Fields{
+		"container": name,
+		"event":     msg.Action,
+		"status":    info.Status,
+	}).Info("Container status changed")
+}
+
+// applyEvent returns the container info after an event, and whether it changed
+func applyEvent(info ContainerInfo, msg events.Message) (ContainerInfo, bool) {
+	at := time.Unix(0, msg.TimeNano)
+
+	switch {
+	case msg.Action == events.ActionStart || msg.Action == events.ActionRestart:
+		// The container may have been recreated under the same name
+		info.ID = shortID(msg.Actor.ID)
+		info.Status, info.State = "RUNNING", "running"
+		info.StartedAt = at
+		info.OOMKilled = false
+		info.clearHealth()
+	case msg.Action == events.ActionUnPause:
+		info.Status, info.State = "RUNNING", "running"
+	case msg.Action == events.ActionPause:
+		info.Status, info.State = "MAINTENANCE", "paused"
+	case msg.Action == events.ActionDie:
+		info.Status, info.State = "STOPPED", "exited"
+		info.StartedAt = time.Time{}
+		info.clearHealth()
+	case msg.Action == events.ActionOOM:
+		// Followed by die if the container's main process was killed
+		info.OOMKilled = true
+	case msg.Action == events.ActionDestroy:
+		info.Status, info.State = "ERROR", ""
+		info.StartedAt = time.Time{}
+		info.clearHealth()
+	case strings.HasPrefix(string(msg.Action), string(events.ActionHealthStatus)+":"):
+		info.Health = strings.TrimSpace(strings.TrimPrefix(string(msg.Action), string(events.ActionHealthStatus)+":"))
+		if info.Health == "healthy" {
+			info.FailingStreak = 0
+		}
+	default:
+		return info, false
+	}
+	return info, true
+}
@@ -0,0 +1,96 @@
+package docker
+
+import (
+	"testing"
+	"time"
+
+	"home-run-backend/internal/cache"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func containerEvent(action events.Action, name string, at time.Time) events.Message {
+	return events.Message{
+		Type:   events.ContainerEventType,
+		Action: action,
+		Actor: events.Actor{
+			ID:         "0123456789abcdef0123",
+			Attributes: map[string]string{"name": name},
+		},
+		TimeNano: at.UnixNano(),
+	}
+}
+
+func TestApplyEvent(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	running := ContainerInfo{ID: "old", Status: "RUNNING", State: "running", Health: "healthy", StartedAt: at.Add(-time.Hour)}
+
+	tests := []struct {
+		name    string
+		action  events.Action
+		status  string
+		health  string
+		started time.Time
+		changed bool
+	}{
+		{"start", events.ActionStart, "RUNNING", "", at, true},
+		{"restart", events.ActionRestart, "RUNNING", "", at, true},
+		{"die", events.ActionDie, "STOPPED", "", time.Time{}, true},
+		{"pause", events.ActionPause, "MAINTENANCE", "healthy", running.StartedAt, true},
+		{"unpause", events.ActionUnPause, "RUNNING", "healthy", running.StartedAt, true},
+		{"destroy", events.ActionDestroy, "ERROR", "", time.Time{}, true},
+		{"unhealthy", events.ActionHealthStatusUnhealthy, "RUNNING", "unhealthy", running.StartedAt, true},
+		{"oom", events.ActionOOM, "RUNNING", "healthy", running.StartedAt, true},
+		{"exec", events.ActionExecStart, "RUNNING", "healthy", running.StartedAt, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, changed := applyEvent(running, containerEvent(tt.action, "web", at))
+			assert.Equal(t, tt.changed, changed)
+			assert.Equal(t, tt.status, info.Status)
+			assert.Equal(t, tt.health, info.Health)
+			assert.True(t, tt.started.Equal(info.StartedAt))
+		})
+	}
+}
+
+func TestApplyEvent_OOMKilled(t *testing.T) {
+	info, _ := applyEvent(ContainerInfo{Status: "RUNNING"}, containerEvent(events.ActionOOM, "web", time.Now()))
+	assert.True(t, info.OOMKilled)
+
+	info, _ = applyEvent(info, containerEvent(events.ActionStart, "web", time.Now()))
+	assert.False(t, info.OOMKilled)
+}
+
+func TestApplyEvent_StartTracksNewContainerID(t *testing.T) {
+	info, _ := applyEvent(ContainerInfo{ID: "old"}, containerEvent(events.ActionStart, "web", time.Now()))
+	assert.Equal(t, "0123456789ab", info.ID)
+}
+
+func TestHandleEvent(t *testing.T) {
+	sc := NewStatsCollector(nil, cache.New(time.Minute), nil, time.Second)
+	sc.containers["web"] = containerState{info: ContainerInfo{ID: "0123456789ab", Status: "RUNNING", State: "running"}}
+	sc.cache.Set("web", &CachedStats{ContainerInfo: ContainerInfo{Status: "RUNNING"}, Usage: Usage{CPUPercent: 12, MemoryMB: 64}})
+
+	sc.handleEvent(containerEvent(events.ActionDie, "web", time.Now()))
+
+	cached := sc.GetCachedStats("web")
+	require.NotNil(t, cached)
+	assert.Equal(t, "STOPPED", cached.Status)
+	assert.Zero(t, cached.CPUPercent)
+
+	sc.handleEvent(containerEvent(events.ActionStart, "web", time.Now()))
+	assert.Equal(t, "RUNNING", sc.GetCachedStats("web").Status)
+}
+
+func TestHandleEvent_IgnoresUntrackedContainers(t *testing.T) {
+	sc := NewStatsCollector(nil, cache.New(time.Minute), nil, time.Second)
+
+	sc.handleEvent(containerEvent(events.ActionDie, "other", time.Now()))
+
+	assert.Nil(t, sc.GetCachedStats("other"))
+	assert.Empty(t, sc.containers)
+}
@@ -0,0 +1,285 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/logger"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// healHistorySize is the number of auto-heal events kept for the API
+	healHistorySize = 100
+	// healTimeout bounds an automatic restart, including the stop timeout
+	healTimeout = 2 * time.Minute
+	// killWindow is how long after a kill event an exit counts as requested
+	// (docker stop, docker kill or a restart) rather than as a crash
+	killWindow = 2 * time.Minute
+)
+
+// Auto-heal actions
+const (
+	HealActionRestart = "restart"
+	HealActionSkip    = "skip" // max_restarts reached within the window
+)
+
+// HealEvent is an automatic action taken on a container
+type HealEvent struct {
+	Time       time.Time `json:"time"`
+	Service    string    `json:"service"`
+	Container  string    `json:"container"`
+	DockerHost string    `json:"dockerHost,omitempty"`
+	Reason     string    `json:"reason"` // unhealthy, or exited with a non-zero code
+	Action     string    `json:"action"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// healTarget is a container with an auto_heal policy
+type healTarget struct {
+	service string
+	host    string
+	policy  config.AutoHealConfig
+}
+
+// healState is the auto-heal bookkeeping of one container
+type healState struct {
+	restarts []time.Time // automatic restarts within the window, oldest first
+	killed   time.Time   // last kill event
+	healing  bool        // a restart is in progress
+	limited  bool        // a skip was recorded since the last restart
+}
+
+// Healer restarts the containers of configured services with auto_heal when
+// they become unhealthy or exit unexpectedly, within each service's restart
+// limits. Discovered containers are never healed.
+type Healer struct {
+	client  *Client
+	targets map[string]healTarget // by container name
+	state   map[string]*healState
+	history []HealEvent // oldest first
+	mu      sync.Mutex
+}
+
+// newHealer returns a healer for the services with auto_heal, or nil if there are none
+func newHealer(client *Client, services []config.ServiceConfig) *Healer {
+	targets := make(map[string]healTarget)
+	for _, svc := range services {
+		if svc.AutoHeal == nil || svc.ContainerName == "" {
+			continue
+		}
+		targets[svc.ContainerName] = healTarget{
+			service: svc.Name,
+			host:    svc.DockerHost,
+			policy:  *svc.AutoHeal,
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	return &Healer{
+		client:  client,
+		targets: targets,
+		state:   make(map[string]*healState),
+	}
+}
+
+// observeEvent heals a container after a health_status: unhealthy event, or
+// a die event with a non-zero exit code that no kill event asked for
+func (h *Healer) observeEvent(msg events.Message) {
+	if h == nil {
+		return
+	}
+	name := msg.Actor.Attributes["name"]
+	target, ok := h.targets[name]
+	if !ok {
+		return
+	}
+	at := time.Unix(0, msg.TimeNano)
+
+	switch {
+	case msg.Action == events.ActionKill:
+		h.mu.Lock()
+		h.stateFor(name).killed = at
+		h.mu.Unlock()
+	case msg.Action == events.ActionDie:
+		exitCode := msg.Actor.Attributes["exitCode"]
+		if target.policy.OnlyWhenUnhealthy || exitCode == "" || exitCode == "0" {
+			return
+		}
+		h.mu.Lock()
+		killed := h.stateFor(name).killed
+		h.mu.Unlock()
+		if at.Sub(killed) < killWindow {
+			return
+		}
+		// Inspecting the restart policy is a round trip, keep it off the event loop
+		go h.healExit(name, exitCode)
+	case msg.Action == events.ActionHealthStatus+": unhealthy":
+		h.trigger(name, "unhealthy", time.Now())
+	}
+}
+
+// healExit restarts a crashed container unless its restart policy already
+// does, so the two don't restart it twice. Docker never restarts unhealthy
+// containers, so those are always healed.
+func (h *Healer) healExit(name, exitCode string) {
+	ctx, cancel := context.WithTimeout(context.Background(), healTimeout)
+	defer cancel()
+
+	restarts, err := h.client.restartPolicyRestarts(ctx, name)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"container": name,
+			"error":     err.Error(),
+		}).Warn("Failed to read restart policy, auto-healing anyway")
+	}
+	if restarts {
+		logger.WithField("container", name).Debug("Restart policy restarts the container, skipping auto-heal")
+		return
+	}
+	h.trigger(name, "exited with code "+exitCode, time.Now())
+}
+
+// observe heals a polled container that is still unhealthy, e.g. one that
+// was unhealthy before Home-Run started or whose restart didn't help
+func (h *Healer) observe(name string, info ContainerInfo) {
+	if h == nil {
+		return
+	}
+	if _, ok := h.targets[name]; !ok || info.Status != "RUNNING" || info.Health != "unhealthy" {
+		return
+	}
+	h.trigger(name, "unhealthy", time.Now())
+}
+
+// trigger restarts a container in the background unless a restart is already
+// running, the cooldown hasn't passed or max_restarts is reached
+func (h *Healer) trigger(name, reason string, now time.Time) {
+	target := h.targets[name]
+	policy := target.policy
+
+	h.mu.Lock()
+	state := h.stateFor(name)
+	if state.healing {
+		h.mu.Unlock()
+		return
+	}
+	if n := len(state.restarts); n > 0 && now.Sub(state.restarts[n-1]) < policy.Cooldown {
+		h.mu.Unlock()
+		return
+	}
+
+	recent := state.restarts[:0]
+	for _, at := range state.restarts {
+		if now.Sub(at) < policy.Window {
+			recent = append(recent, at)
+		}
+	}
+	state.restarts = recent
+
+	if len(state.restarts) >= policy.MaxRestarts {
+		// Record giving up once rather than on every poll
+		if !state.limited {
+			state.limited = true
+			h.record(HealEvent{
+				Time:       now,
+				Service:    target.service,
+				Container:  name,
+				DockerHost: target.host,
+				Reason:     reason,
+				Action:     HealActionSkip,
+				Error:      fmt.Sprintf("max_restarts reached (%d per %s)", policy.MaxRestarts, policy.Window),
+			})
+			logger.WithFields(logrus.Fields{
+				"container":    name,
+				"reason":       reason,
+				"max_restarts": policy.MaxRestarts,
+				"window":       policy.Window,
+			}).Warn("Auto-heal restart limit reached, leaving container alone")
+		}
+		h.mu.Unlock()
+		return
+	}
+
+	state.limited = false
+	state.healing = true
+	state.restarts = append(state.restarts, now)
+	h.mu.Unlock()
+
+	go h.restart(name, target, reason)
+}
+
+// restart restarts a container and records the outcome
+func (h *Healer) restart(name string, target healTarget, reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), healTimeout)
+	defer cancel()
+
+	logger.WithFields(logrus.Fields{
+		"container": name,
+		"reason":    reason,
+	}).Warn("Auto-healing container")
+
+	_, err := h.client.ContainerAction(ctx, name, ActionRestart)
+
+	event := HealEvent{
+		Time:       time.Now(),
+		Service:    target.service,
+		Container:  name,
+		DockerHost: target.host,
+		Reason:     reason,
+		Action:     HealActionRestart,
+		Success:    err == nil,
+	}
+	if err != nil {
+		event.Error = err.Error()
+		logger.WithFields(logrus.Fields{
+			"container": name,
+			"error":     err.Error(),
+		}).Error("Auto-heal restart failed")
+	}
+
+	h.mu.Lock()
+	h.stateFor(name).healing = false
+	h.record(event)
+	h.mu.Unlock()
+}
+
+// stateFor returns a container's bookkeeping, creating it if needed. Callers hold mu.
+func (h *Healer) stateFor(name string) *healState {
+	state, ok := h.state[name]
+	if !ok {
+		state = &healState{}
+		h.state[name] = state
+	}
+	return state
+}
+
+// record appends an event, dropping the oldest beyond healHistorySize. Callers hold mu.
+func (h *Healer) record(event HealEvent) {
+	h.history = append(h.history, event)
+	if len(h.history) > healHistorySize {
+		h.history = h.history[len(h.history)-healHistorySize:]
+	}
+}
+
+// History returns the recorded auto-heal events, newest first
+func (h *Healer) History() []HealEvent {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	result := make([]HealEvent, len(h.history))
+	for i, event := range h.history {
+		result[len(h.history)-1-i] = event
+	}
+	return result
+}
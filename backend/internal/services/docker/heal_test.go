@@ -0,0 +1,164 @@
+package docker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"home-run-backend/internal/config"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noRestartPolicy is the inspection of a "web" container that docker never restarts
+const noRestartPolicy = `{"Id":"abcdef0123456789","HostConfig":{"RestartPolicy":{"Name":"no"}},"State":{"Status":"exited"}}`
+
+// restartDaemon serves a single container "web" with the given inspection and counts restarts
+func restartDaemon(t *testing.T, restarts *atomic.Int32, inspect string) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_ping"):
+			w.Header().Set("Api-Version", "1.45")
+			_, _ = w.Write([]byte("OK"))
+		case strings.HasSuffix(r.URL.Path, "/containers/json"):
+			_, _ = w.Write([]byte(`[{"Id":"abcdef0123456789","Names":["/web"],"State":"running"}]`))
+		case strings.HasSuffix(r.URL.Path, "/containers/web/json"):
+			_, _ = w.Write([]byte(inspect))
+		case strings.HasSuffix(r.URL.Path, "/restart") && r.Method == http.MethodPost:
+			restarts.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return openTestClient(t, server)
+}
+
+// webEvent is an event of the "web" container with extra attributes such as exitCode
+func webEvent(action events.Action, at time.Time, attrs map[string]string) events.Message {
+	msg := containerEvent(action, "web", at)
+	for k, v := range attrs {
+		msg.Actor.Attributes[k] = v
+	}
+	return msg
+}
+
+func healerFor(c *Client, policy config.AutoHealConfig) *Healer {
+	return newHealer(c, []config.ServiceConfig{
+		{Name: "Web", ContainerName: "web", AutoHeal: &policy},
+		{Name: "Other", ContainerName: "other"},
+	})
+}
+
+func TestNewHealer_WithoutAutoHeal(t *testing.T) {
+	assert.Nil(t, newHealer(nil, []config.ServiceConfig{{Name: "Web", ContainerName: "web"}}))
+
+	// A nil healer ignores everything
+	var h *Healer
+	h.observeEvent(webEvent(events.ActionHealthStatus+": unhealthy", time.Now(), nil))
+	h.observe("web", ContainerInfo{Status: "RUNNING", Health: "unhealthy"})
+	assert.Empty(t, h.History())
+}
+
+func TestHealer_RestartsWithinLimits(t *testing.T) {
+	var restarts atomic.Int32
+	h := healerFor(restartDaemon(t, &restarts, noRestartPolicy), config.AutoHealConfig{MaxRestarts: 2, Window: time.Hour})
+	now := time.Now()
+
+	// Clean exits and exits after a kill (docker stop, restarts) are not crashes
+	h.observeEvent(webEvent(events.ActionDie, now, map[string]string{"exitCode": "0"}))
+	h.observeEvent(webEvent(events.ActionKill, now, map[string]string{"signal": "15"}))
+	h.observeEvent(webEvent(events.ActionDie, now.Add(time.Second), map[string]string{"exitCode": "143"}))
+	h.observe("web", ContainerInfo{Status: "RUNNING", Health: "healthy"})
+	assert.Empty(t, h.History())
+
+	h.observeEvent(webEvent(events.ActionHealthStatus+": unhealthy", now.Add(time.Minute), nil))
+	require.Eventually(t, func() bool { return len(h.History()) == 1 }, time.Second, 10*time.Millisecond)
+
+	h.observeEvent(webEvent(events.ActionDie, now.Add(10*time.Minute), map[string]string{"exitCode": "1"}))
+	require.Eventually(t, func() bool { return len(h.History()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.EqualValues(t, 2, restarts.Load())
+
+	history := h.History()
+	assert.Equal(t, HealActionRestart, history[0].Action)
+	assert.Equal(t, "exited with code 1", history[0].Reason)
+	assert.True(t, history[0].Success)
+	assert.Equal(t, "unhealthy", history[1].Reason)
+	assert.Equal(t, "Web", history[1].Service)
+
+	// The limit is recorded once, not on every poll
+	h.observe("web", ContainerInfo{Status: "RUNNING", Health: "unhealthy"})
+	h.observe("web", ContainerInfo{Status: "RUNNING", Health: "unhealthy"})
+	history = h.History()
+	require.Len(t, history, 3)
+	assert.Equal(t, HealActionSkip, history[0].Action)
+	assert.Contains(t, history[0].Error, "max_restarts reached")
+	assert.EqualValues(t, 2, restarts.Load())
+}
+
+func TestHealer_Cooldown(t *testing.T) {
+	var restarts atomic.Int32
+	h := healerFor(restartDaemon(t, &restarts, noRestartPolicy), config.AutoHealConfig{MaxRestarts: 5, Window: time.Hour, Cooldown: 10 * time.Minute})
+	now := time.Now()
+
+	h.trigger("web", "unhealthy", now)
+	require.Eventually(t, func() bool { return len(h.History()) == 1 }, time.Second, 10*time.Millisecond)
+
+	h.trigger("web", "unhealthy", now.Add(5*time.Minute))
+	assert.Len(t, h.History(), 1)
+
+	h.trigger("web", "unhealthy", now.Add(11*time.Minute))
+	require.Eventually(t, func() bool { return len(h.History()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.EqualValues(t, 2, restarts.Load())
+}
+
+func TestHealer_OnlyWhenUnhealthy(t *testing.T) {
+	var restarts atomic.Int32
+	h := healerFor(restartDaemon(t, &restarts, noRestartPolicy), config.AutoHealConfig{MaxRestarts: 5, Window: time.Hour, OnlyWhenUnhealthy: true})
+
+	h.observeEvent(webEvent(events.ActionDie, time.Now(), map[string]string{"exitCode": "1"}))
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, h.History())
+	assert.Zero(t, restarts.Load())
+}
+
+func TestHealer_LeavesExitsToRestartPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		inspect string
+		healed  bool
+	}{
+		{"no policy", noRestartPolicy, true},
+		{"always", `{"Id":"abcdef0123456789","HostConfig":{"RestartPolicy":{"Name":"always"}},"State":{"Status":"restarting","Restarting":true}}`, false},
+		{"unless-stopped", `{"Id":"abcdef0123456789","HostConfig":{"RestartPolicy":{"Name":"unless-stopped"}},"State":{"Status":"restarting","Restarting":true}}`, false},
+		{"on-failure with retries left", `{"Id":"abcdef0123456789","RestartCount":1,"HostConfig":{"RestartPolicy":{"Name":"on-failure","MaximumRetryCount":3}},"State":{"Status":"restarting","Restarting":true}}`, false},
+		{"on-failure out of retries", `{"Id":"abcdef0123456789","RestartCount":3,"HostConfig":{"RestartPolicy":{"Name":"on-failure","MaximumRetryCount":3}},"State":{"Status":"exited"}}`, true},
+		{"inspect fails", `not json`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var restarts atomic.Int32
+			h := healerFor(restartDaemon(t, &restarts, tt.inspect), config.AutoHealConfig{MaxRestarts: 5, Window: time.Hour})
+
+			h.observeEvent(webEvent(events.ActionDie, time.Now(), map[string]string{"exitCode": "1"}))
+			if tt.healed {
+				require.Eventually(t, func() bool { return len(h.History()) == 1 }, time.Second, 10*time.Millisecond)
+				assert.EqualValues(t, 1, restarts.Load())
+				return
+			}
+			time.Sleep(50 * time.Millisecond)
+			assert.Empty(t, h.History())
+			assert.Zero(t, restarts.Load())
+		})
+	}
+}
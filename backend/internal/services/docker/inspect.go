@@ -0,0 +1,257 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ContainerDetails is a curated view of a container's inspection
+type ContainerDetails struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Image         string            `json:"image"`
+	ImageID       string            `json:"imageId"`
+	Digest        string            `json:"digest,omitempty"` // repo digest the image was pulled by
+	Created       time.Time         `json:"created"`
+	Command       string            `json:"command"` // secret arguments are removed
+	Ports         []PortBinding     `json:"ports"`
+	Mounts        []Mount           `json:"mounts"`
+	Networks      []NetworkEndpoint `json:"networks"`
+	Labels        map[string]string `json:"labels"` // secret values are removed
+	RestartPolicy RestartPolicy     `json:"restartPolicy"`
+	Env           []EnvVar          `json:"env"`
+}
+
+// PortBinding is a container port and where it is published on the host
+type PortBinding struct {
+	ContainerPort int    `json:"containerPort"`
+	Protocol      string `json:"protocol"`
+	HostIP        string `json:"hostIp,omitempty"`
+	HostPort      string `json:"hostPort,omitempty"` // empty if only exposed
+}
+
+// Mount is a volume, bind mount or tmpfs
+type Mount struct {
+	Type        string `json:"type"`
+	Name        string `json:"name,omitempty"` // volume name
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	ReadOnly    bool   `json:"readOnly"`
+}
+
+// NetworkEndpoint is the container's attachment to one network
+type NetworkEndpoint struct {
+	Name       string   `json:"name"`
+	IPAddress  string   `json:"ipAddress,omitempty"`
+	IPv6       string   `json:"ipv6,omitempty"`
+	Gateway    string   `json:"gateway,omitempty"`
+	MacAddress string   `json:"macAddress,omitempty"`
+	Aliases    []string `json:"aliases,omitempty"`
+}
+
+// RestartPolicy is the container's restart policy
+type RestartPolicy struct {
+	Name              string `json:"name"`
+	MaximumRetryCount int    `json:"maximumRetryCount,omitempty"`
+}
+
+// EnvVar is an environment variable; values that look like secrets are removed
+type EnvVar struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Redacted bool   `json:"redacted,omitempty"`
+}
+
+// secretEnvPattern matches variable, flag and label names whose values should
+// not be shown
+var secretEnvPattern = regexp.MustCompile(`(?i)(pass|secret|token|key|credential|auth|private|salt|cookie|session|dsn)`)
+
+// redactedValue replaces secret values
+const redactedValue = "********"
+
+// InspectContainer returns details of a container by name
+func (c *Client) InspectContainer(ctx context.Context, containerName string) (*ContainerDetails, error) {
+	containers, err := c.ListContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, ok := containers[containerName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrContainerNotFound, containerName)
+	}
+
+	inspect, err := c.cli.ContainerInspect(ctx, info.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	details := detailsFromInspect(inspect)
+
+	// The digest is only known to the image; locally built images have none
+	if image, _, err := c.cli.ImageInspectWithRaw(ctx, inspect.Image); err == nil && len(image.RepoDigests) > 0 {
+		details.Digest = image.RepoDigests[0]
+	}
+
+	return details, nil
+}
+
+// detailsFromInspect builds the curated view from a container inspection
+func detailsFromInspect(inspect types.ContainerJSON) *ContainerDetails {
+	details := &ContainerDetails{
+		Ports:    []PortBinding{},
+		Mounts:   []Mount{},
+		Networks: []NetworkEndpoint{},
+		Labels:   map[string]string{},
+		Env:      []EnvVar{},
+	}
+
+	if base := inspect.ContainerJSONBase; base != nil {
+		details.ID = shortID(base.ID)
+		details.Name = strings.TrimPrefix(base.Name, "/")
+		details.ImageID = base.Image
+		details.Command = strings.TrimSpace(base.Path + " " + strings.Join(redactArgs(base.Args), " "))
+		if created, err := time.Parse(time.RFC3339Nano, base.Created); err == nil {
+			details.Created = created
+		}
+		if base.HostConfig != nil {
+			details.RestartPolicy = RestartPolicy{
+				Name:              string(base.HostConfig.RestartPolicy.Name),
+				MaximumRetryCount: base.HostConfig.RestartPolicy.MaximumRetryCount,
+			}
+		}
+	}
+
+	if cfg := inspect.Config; cfg != nil {
+		details.Image = cfg.Image
+		// e.g. traefik.http.middlewares.auth.basicauth.users=admin:$apr1$...
+		for name, value := range cfg.Labels {
+			details.Labels[name], _ = redactValue(name, value)
+		}
+		for _, kv := range cfg.Env {
+			details.Env = append(details.Env, redactEnv(kv))
+		}
+	}
+
+	for _, m := range inspect.Mounts {
+		details.Mounts = append(details.Mounts, Mount{
+			Type:        string(m.Type),
+			Name:        m.Name,
+			Source:      m.Source,
+			Destination: m.Destination,
+			ReadOnly:    !m.RW,
+		})
+	}
+
+	if settings := inspect.NetworkSettings; settings != nil {
+		for port, bindings := range settings.Ports {
+			if len(bindings) == 0 {
+				details.Ports = append(details.Ports, PortBinding{ContainerPort: port.Int(), Protocol: port.Proto()})
+				continue
+			}
+			for _, binding := range bindings {
+				details.Ports = append(details.Ports, PortBinding{
+					ContainerPort: port.Int(),
+					Protocol:      port.Proto(),
+					HostIP:        binding.HostIP,
+					HostPort:      binding.HostPort,
+				})
+			}
+		}
+
+		for name, endpoint := range settings.Networks {
+			if endpoint == nil {
+				continue
+			}
+			details.Networks = append(details.Networks, NetworkEndpoint{
+				Name:       name,
+				IPAddress:  endpoint.IPAddress,
+				IPv6:       endpoint.GlobalIPv6Address,
+				Gateway:    endpoint.Gateway,
+				MacAddress: endpoint.MacAddress,
+				Aliases:    endpoint.Aliases,
+			})
+		}
+	}
+
+	// Map iteration order is random; keep the output stable
+	sort.Slice(details.Ports, func(i, j int) bool {
+		a, b := details.Ports[i], details.Ports[j]
+		if a.ContainerPort != b.ContainerPort {
+			return a.ContainerPort < b.ContainerPort
+		}
+		if a.Protocol != b.Protocol {
+			return a.Protocol < b.Protocol
+		}
+		return a.HostIP < b.HostIP
+	})
+	sort.Slice(details.Networks, func(i, j int) bool {
+		return details.Networks[i].Name < details.Networks[j].Name
+	})
+
+	return details
+}
+
+// redactEnv splits a NAME=value pair, hiding values of secret-looking
+// variables and passwords embedded in URLs
+func redactEnv(kv string) EnvVar {
+	name, value, _ := strings.Cut(kv, "=")
+	value, redacted := redactValue(name, value)
+	return EnvVar{Name: name, Value: value, Redacted: redacted}
+}
+
+// redactArgs hides the values of secret-looking flags, given as
+// --password=value or --password value, and passwords embedded in URLs
+func redactArgs(args []string) []string {
+	result := make([]string, len(args))
+	for i, arg := range args {
+		if i > 0 && isSecretFlag(args[i-1]) && !strings.HasPrefix(arg, "-") {
+			result[i] = redactedValue
+			continue
+		}
+		if redacted, ok := redactURL(arg); ok {
+			result[i] = redacted
+			continue
+		}
+		if name, value, ok := strings.Cut(arg, "="); ok {
+			value, _ = redactValue(name, value)
+			result[i] = name + "=" + value
+			continue
+		}
+		result[i] = arg
+	}
+	return result
+}
+
+// isSecretFlag reports whether an argument is a flag whose value follows it
+// and should be hidden, such as --password
+func isSecretFlag(arg string) bool {
+	return strings.HasPrefix(arg, "-") && !strings.Contains(arg, "=") && secretEnvPattern.MatchString(arg)
+}
+
+// redactValue hides a value if its name looks secret or it embeds a
+// password in a URL, and reports whether it did
+func redactValue(name, value string) (string, bool) {
+	if value == "" {
+		return value, false
+	}
+	if secretEnvPattern.MatchString(name) {
+		return redactedValue, true
+	}
+	return redactURL(value)
+}
+
+// redactURL hides the password of a URL such as postgres://user:password@db/app
+func redactURL(value string) (string, bool) {
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			return u.Redacted(), true
+		}
+	}
+	return value, false
+}
@@ -0,0 +1,142 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactEnv(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected EnvVar
+	}{
+		{"TZ=Europe/Berlin", EnvVar{Name: "TZ", Value: "Europe/Berlin"}},
+		{"POSTGRES_PASSWORD=hunter2", EnvVar{Name: "POSTGRES_PASSWORD", Value: redactedValue, Redacted: true}},
+		{"API_KEY=abc", EnvVar{Name: "API_KEY", Value: redactedValue, Redacted: true}},
+		{"github_token=abc", EnvVar{Name: "github_token", Value: redactedValue, Redacted: true}},
+		{"DATABASE_URL=postgres://app:hunter2@db:5432/app", EnvVar{Name: "DATABASE_URL", Value: "postgres://app:xxxxx@db:5432/app", Redacted: true}},
+		{"UPSTREAM=http://proxy@example.com", EnvVar{Name: "UPSTREAM", Value: "http://proxy@example.com"}},
+		{"EMPTY_SECRET=", EnvVar{Name: "EMPTY_SECRET"}},
+		{"FLAG", EnvVar{Name: "FLAG"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, redactEnv(tt.input))
+		})
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{"plain", []string{"serve", "--port", "8080"}, []string{"serve", "--port", "8080"}},
+		{"flag with value", []string{"--password=hunter2", "--user=admin"}, []string{"--password=********", "--user=admin"}},
+		{"flag then value", []string{"--token", "abc", "-v"}, []string{"--token", "********", "-v"}},
+		{"flag without value", []string{"--no-auth", "--port", "80"}, []string{"--no-auth", "--port", "80"}},
+		{"url", []string{"--db", "postgres://app:hunter2@db/app"}, []string{"--db", "postgres://app:xxxxx@db/app"}},
+		{"url with query", []string{"--db=postgres://app:hunter2@db/app?sslmode=off"}, []string{"--db=postgres://app:xxxxx@db/app?sslmode=off"}},
+		{"env style", []string{"env", "SECRET_KEY=abc", "app"}, []string{"env", "SECRET_KEY=********", "app"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, redactArgs(tt.args))
+		})
+	}
+}
+
+func TestDetailsFromInspect(t *testing.T) {
+	inspect := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:      "0123456789abcdef",
+			Name:    "/jellyfin",
+			Image:   "sha256:feed",
+			Path:    "/jellyfin/jellyfin",
+			Args:    []string{"--ffmpeg", "/usr/bin/ffmpeg", "--api-key", "abc"},
+			Created: "2026-01-02T03:04:05.5Z",
+			HostConfig: &container.HostConfig{
+				RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 3},
+			},
+		},
+		Mounts: []types.MountPoint{
+			{Type: mount.TypeBind, Source: "/srv/media", Destination: "/media", RW: false},
+			{Type: mount.TypeVolume, Name: "jellyfin-config", Source: "/var/lib/docker/volumes/jellyfin-config/_data", Destination: "/config", RW: true},
+		},
+		Config: &container.Config{
+			Image: "jellyfin/jellyfin:10.9",
+			Labels: map[string]string{
+				"home-run.enable": "true",
+				"traefik.http.middlewares.auth.basicauth.users": "admin:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/",
+			},
+			Env: []string{"TZ=UTC", "JELLYFIN_API_KEY=abc"},
+		},
+		NetworkSettings: &types.NetworkSettings{
+			NetworkSettingsBase: types.NetworkSettingsBase{
+				Ports: nat.PortMap{
+					"8920/tcp": nil,
+					"8096/tcp": {{HostIP: "0.0.0.0", HostPort: "8096"}, {HostIP: "::", HostPort: "8096"}},
+					"1900/udp": nil,
+				},
+			},
+			Networks: map[string]*network.EndpointSettings{
+				"proxy":  {IPAddress: "172.20.0.5", Gateway: "172.20.0.1", Aliases: []string{"jellyfin"}},
+				"bridge": {IPAddress: "172.17.0.3"},
+			},
+		},
+	}
+
+	details := detailsFromInspect(inspect)
+
+	assert.Equal(t, "0123456789ab", details.ID)
+	assert.Equal(t, "jellyfin", details.Name)
+	assert.Equal(t, "jellyfin/jellyfin:10.9", details.Image)
+	assert.Equal(t, "sha256:feed", details.ImageID)
+	assert.Equal(t, "/jellyfin/jellyfin --ffmpeg /usr/bin/ffmpeg --api-key ********", details.Command)
+	assert.Equal(t, 2026, details.Created.Year())
+	assert.Equal(t, RestartPolicy{Name: "on-failure", MaximumRetryCount: 3}, details.RestartPolicy)
+
+	assert.Equal(t, []PortBinding{
+		{ContainerPort: 1900, Protocol: "udp"},
+		{ContainerPort: 8096, Protocol: "tcp", HostIP: "0.0.0.0", HostPort: "8096"},
+		{ContainerPort: 8096, Protocol: "tcp", HostIP: "::", HostPort: "8096"},
+		{ContainerPort: 8920, Protocol: "tcp"},
+	}, details.Ports)
+
+	require.Len(t, details.Mounts, 2)
+	assert.True(t, details.Mounts[0].ReadOnly)
+	assert.Equal(t, "jellyfin-config", details.Mounts[1].Name)
+	assert.False(t, details.Mounts[1].ReadOnly)
+
+	require.Len(t, details.Networks, 2)
+	assert.Equal(t, "bridge", details.Networks[0].Name)
+	assert.Equal(t, "172.20.0.5", details.Networks[1].IPAddress)
+	assert.Equal(t, []string{"jellyfin"}, details.Networks[1].Aliases)
+
+	assert.Equal(t, "true", details.Labels["home-run.enable"])
+	assert.Equal(t, redactedValue, details.Labels["traefik.http.middlewares.auth.basicauth.users"])
+	assert.Equal(t, []EnvVar{
+		{Name: "TZ", Value: "UTC"},
+		{Name: "JELLYFIN_API_KEY", Value: redactedValue, Redacted: true},
+	}, details.Env)
+}
+
+func TestDetailsFromInspect_Empty(t *testing.T) {
+	details := detailsFromInspect(types.ContainerJSON{})
+
+	// Lists are empty rather than null in JSON
+	assert.NotNil(t, details.Ports)
+	assert.NotNil(t, details.Mounts)
+	assert.NotNil(t, details.Networks)
+	assert.NotNil(t, details.Env)
+}
@@ -0,0 +1,223 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	timetypes "github.com/docker/docker/api/types/time"
+)
+
+// ErrInvalidLogOptions is returned when tail, since or grep can't be used
+var ErrInvalidLogOptions = errors.New("invalid log options")
+
+// LogOptions selects which log lines to return
+type LogOptions struct {
+	Tail   int    // number of lines from the end before filtering; negative for all
+	Since  string // RFC 3339 time, unix timestamp or duration such as 10m
+	Follow bool   // keep streaming new lines
+	Grep   string // regular expression lines must match
+	Invert bool   // return lines that don't match Grep instead
+}
+
+// LogLine is one line of container output
+type LogLine struct {
+	Time   time.Time `json:"time"`
+	Stream string    `json:"stream"` // stdout or stderr
+	Text   string    `json:"text"`
+}
+
+// Stream identifiers in the multiplexed log header
+var logStreams = map[byte]string{1: "stdout", 2: "stderr"}
+
+// maxLogFrameSize bounds the payload of one multiplexed frame. The daemon
+// sends far smaller frames, so a larger size means a corrupt stream.
+const maxLogFrameSize = 1 << 20
+
+// LogStream reads lines from a container's log
+type LogStream struct {
+	body    io.ReadCloser
+	reader  *bufio.Reader
+	tty     bool
+	grep    *regexp.Regexp
+	invert  bool
+	pending map[string]string
+	queue   []LogLine
+	done    bool
+}
+
+// ContainerLogs opens the log of a container by name. Close the stream when done.
+func (c *Client) ContainerLogs(ctx context.Context, containerName string, opts LogOptions) (*LogStream, error) {
+	if opts.Since != "" {
+		if _, err := timetypes.GetTimestamp(opts.Since, time.Now()); err != nil {
+			return nil, fmt.Errorf("%w: since: %v", ErrInvalidLogOptions, err)
+		}
+	}
+	var grep *regexp.Regexp
+	if opts.Grep != "" {
+		var err error
+		if grep, err = regexp.Compile(opts.Grep); err != nil {
+			return nil, fmt.Errorf("%w: grep: %v", ErrInvalidLogOptions, err)
+		}
+	}
+
+	containers, err := c.ListContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, ok := containers[containerName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrContainerNotFound, containerName)
+	}
+
+	// Containers with a TTY write a raw stream instead of a multiplexed one
+	inspect, err := c.cli.ContainerInspect(ctx, info.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	tty := inspect.Config != nil && inspect.Config.Tty
+
+	tail := "all"
+	if opts.Tail >= 0 {
+		tail = strconv.Itoa(opts.Tail)
+	}
+	body, err := c.cli.ContainerLogs(ctx, info.ID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Since:      opts.Since,
+		Timestamps: true,
+		Follow:     opts.Follow,
+		Tail:       tail,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container logs: %w", err)
+	}
+
+	return newLogStream(body, tty, grep, opts.Invert), nil
+}
+
+func newLogStream(body io.ReadCloser, tty bool, grep *regexp.Regexp, invert bool) *LogStream {
+	return &LogStream{
+		body:    body,
+		reader:  bufio.NewReader(body),
+		tty:     tty,
+		grep:    grep,
+		invert:  invert,
+		pending: make(map[string]string),
+	}
+}
+
+// Next returns the next matching line, or io.EOF at the end of the log
+func (s *LogStream) Next() (LogLine, error) {
+	for len(s.queue) == 0 {
+		if s.done {
+			return LogLine{}, io.EOF
+		}
+		if err := s.fill(); err != nil {
+			return LogLine{}, err
+		}
+	}
+
+	line := s.queue[0]
+	s.queue = s.queue[1:]
+	return line, nil
+}
+
+// Close closes the underlying log stream
+func (s *LogStream) Close() error {
+	return s.body.Close()
+}
+
+// fill reads the next chunk of the log into the queue
+func (s *LogStream) fill() error {
+	if s.tty {
+		chunk, err := s.reader.ReadString('\n')
+		s.add("stdout", chunk)
+		return s.finish(err)
+	}
+
+	// Multiplexed frames: stream type, three zero bytes, big-endian payload size
+	var header [8]byte
+	if _, err := io.ReadFull(s.reader, header[:]); err != nil {
+		return s.finish(err)
+	}
+	size := binary.BigEndian.Uint32(header[4:])
+	if size > maxLogFrameSize {
+		return fmt.Errorf("log frame of %d bytes exceeds %d bytes", size, maxLogFrameSize)
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(s.reader, payload); err != nil {
+		return s.finish(err)
+	}
+
+	stream, ok := logStreams[header[0]]
+	if !ok {
+		// stdin is never sent and systemerr carries daemon errors
+		return fmt.Errorf("log stream error: %s", strings.TrimSpace(string(payload)))
+	}
+	s.add(stream, string(payload))
+	return nil
+}
+
+// finish handles a read error, flushing incomplete lines at the end of the log
+func (s *LogStream) finish(err error) error {
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+	for _, stream := range []string{"stdout", "stderr"} {
+		if rest := s.pending[stream]; rest != "" {
+			s.pending[stream] = ""
+			s.emit(stream, rest)
+		}
+	}
+	s.done = true
+	return nil
+}
+
+// add buffers output and queues each complete line
+func (s *LogStream) add(stream, chunk string) {
+	buf := s.pending[stream] + chunk
+	for {
+		line, rest, found := strings.Cut(buf, "\n")
+		if !found {
+			break
+		}
+		s.emit(stream, line)
+		buf = rest
+	}
+	s.pending[stream] = buf
+}
+
+// emit queues a line if it passes the filter
+func (s *LogStream) emit(stream, raw string) {
+	line := parseLogLine(stream, raw)
+	if s.grep != nil && s.grep.MatchString(line.Text) == s.invert {
+		return
+	}
+	s.queue = append(s.queue, line)
+}
+
+// parseLogLine splits the timestamp docker prepends to each line
+func parseLogLine(stream, raw string) LogLine {
+	raw = strings.TrimSuffix(raw, "\r")
+	line := LogLine{Stream: stream, Text: raw}
+
+	if ts, text, found := strings.Cut(raw, " "); found {
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			line.Time = t
+			line.Text = text
+		}
+	}
+	return line
+}
@@ -0,0 +1,157 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// frame builds one multiplexed log frame
+func frame(stream byte, payload string) []byte {
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	return append(header, payload...)
+}
+
+func readAll(t *testing.T, s *LogStream) []LogLine {
+	t.Helper()
+	var lines []LogLine
+	for {
+		line, err := s.Next()
+		if err == io.EOF {
+			return lines
+		}
+		require.NoError(t, err)
+		lines = append(lines, line)
+	}
+}
+
+func TestLogStream_Multiplexed(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(frame(1, "2026-01-02T03:04:05.123456789Z listening on :8080\n"))
+	buf.Write(frame(2, "2026-01-02T03:04:06Z warning: disk "))
+	buf.Write(frame(1, "2026-01-02T03:04:07Z request served\n"))
+	buf.Write(frame(2, "almost full\n"))
+	buf.Write(frame(1, "2026-01-02T03:04:08Z no trailing newline"))
+
+	lines := readAll(t, newLogStream(io.NopCloser(&buf), false, nil, false))
+
+	require.Len(t, lines, 4)
+	assert.Equal(t, LogLine{
+		Time:   time.Date(2026, 1, 2, 3, 4, 5, 123456789, time.UTC),
+		Stream: "stdout",
+		Text:   "listening on :8080",
+	}, lines[0])
+	assert.Equal(t, "request served", lines[1].Text)
+	assert.Equal(t, "stderr", lines[2].Stream)
+	assert.Equal(t, "warning: disk almost full", lines[2].Text)
+	assert.Equal(t, "no trailing newline", lines[3].Text)
+}
+
+func TestLogStream_TTY(t *testing.T) {
+	body := io.NopCloser(bytes.NewBufferString("2026-01-02T03:04:05Z first\r\n2026-01-02T03:04:06Z second\n"))
+
+	lines := readAll(t, newLogStream(body, true, nil, false))
+
+	require.Len(t, lines, 2)
+	assert.Equal(t, "stdout", lines[0].Stream)
+	assert.Equal(t, "first", lines[0].Text)
+	assert.Equal(t, "second", lines[1].Text)
+}
+
+func TestLogStream_Grep(t *testing.T) {
+	input := "2026-01-02T03:04:05Z GET /health 200\n2026-01-02T03:04:06Z GET /api 500\n2026-01-02T03:04:07Z GET /health 200\n"
+	grep := regexp.MustCompile(`/health`)
+
+	matched := readAll(t, newLogStream(io.NopCloser(bytes.NewBufferString(input)), true, grep, false))
+	require.Len(t, matched, 2)
+
+	inverted := readAll(t, newLogStream(io.NopCloser(bytes.NewBufferString(input)), true, grep, true))
+	require.Len(t, inverted, 1)
+	assert.Equal(t, "GET /api 500", inverted[0].Text)
+}
+
+func TestLogStream_SystemError(t *testing.T) {
+	body := io.NopCloser(bytes.NewReader(frame(3, "log driver failed\n")))
+
+	_, err := newLogStream(body, false, nil, false).Next()
+	assert.ErrorContains(t, err, "log driver failed")
+}
+
+func TestLogStream_OversizedFrame(t *testing.T) {
+	header := []byte{1, 0, 0, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(header[4:], 0xFFFFFFFF)
+
+	_, err := newLogStream(io.NopCloser(bytes.NewReader(header)), false, nil, false).Next()
+	assert.ErrorContains(t, err, "exceeds")
+}
+
+func TestParseLogLine_WithoutTimestamp(t *testing.T) {
+	line := parseLogLine("stdout", "plain output")
+	assert.True(t, line.Time.IsZero())
+	assert.Equal(t, "plain output", line.Text)
+}
+
+func TestContainerLogs(t *testing.T) {
+	var gotQuery url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_ping"):
+			w.Header().Set("Api-Version", "1.45")
+			_, _ = w.Write([]byte("OK"))
+		case strings.HasSuffix(r.URL.Path, "/containers/json"):
+			_, _ = w.Write([]byte(`[{"Id":"abcdef0123456789","Names":["/web"],"State":"running"}]`))
+		case strings.HasSuffix(r.URL.Path, "/containers/abcdef012345/json"):
+			_, _ = w.Write([]byte(`{"Id":"abcdef0123456789","Config":{"Tty":false}}`))
+		case strings.HasSuffix(r.URL.Path, "/containers/abcdef012345/logs"):
+			gotQuery = r.URL.Query()
+			w.Header().Set("Content-Type", "application/vnd.docker.multiplexed-stream")
+			_, _ = w.Write(frame(1, "2026-01-02T03:04:05Z GET /health\n"))
+			_, _ = w.Write(frame(2, "2026-01-02T03:04:06Z panic: oops\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := openTestClient(t, server)
+
+	stream, err := c.ContainerLogs(context.Background(), "web", LogOptions{Tail: 50, Since: "10m", Grep: "panic"})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	lines := readAll(t, stream)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "stderr", lines[0].Stream)
+	assert.Equal(t, "panic: oops", lines[0].Text)
+
+	assert.Equal(t, "50", gotQuery.Get("tail"))
+	assert.Equal(t, "1", gotQuery.Get("timestamps"))
+	assert.NotEmpty(t, gotQuery.Get("since"))
+
+	_, err = c.ContainerLogs(context.Background(), "missing", LogOptions{})
+	assert.ErrorIs(t, err, ErrContainerNotFound)
+}
+
+func TestContainerLogs_InvalidOptions(t *testing.T) {
+	c := &Client{}
+
+	_, err := c.ContainerLogs(context.Background(), "web", LogOptions{Since: "yesterday"})
+	assert.ErrorIs(t, err, ErrInvalidLogOptions)
+
+	_, err = c.ContainerLogs(context.Background(), "web", LogOptions{Grep: "("})
+	assert.ErrorIs(t, err, ErrInvalidLogOptions)
+}
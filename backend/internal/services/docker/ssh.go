@@ -0,0 +1,104 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// sshArgs builds the ssh command line that runs `docker system dial-stdio` on a remote host
+func sshArgs(rawURL string) ([]string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ssh url: %w", err)
+	}
+	if u.Scheme != "ssh" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid ssh url '%s'", rawURL)
+	}
+	if u.Path != "" && u.Path != "/" {
+		return nil, fmt.Errorf("ssh url '%s' must not have a path", rawURL)
+	}
+
+	args := []string{"-o", "BatchMode=yes"}
+	if u.User != nil {
+		args = append(args, "-l", u.User.Username())
+	}
+	if port := u.Port(); port != "" {
+		args = append(args, "-p", port)
+	}
+	return append(args, "--", u.Hostname(), "docker", "system", "dial-stdio"), nil
+}
+
+// sshDialer returns a dialer that tunnels each connection over a new ssh process
+func sshDialer(rawURL string) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	args, err := sshArgs(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return func(_ context.Context, _, _ string) (net.Conn, error) {
+		return newCommandConn("ssh", args...)
+	}, nil
+}
+
+// commandConn is a net.Conn over the stdin/stdout of a command
+type commandConn struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	stdout    io.ReadCloser
+	closeOnce sync.Once
+}
+
+// newCommandConn starts a command and connects to its stdio. The command is not bound
+// to a context since the connection outlives the dial.
+func newCommandConn(name string, args ...string) (net.Conn, error) {
+	cmd := exec.Command(name, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", name, err)
+	}
+	return &commandConn{cmd: cmd, stdin: stdin, stdout: stdout}, nil
+}
+
+func (c *commandConn) Read(p []byte) (int, error) {
+	return c.stdout.Read(p)
+}
+
+func (c *commandConn) Write(p []byte) (int, error) {
+	return c.stdin.Write(p)
+}
+
+// Close closes stdin and stops the command
+func (c *commandConn) Close() error {
+	c.closeOnce.Do(func() {
+		_ = c.stdin.Close()
+		_ = c.cmd.Process.Kill()
+		_ = c.cmd.Wait()
+	})
+	return nil
+}
+
+func (c *commandConn) LocalAddr() net.Addr  { return commandAddr{} }
+func (c *commandConn) RemoteAddr() net.Addr { return commandAddr{} }
+
+// Deadlines are not supported on pipes; the HTTP client's timeouts still apply
+func (c *commandConn) SetDeadline(_ time.Time) error      { return nil }
+func (c *commandConn) SetReadDeadline(_ time.Time) error  { return nil }
+func (c *commandConn) SetWriteDeadline(_ time.Time) error { return nil }
+
+// commandAddr is the placeholder address of a commandConn
+type commandAddr struct{}
+
+func (commandAddr) Network() string { return "command" }
+func (commandAddr) String() string  { return "command" }
@@ -0,0 +1,53 @@
+package docker
+
+import (
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSHArgs(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected []string
+	}{
+		{"ssh://nas", []string{"-o", "BatchMode=yes", "--", "nas", "docker", "system", "dial-stdio"}},
+		{"ssh://admin@vm.lan:2222", []string{"-o", "BatchMode=yes", "-l", "admin", "-p", "2222", "--", "vm.lan", "docker", "system", "dial-stdio"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			args, err := sshArgs(tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, args)
+		})
+	}
+
+	_, err := sshArgs("tcp://nas:2375")
+	assert.Error(t, err)
+	_, err = sshArgs("ssh://nas/var/run/docker.sock")
+	assert.Error(t, err)
+}
+
+func TestCommandConn_RoundTrip(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat not available")
+	}
+
+	conn, err := newCommandConn("cat")
+	require.NoError(t, err)
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+
+	assert.NoError(t, conn.Close())
+	assert.NoError(t, conn.Close()) // idempotent
+}
@@ -2,6 +2,7 @@ package docker
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -12,37 +13,79 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// CachedStats holds cached container stats
+// CachedStats holds cached container status and stats
 type CachedStats struct {
-	Status     string
-	StartedAt  time.Time
-	CPUPercent float64
-	MemoryMB   float64
+	ContainerInfo
+	Usage
 	LastUpdate time.Time
 }
 
-// StatsCollector collects Docker container stats periodically
+// containerState is the last known state of a tracked container
+type containerState struct {
+	info    ContainerInfo
+	updated time.Time
+	stale   bool // changed by an event; inspect on the next poll
+}
+
+// StatsCollector tracks container status from the Docker event stream and
+// polls resource usage periodically
 type StatsCollector struct {
-	client   *Client
-	cache    *cache.Cache
-	services []config.ServiceConfig
-	interval time.Duration
-	mu       sync.RWMutex
-	running  bool
-	stopCh   chan struct{}
+	client     *Client
+	cache      *cache.Cache
+	services   []config.ServiceConfig
+	interval   time.Duration
+	discover   bool
+	dockerHost string
+	discovered []config.ServiceConfig
+	containers map[string]containerState
+	samples    map[string]*Stats   // previous usage sample by container ID, for I/O rates
+	projects   map[string][]string // compose project members found by the last poll
+	healer     *Healer             // nil unless a service has auto_heal
+	mu         sync.RWMutex
+	running    bool
+	cancel     context.CancelFunc
+	stopCh     chan struct{}
 }
 
 // NewStatsCollector creates a new stats collector
 func NewStatsCollector(dockerClient *Client, statsCache *cache.Cache, services []config.ServiceConfig, interval time.Duration) *StatsCollector {
 	return &StatsCollector{
-		client:   dockerClient,
-		cache:    statsCache,
-		services: services,
-		interval: interval,
-		stopCh:   make(chan struct{}),
+		client:     dockerClient,
+		cache:      statsCache,
+		services:   services,
+		interval:   interval,
+		containers: make(map[string]containerState),
+		samples:    make(map[string]*Stats),
+		projects:   make(map[string][]string),
+		healer:     newHealer(dockerClient, services),
+		stopCh:     make(chan struct{}),
 	}
 }
 
+// EnableDiscovery makes the collector also pick up containers labelled
+// home-run.enable=true on each collection, tagging them with dockerHost
+func (sc *StatsCollector) EnableDiscovery(dockerHost string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.discover = true
+	sc.dockerHost = dockerHost
+}
+
+// Discovered returns the services found by the last discovery run
+func (sc *StatsCollector) Discovered() []config.ServiceConfig {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return append([]config.ServiceConfig(nil), sc.discovered...)
+}
+
+// Monitored returns the configured services plus those found by the last
+// discovery run
+func (sc *StatsCollector) Monitored() []config.ServiceConfig {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return append(append([]config.ServiceConfig(nil), sc.services...), sc.discovered...)
+}
+
 // Start begins collecting stats in the background
 func (sc *StatsCollector) Start(ctx context.Context) {
 	sc.mu.Lock()
@@ -51,6 +94,7 @@ func (sc *StatsCollector) Start(ctx context.Context) {
 		return
 	}
 	sc.running = true
+	ctx, sc.cancel = context.WithCancel(ctx)
 	sc.mu.Unlock()
 
 	logger.WithField("interval", sc.interval).Info("Starting Docker stats collector")
@@ -58,6 +102,9 @@ func (sc *StatsCollector) Start(ctx context.Context) {
 	// Collect immediately on start
 	sc.collectAll(ctx)
 
+	// Status changes arrive as events; the ticker only refreshes usage
+	go sc.watchEvents(ctx)
+
 	go func() {
 		ticker := time.NewTicker(sc.interval)
 		defer ticker.Stop()
@@ -83,35 +130,56 @@ func (sc *StatsCollector) Stop() {
 	defer sc.mu.Unlock()
 	if sc.running {
 		close(sc.stopCh)
+		sc.cancel()
 		sc.running = false
 	}
 }
 
-// collectAll collects stats for all Docker services
+// collectAll refreshes status and resource usage for all services routed to
+// the collector, listing containers once rather than once per service
 func (sc *StatsCollector) collectAll(ctx context.Context) {
+	// Watch reports the outage; services show the daemon as unreachable until it is back
+	if !sc.client.Connected() {
+		logger.Log.Debug("Docker daemon unreachable, skipping stats collection")
+		return
+	}
+
 	logger.Log.Debug("Collecting Docker stats for all containers")
-	for _, svc := range sc.services {
-		if svc.Backend != "docker" {
-			continue
+	targets := sc.targets(ctx)
+
+	listedAt := time.Now()
+	containers, err := sc.client.ListContainers(ctx)
+	if err != nil {
+		logger.WithField("error", err.Error()).Warn("Failed to list containers")
+		for _, name := range sc.containerNames(targets) {
+			sc.cache.Set(name, &CachedStats{
+				ContainerInfo: ContainerInfo{Status: "ERROR"},
+				LastUpdate:    time.Now(),
+			})
 		}
+		return
+	}
+	targets, projects := expandProjects(targets, containers)
+	sc.mu.Lock()
+	sc.projects = projects
+	sc.mu.Unlock()
+	sc.track(targets, containers)
 
-		info, err := sc.client.GetContainerInfo(ctx, svc.ContainerName)
-		if err != nil {
-			logger.WithFields(logrus.Fields{
-				"container": svc.ContainerName,
-				"error":     err.Error(),
-			}).Warn("Failed to get container info")
+	for _, svc := range targets {
+		info, ok := containers[svc.ContainerName]
+		if !ok {
+			logger.WithField("container", svc.ContainerName).Warn("Container not found")
 			sc.cache.Set(svc.ContainerName, &CachedStats{
-				Status:     "ERROR",
-				LastUpdate: time.Now(),
+				ContainerInfo: ContainerInfo{Status: "ERROR"},
+				LastUpdate:    time.Now(),
 			})
 			continue
 		}
+		info = sc.reconcile(ctx, svc.ContainerName, info, listedAt)
 
 		cached := &CachedStats{
-			Status:     info.Status,
-			StartedAt:  info.StartedAt,
-			LastUpdate: time.Now(),
+			ContainerInfo: info,
+			LastUpdate:    time.Now(),
 		}
 
 		// Only get stats if container is running
@@ -123,18 +191,135 @@ func (sc *StatsCollector) collectAll(ctx context.Context) {
 					"error":     err.Error(),
 				}).Warn("Failed to get container stats")
 			} else {
-				cached.CPUPercent = stats.CPUPercent
-				cached.MemoryMB = stats.MemoryMB
+				cached.Usage = sc.usage(info.ID, stats)
 				logger.WithFields(logrus.Fields{
 					"container": svc.ContainerName,
 					"cpu":       stats.CPUPercent,
 					"memory_mb": stats.MemoryMB,
+					"net_rx":    cached.NetRxBytesPerSec,
+					"net_tx":    cached.NetTxBytesPerSec,
 				}).Debug("Collected container stats")
 			}
 		}
 
 		sc.cache.Set(svc.ContainerName, cached)
+		sc.healer.observe(svc.ContainerName, info)
+	}
+}
+
+// containerNames returns the containers of the targets, using the last known
+// members of compose projects
+func (sc *StatsCollector) containerNames(targets []config.ServiceConfig) []string {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	var names []string
+	for _, svc := range targets {
+		if svc.ComposeProject == "" {
+			names = append(names, svc.ContainerName)
+			continue
+		}
+		names = append(names, sc.projects[svc.ComposeProject]...)
+	}
+	return names
+}
+
+// usage computes usage from a sample and remembers it for the next rates
+func (sc *StatsCollector) usage(containerID string, stats *Stats) Usage {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	usage := UsageFrom(stats, sc.samples[containerID])
+	sc.samples[containerID] = stats
+	return usage
+}
+
+// track limits the tracked containers to the current targets so events for
+// unrelated containers are ignored, and drops samples of removed containers
+func (sc *StatsCollector) track(targets []config.ServiceConfig, containers map[string]ContainerInfo) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	wanted := make(map[string]bool, len(targets))
+	for _, svc := range targets {
+		wanted[svc.ContainerName] = true
+		if _, ok := sc.containers[svc.ContainerName]; !ok {
+			sc.containers[svc.ContainerName] = containerState{}
+		}
+	}
+	for name := range sc.containers {
+		if !wanted[name] {
+			delete(sc.containers, name)
+		}
+	}
+
+	present := make(map[string]bool, len(containers))
+	for _, info := range containers {
+		present[info.ID] = true
+	}
+	for id := range sc.samples {
+		if !present[id] {
+			delete(sc.samples, id)
+		}
+	}
+}
+
+// reconcile merges a listed container with its tracked state. Containers are
+// only inspected when they change or their healthcheck is not passing, and an
+// event newer than the listing wins.
+func (sc *StatsCollector) reconcile(ctx context.Context, name string, info ContainerInfo, listedAt time.Time) ContainerInfo {
+	sc.mu.RLock()
+	prev := sc.containers[name]
+	sc.mu.RUnlock()
+
+	if prev.updated.After(listedAt) {
+		return prev.info
+	}
+	// Failing streak and probe output change without an event while unhealthy
+	settled := prev.info.Health == "" || prev.info.Health == "healthy"
+	if prev.info.ID == info.ID && prev.info.State == info.State && !prev.stale && settled {
+		return prev.info
+	}
+	if err := sc.client.inspectState(ctx, &info); err != nil {
+		// Leave the tracked state alone so the next poll inspects again
+		logger.WithFields(logrus.Fields{
+			"container": name,
+			"error":     err.Error(),
+		}).Warn("Failed to inspect container")
+		return info
+	}
+
+	sc.mu.Lock()
+	if cur, ok := sc.containers[name]; ok && !cur.updated.After(listedAt) {
+		sc.containers[name] = containerState{info: info, updated: listedAt}
+	}
+	sc.mu.Unlock()
+	return info
+}
+
+// targets returns the configured services plus any discovered containers
+func (sc *StatsCollector) targets(ctx context.Context) []config.ServiceConfig {
+	sc.mu.RLock()
+	discover, dockerHost := sc.discover, sc.dockerHost
+	sc.mu.RUnlock()
+
+	if !discover {
+		return sc.services
+	}
+
+	// Keep the previous discovery result if the daemon can't be listed
+	if discovered, err := sc.client.DiscoverServices(ctx, dockerHost, sc.services); err == nil {
+		sc.mu.Lock()
+		if len(discovered) != len(sc.discovered) {
+			logger.WithFields(logrus.Fields{
+				"docker_host": dockerHost,
+				"count":       len(discovered),
+			}).Info("Number of discovered containers changed")
+		}
+		sc.discovered = discovered
+		sc.mu.Unlock()
 	}
+
+	return append(append([]config.ServiceConfig(nil), sc.services...), sc.Discovered()...)
 }
 
 // GetCachedStats retrieves cached stats for a container
@@ -146,3 +331,40 @@ func (sc *StatsCollector) GetCachedStats(containerName string) *CachedStats {
 	}
 	return nil
 }
+
+// GetProjectStats rolls up the cached state of a compose project's containers,
+// or returns nil if the project hasn't been collected yet
+func (sc *StatsCollector) GetProjectStats(project string) *ProjectInfo {
+	sc.mu.RLock()
+	members, ok := sc.projects[project]
+	sc.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	cached := make(map[string]*CachedStats, len(members))
+	for _, name := range members {
+		if stats := sc.GetCachedStats(name); stats != nil {
+			cached[name] = stats
+		}
+	}
+	return rollUpProject(project, members, cached, time.Now())
+}
+
+// HealEvents returns the automatic restarts of auto_heal services, newest first
+func (sc *StatsCollector) HealEvents() []HealEvent {
+	return sc.healer.History()
+}
+
+// Tracked returns the names of the containers the collector follows,
+// including members of compose projects
+func (sc *StatsCollector) Tracked() []string {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	names := make([]string, 0, len(sc.containers))
+	for name := range sc.containers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
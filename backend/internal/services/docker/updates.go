@@ -0,0 +1,201 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"home-run-backend/internal/logger"
+	"home-run-backend/internal/services/registry"
+
+	"github.com/sirupsen/logrus"
+)
+
+// updateRecheck is how often running images are compared with the cached
+// registry digests, so pulling a new image clears the flag without waiting
+// for the next registry query
+const updateRecheck = 5 * time.Minute
+
+// ImageUpdate is the result of comparing a container's image with its registry
+type ImageUpdate struct {
+	Image           string    // reference the container was created from
+	Digest          string    // digest the tag has in the registry
+	UpdateAvailable bool      // the running image is not that digest
+	CheckedAt       time.Time // when the registry was queried
+}
+
+// remoteDigest is a cached registry lookup
+type remoteDigest struct {
+	digest  string
+	err     error
+	checked time.Time
+}
+
+// UpdateChecker compares the images of the containers tracked by a stats
+// collector with the digests of their tags in the registry
+type UpdateChecker struct {
+	client   *Client
+	registry *registry.Client
+	stats    *StatsCollector
+	interval time.Duration
+	results  map[string]ImageUpdate  // by container name
+	remote   map[string]remoteDigest // by image reference
+	mu       sync.RWMutex
+	running  bool
+	stopCh   chan struct{}
+}
+
+// NewUpdateChecker creates an update checker that queries registries at most once per interval
+func NewUpdateChecker(dockerClient *Client, registryClient *registry.Client, stats *StatsCollector, interval time.Duration) *UpdateChecker {
+	return &UpdateChecker{
+		client:   dockerClient,
+		registry: registryClient,
+		stats:    stats,
+		interval: interval,
+		results:  make(map[string]ImageUpdate),
+		remote:   make(map[string]remoteDigest),
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start begins checking for updates in the background
+func (uc *UpdateChecker) Start(ctx context.Context) {
+	uc.mu.Lock()
+	if uc.running {
+		uc.mu.Unlock()
+		return
+	}
+	uc.running = true
+	uc.mu.Unlock()
+
+	logger.WithField("interval", uc.interval).Info("Starting image update checker")
+
+	go func() {
+		ticker := time.NewTicker(min(uc.interval, updateRecheck))
+		defer ticker.Stop()
+
+		for {
+			uc.checkAll(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-uc.stopCh:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the update checker
+func (uc *UpdateChecker) Stop() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if uc.running {
+		close(uc.stopCh)
+		uc.running = false
+	}
+}
+
+// Get returns the latest update check of a container
+func (uc *UpdateChecker) Get(containerName string) (ImageUpdate, bool) {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	update, ok := uc.results[containerName]
+	return update, ok
+}
+
+// checkAll compares every tracked container's image with its registry.
+// Locally built images and images pinned to a digest are skipped.
+func (uc *UpdateChecker) checkAll(ctx context.Context) {
+	results := make(map[string]ImageUpdate)
+	available := 0
+
+	for _, name := range uc.stats.Tracked() {
+		image, repoDigests, err := uc.client.runningImage(ctx, name)
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"container": name,
+				"error":     err.Error(),
+			}).Debug("Failed to get container image")
+			continue
+		}
+		local := registry.RepoDigests(image, repoDigests)
+		if len(local) == 0 {
+			continue
+		}
+
+		remote := uc.remoteDigest(ctx, image)
+		if remote.err != nil {
+			continue
+		}
+
+		update := ImageUpdate{
+			Image:           image,
+			Digest:          remote.digest,
+			UpdateAvailable: !slices.Contains(local, remote.digest),
+			CheckedAt:       remote.checked,
+		}
+		if update.UpdateAvailable {
+			available++
+		}
+		results[name] = update
+	}
+
+	uc.mu.Lock()
+	uc.results = results
+	uc.mu.Unlock()
+
+	logger.WithFields(logrus.Fields{
+		"checked":   len(results),
+		"available": available,
+	}).Debug("Checked for image updates")
+}
+
+// remoteDigest returns the registry digest of an image reference, querying
+// the registry only when the cached result is older than the interval
+func (uc *UpdateChecker) remoteDigest(ctx context.Context, image string) remoteDigest {
+	uc.mu.RLock()
+	cached, ok := uc.remote[image]
+	uc.mu.RUnlock()
+	if ok && time.Since(cached.checked) < uc.interval {
+		return cached
+	}
+
+	digest, err := uc.registry.Digest(ctx, image)
+	if err != nil && !errors.Is(err, registry.ErrNoTag) {
+		logger.WithFields(logrus.Fields{
+			"image": image,
+			"error": err.Error(),
+		}).Warn("Failed to check image for updates")
+	}
+
+	// Failures are cached too so an unreachable registry isn't queried on every recheck
+	result := remoteDigest{digest: digest, err: err, checked: time.Now()}
+	uc.mu.Lock()
+	uc.remote[image] = result
+	uc.mu.Unlock()
+	return result
+}
+
+// runningImage returns the reference a container was created from and the
+// repo digests of its image
+func (c *Client) runningImage(ctx context.Context, containerName string) (string, []string, error) {
+	inspect, err := c.cli.ContainerInspect(ctx, containerName)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if inspect.ContainerJSONBase == nil || inspect.Config == nil {
+		return "", nil, fmt.Errorf("container '%s' has no image", containerName)
+	}
+
+	image, _, err := c.cli.ImageInspectWithRaw(ctx, inspect.Image)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to inspect image: %w", err)
+	}
+	return inspect.Config.Image, image.RepoDigests, nil
+}
@@ -0,0 +1,97 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"home-run-backend/internal/cache"
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/services/registry"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	currentDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	newerDigest   = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+func TestUpdateChecker(t *testing.T) {
+	var registryDigest atomic.Value
+	registryDigest.Store(currentDigest)
+	var registryRequests atomic.Int32
+	reg := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		registryRequests.Add(1)
+		if r.URL.Path != "/v2/team/app/manifests/1.2" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", registryDigest.Load().(string))
+	}))
+	defer reg.Close()
+	registryHost := strings.TrimPrefix(reg.URL, "http://")
+
+	daemon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_ping"):
+			w.Header().Set("Api-Version", "1.45")
+			_, _ = w.Write([]byte("OK"))
+		case strings.HasSuffix(r.URL.Path, "/containers/app/json"):
+			fmt.Fprintf(w, `{"Id":"abcdef0123456789","Image":"sha256:feed","Config":{"Image":"%s/team/app:1.2"}}`, registryHost)
+		case strings.HasSuffix(r.URL.Path, "/containers/builtlocally/json"):
+			_, _ = w.Write([]byte(`{"Id":"0123456789abcdef","Image":"sha256:beef","Config":{"Image":"builtlocally"}}`))
+		case strings.HasSuffix(r.URL.Path, "/images/sha256:feed/json"):
+			fmt.Fprintf(w, `{"Id":"sha256:feed","RepoDigests":["%s/team/app@%s"]}`, registryHost, currentDigest)
+		case strings.HasSuffix(r.URL.Path, "/images/sha256:beef/json"):
+			_, _ = w.Write([]byte(`{"Id":"sha256:beef","RepoDigests":[]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer daemon.Close()
+
+	c := openTestClient(t, daemon)
+
+	sc := NewStatsCollector(c, cache.New(time.Minute), nil, time.Second)
+	sc.containers["app"] = containerState{}
+	sc.containers["builtlocally"] = containerState{}
+
+	uc := NewUpdateChecker(c, registry.NewClient(map[string]config.RegistryConfig{
+		registryHost: {Insecure: true},
+	}), sc, time.Hour)
+
+	uc.checkAll(context.Background())
+	update, ok := uc.Get("app")
+	require.True(t, ok)
+	assert.False(t, update.UpdateAvailable)
+	assert.Equal(t, currentDigest, update.Digest)
+	_, ok = uc.Get("builtlocally")
+	assert.False(t, ok)
+
+	// The tag moves, but the registry isn't queried again within the interval
+	registryDigest.Store(newerDigest)
+	uc.checkAll(context.Background())
+	update, _ = uc.Get("app")
+	assert.False(t, update.UpdateAvailable)
+	assert.EqualValues(t, 1, registryRequests.Load())
+
+	uc.mu.Lock()
+	for image, cached := range uc.remote {
+		cached.checked = cached.checked.Add(-2 * time.Hour)
+		uc.remote[image] = cached
+	}
+	uc.mu.Unlock()
+
+	uc.checkAll(context.Background())
+	update, _ = uc.Get("app")
+	assert.True(t, update.UpdateAvailable)
+	assert.Equal(t, newerDigest, update.Digest)
+}
@@ -0,0 +1,50 @@
+package docker
+
+// Usage is the resource usage of a running container. Rates are bytes per
+// second since the previous sample.
+type Usage struct {
+	CPUPercent            float64
+	MemoryMB              float64
+	MemoryLimitMB         float64
+	MemoryPercent         float64
+	PIDs                  uint64
+	NetRxBytesPerSec      float64
+	NetTxBytesPerSec      float64
+	BlockReadBytesPerSec  float64
+	BlockWriteBytesPerSec float64
+}
+
+// UsageFrom builds usage from a sample, computing I/O rates against the
+// previous sample of the same container if there is one
+func UsageFrom(cur *Stats, prev *Stats) Usage {
+	usage := Usage{
+		CPUPercent:    cur.CPUPercent,
+		MemoryMB:      cur.MemoryMB,
+		MemoryLimitMB: cur.MemoryLimitMB,
+		PIDs:          cur.PIDs,
+	}
+	if cur.MemoryLimitMB > 0 {
+		usage.MemoryPercent = cur.MemoryMB / cur.MemoryLimitMB * 100
+	}
+
+	if prev == nil {
+		return usage
+	}
+	elapsed := cur.Read.Sub(prev.Read).Seconds()
+	if elapsed <= 0 {
+		return usage
+	}
+	usage.NetRxBytesPerSec = rate(cur.NetworkRxBytes, prev.NetworkRxBytes, elapsed)
+	usage.NetTxBytesPerSec = rate(cur.NetworkTxBytes, prev.NetworkTxBytes, elapsed)
+	usage.BlockReadBytesPerSec = rate(cur.BlockReadBytes, prev.BlockReadBytes, elapsed)
+	usage.BlockWriteBytesPerSec = rate(cur.BlockWriteBytes, prev.BlockWriteBytes, elapsed)
+	return usage
+}
+
+// rate returns the per-second increase of a counter, or 0 if it was reset
+func rate(cur, prev uint64, elapsed float64) float64 {
+	if cur < prev {
+		return 0
+	}
+	return float64(cur-prev) / elapsed
+}
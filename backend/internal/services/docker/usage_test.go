@@ -0,0 +1,82 @@
+package docker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsFromResponse(t *testing.T) {
+	read := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	resp := &container.StatsResponse{
+		Stats: container.Stats{
+			Read:        read,
+			PidsStats:   container.PidsStats{Current: 12},
+			MemoryStats: container.MemoryStats{Usage: 256 << 20, Limit: 1024 << 20},
+			BlkioStats: container.BlkioStats{
+				IoServiceBytesRecursive: []container.BlkioStatEntry{
+					{Op: "Read", Value: 100},
+					{Op: "Write", Value: 200},
+					{Op: "read", Value: 1},
+					{Op: "write", Value: 2},
+					{Op: "Total", Value: 303},
+				},
+			},
+		},
+		Networks: map[string]container.NetworkStats{
+			"eth0": {RxBytes: 1000, TxBytes: 500},
+			"eth1": {RxBytes: 10, TxBytes: 5},
+		},
+	}
+
+	stats := statsFromResponse(resp)
+
+	assert.Equal(t, 256.0, stats.MemoryMB)
+	assert.Equal(t, 1024.0, stats.MemoryLimitMB)
+	assert.Equal(t, uint64(12), stats.PIDs)
+	assert.Equal(t, uint64(1010), stats.NetworkRxBytes)
+	assert.Equal(t, uint64(505), stats.NetworkTxBytes)
+	assert.Equal(t, uint64(101), stats.BlockReadBytes)
+	assert.Equal(t, uint64(202), stats.BlockWriteBytes)
+	assert.Equal(t, read, stats.Read)
+}
+
+func TestUsageFrom(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	prev := &Stats{
+		NetworkRxBytes: 1000, NetworkTxBytes: 2000,
+		BlockReadBytes: 0, BlockWriteBytes: 4096,
+		Read: start,
+	}
+	cur := &Stats{
+		CPUPercent: 5, MemoryMB: 256, MemoryLimitMB: 1024, PIDs: 7,
+		NetworkRxBytes: 21000, NetworkTxBytes: 2500,
+		BlockReadBytes: 10240, BlockWriteBytes: 4096,
+		Read: start.Add(10 * time.Second),
+	}
+
+	usage := UsageFrom(cur, prev)
+
+	assert.Equal(t, 25.0, usage.MemoryPercent)
+	assert.Equal(t, uint64(7), usage.PIDs)
+	assert.Equal(t, 2000.0, usage.NetRxBytesPerSec)
+	assert.Equal(t, 50.0, usage.NetTxBytesPerSec)
+	assert.Equal(t, 1024.0, usage.BlockReadBytesPerSec)
+	assert.Zero(t, usage.BlockWriteBytesPerSec)
+}
+
+func TestUsageFrom_NoRates(t *testing.T) {
+	cur := &Stats{NetworkRxBytes: 500, Read: time.Now()}
+
+	// First sample
+	assert.Zero(t, UsageFrom(cur, nil).NetRxBytesPerSec)
+
+	// Counters reset when the container restarted
+	prev := &Stats{NetworkRxBytes: 1000, Read: cur.Read.Add(-10 * time.Second)}
+	assert.Zero(t, UsageFrom(cur, prev).NetRxBytesPerSec)
+
+	// Same sample twice
+	assert.Zero(t, UsageFrom(cur, cur).NetRxBytesPerSec)
+}
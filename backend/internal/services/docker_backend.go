@@ -0,0 +1,416 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"home-run-backend/internal/cache"
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/logger"
+	"home-run-backend/internal/models"
+	"home-run-backend/internal/services/docker"
+	imageregistry "home-run-backend/internal/services/registry"
+
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	RegisterBackend("docker", newDockerBackend, validateDockerService)
+}
+
+// dockerDaemon is a connection to one Docker daemon and its stats collector
+type dockerDaemon struct {
+	client  *docker.Client
+	stats   *docker.StatsCollector
+	updates *docker.UpdateChecker // nil unless image_updates is enabled
+}
+
+func newDockerDaemon(client *docker.Client, services []config.ServiceConfig) *dockerDaemon {
+	return &dockerDaemon{
+		client: client,
+		stats:  docker.NewStatsCollector(client, cache.New(30*time.Second), services, 10*time.Second),
+	}
+}
+
+// populate fills in status of a container on this daemon
+func (d *dockerDaemon) populate(ctx context.Context, svc *models.Service, containerName string) {
+	// Try cache first
+	if cached := d.stats.GetCachedStats(containerName); cached != nil {
+		applyContainerInfo(svc, cached.ContainerInfo)
+		applyUsage(svc, cached.Usage)
+		svc.UpdateAvailable = d.updateAvailable(containerName)
+		return
+	}
+
+	// Fallback to live query
+	info, err := d.client.GetContainerInfo(ctx, containerName)
+	if err != nil {
+		svc.Status = "ERROR"
+		return
+	}
+
+	applyContainerInfo(svc, *info)
+	svc.UpdateAvailable = d.updateAvailable(containerName)
+
+	// Try to get stats
+	if info.Status == "RUNNING" {
+		// A single sample has no I/O rates
+		if stats, err := d.client.GetContainerStats(ctx, info.ID); err == nil {
+			applyUsage(svc, docker.UsageFrom(stats, nil))
+		}
+	}
+}
+
+// populateProject fills in the rolled-up status of a compose project on this daemon
+func (d *dockerDaemon) populateProject(ctx context.Context, svc *models.Service, project string) {
+	info := d.stats.GetProjectStats(project)
+	if info == nil {
+		var err error
+		if info, err = d.client.GetProjectInfo(ctx, project); err != nil {
+			svc.Status = "ERROR"
+			return
+		}
+	}
+
+	svc.Status = info.Status
+	svc.Uptime = formatUptime(info.StartedAt)
+	applyUsage(svc, info.Usage)
+	for _, ctr := range info.Containers {
+		svc.Containers = append(svc.Containers, models.Container{
+			Name:    ctr.Name,
+			Service: ctr.Service,
+			Status:  ctr.Status,
+		})
+		svc.UpdateAvailable = svc.UpdateAvailable || d.updateAvailable(ctr.Name)
+	}
+}
+
+// updateAvailable reports whether the registry has a newer image for a container
+func (d *dockerDaemon) updateAvailable(containerName string) bool {
+	if d.updates == nil {
+		return false
+	}
+	update, ok := d.updates.Get(containerName)
+	return ok && update.UpdateAvailable
+}
+
+// applyUsage fills in resource usage
+func applyUsage(svc *models.Service, usage docker.Usage) {
+	svc.CPUUsage = usage.CPUPercent
+	svc.MemoryUsage = usage.MemoryMB
+	svc.MemoryLimit = usage.MemoryLimitMB
+	svc.MemoryPercent = usage.MemoryPercent
+	svc.NetworkRx = usage.NetRxBytesPerSec
+	svc.NetworkTx = usage.NetTxBytesPerSec
+	svc.BlockRead = usage.BlockReadBytesPerSec
+	svc.BlockWrite = usage.BlockWriteBytesPerSec
+	svc.PIDs = usage.PIDs
+}
+
+// applyContainerInfo fills in status, uptime, health and restart counters
+func applyContainerInfo(svc *models.Service, info docker.ContainerInfo) {
+	svc.Status = info.ServiceStatus(time.Now())
+	svc.Uptime = formatUptime(info.StartedAt)
+	svc.RestartCount = info.RestartCount
+	svc.OOMKilled = info.OOMKilled
+
+	if info.Health == "" {
+		return
+	}
+	svc.Health = &models.Health{
+		Status:        info.Health,
+		FailingStreak: info.FailingStreak,
+	}
+	for _, probe := range info.HealthLog {
+		svc.Health.Log = append(svc.Health.Log, models.HealthProbe{
+			Start:    probe.Start.Format(time.RFC3339),
+			ExitCode: probe.ExitCode,
+			Output:   probe.Output,
+		})
+	}
+}
+
+// errComposeProject is returned for single-container operations on a compose project
+var errComposeProject = fmt.Errorf("%w: compose projects have several containers", ErrNotContainer)
+
+// dockerBackend reports status of containers on the local daemon and any
+// configured docker_hosts, with one stats collector per daemon
+type dockerBackend struct {
+	daemons map[string]*dockerDaemon // keyed by docker_host, "" is the local daemon
+}
+
+func newDockerBackend(cfg *config.Config, services []config.ServiceConfig) (StatusBackend, error) {
+	byHost := make(map[string][]config.ServiceConfig)
+	for _, svc := range services {
+		byHost[svc.DockerHost] = append(byHost[svc.DockerHost], svc)
+	}
+
+	// Discovery searches every daemon, even those without configured services
+	if cfg.DockerDiscovery.Enabled {
+		hosts := []string{""}
+		for name := range cfg.DockerHosts {
+			hosts = append(hosts, name)
+		}
+		for _, name := range hosts {
+			if _, ok := byHost[name]; !ok {
+				byHost[name] = nil
+			}
+		}
+	}
+
+	b := &dockerBackend{daemons: make(map[string]*dockerDaemon)}
+	registryClient := imageregistry.NewClient(cfg.ImageUpdates.Registries)
+	for name, hostServices := range byHost {
+		// Daemons that aren't reachable yet are kept and reconnected by Watch;
+		// only clients that can't be created at all (bad TLS or ssh config) are dropped
+		var client *docker.Client
+		var err error
+		if name == "" {
+			client, err = docker.OpenClient()
+		} else {
+			client, err = docker.OpenClientFromConfig(cfg.DockerHosts[name])
+		}
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"docker_host": name,
+				"error":       err.Error(),
+			}).Warn("Docker daemon unavailable")
+			continue
+		}
+		d := newDockerDaemon(client, hostServices)
+		if cfg.DockerDiscovery.Enabled {
+			d.stats.EnableDiscovery(name)
+		}
+		if cfg.ImageUpdates.Enabled {
+			d.updates = docker.NewUpdateChecker(client, registryClient, d.stats, cfg.ImageUpdates.Interval)
+		}
+		b.daemons[name] = d
+	}
+
+	if len(b.daemons) == 0 {
+		return nil, errors.New("no docker daemon available")
+	}
+	return b, nil
+}
+
+// validateDockerService checks the container, compose and docker_host fields of a docker service
+func validateDockerService(cfg *config.Config, index int, svc config.ServiceConfig) error {
+	if (svc.ContainerName == "") == (svc.ComposeProject == "") {
+		return fmt.Errorf("services[%d] needs exactly one of container_name or compose_project for docker backend", index)
+	}
+	if svc.ComposeProject != "" && svc.AllowActions {
+		return fmt.Errorf("services[%d].allow_actions is not supported for compose_project", index)
+	}
+	if heal := svc.AutoHeal; heal != nil {
+		if svc.ComposeProject != "" {
+			return fmt.Errorf("services[%d].auto_heal is not supported for compose_project", index)
+		}
+		if heal.MaxRestarts < 0 || heal.Window < 0 || heal.Cooldown < 0 {
+			return fmt.Errorf("services[%d].auto_heal max_restarts, window and cooldown must not be negative", index)
+		}
+	}
+	if svc.DockerHost != "" {
+		if _, ok := cfg.DockerHosts[svc.DockerHost]; !ok {
+			return fmt.Errorf("services[%d].docker_host '%s' is not defined in docker_hosts", index, svc.DockerHost)
+		}
+	}
+	return nil
+}
+
+// Start starts the health watchers, stats collectors and update checkers
+func (b *dockerBackend) Start(ctx context.Context) {
+	for _, d := range b.daemons {
+		go d.client.Watch(ctx)
+		d.stats.Start(ctx)
+		if d.updates != nil {
+			d.updates.Start(ctx)
+		}
+	}
+}
+
+// Stop stops the stats collectors and update checkers and closes the Docker clients
+func (b *dockerBackend) Stop() {
+	for _, d := range b.daemons {
+		if d.updates != nil {
+			d.updates.Stop()
+		}
+		d.stats.Stop()
+		d.client.Close()
+	}
+}
+
+// Discovered returns the labelled containers found on all daemons
+func (b *dockerBackend) Discovered() []config.ServiceConfig {
+	var result []config.ServiceConfig
+	for _, d := range b.daemons {
+		result = append(result, d.stats.Discovered()...)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Populate fills in status from the service's Docker daemon. Services on an
+// unreachable daemon are UNKNOWN rather than failed.
+func (b *dockerBackend) Populate(ctx context.Context, svc *models.Service, cfg config.ServiceConfig) {
+	d, ok := b.daemons[cfg.DockerHost]
+	if !ok {
+		svc.Status = "ERROR"
+		return
+	}
+	if !d.client.Connected() {
+		svc.Status = "UNKNOWN"
+		return
+	}
+	if cfg.ComposeProject != "" {
+		d.populateProject(ctx, svc, cfg.ComposeProject)
+		return
+	}
+	d.populate(ctx, svc, cfg.ContainerName)
+}
+
+// ConfigFiles returns the compose files of a compose project. Files are only
+// readable for projects on the local daemon.
+func (b *dockerBackend) ConfigFiles(cfg config.ServiceConfig) []string {
+	d, ok := b.daemons[cfg.DockerHost]
+	if !ok || cfg.ComposeProject == "" || cfg.DockerHost != "" {
+		return nil
+	}
+	if info := d.stats.GetProjectStats(cfg.ComposeProject); info != nil {
+		return info.ConfigFiles
+	}
+	return nil
+}
+
+// Act runs a lifecycle action on the service's container
+func (b *dockerBackend) Act(ctx context.Context, cfg config.ServiceConfig, action string) (*docker.ContainerInfo, error) {
+	if cfg.ComposeProject != "" {
+		return nil, errComposeProject
+	}
+	d, err := b.daemon(cfg.DockerHost)
+	if err != nil {
+		return nil, err
+	}
+	return d.client.ContainerAction(ctx, cfg.ContainerName, action)
+}
+
+// Logs opens the log of the service's container
+func (b *dockerBackend) Logs(ctx context.Context, cfg config.ServiceConfig, opts docker.LogOptions) (*docker.LogStream, error) {
+	if cfg.ComposeProject != "" {
+		return nil, errComposeProject
+	}
+	d, err := b.daemon(cfg.DockerHost)
+	if err != nil {
+		return nil, err
+	}
+	return d.client.ContainerLogs(ctx, cfg.ContainerName, opts)
+}
+
+// Inspect describes the service's container
+func (b *dockerBackend) Inspect(ctx context.Context, cfg config.ServiceConfig) (*docker.ContainerDetails, error) {
+	if cfg.ComposeProject != "" {
+		return nil, errComposeProject
+	}
+	d, err := b.daemon(cfg.DockerHost)
+	if err != nil {
+		return nil, err
+	}
+	return d.client.InspectContainer(ctx, cfg.ContainerName)
+}
+
+// HealEvents returns the auto-heal events of all daemons, newest first
+func (b *dockerBackend) HealEvents() []docker.HealEvent {
+	result := []docker.HealEvent{}
+	for _, d := range b.daemons {
+		result = append(result, d.stats.HealEvents()...)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.After(result[j].Time)
+	})
+	return result
+}
+
+// DiskUsage returns the disk usage of every reachable daemon, local first.
+// A daemon that fails to report is logged and left out.
+func (b *dockerBackend) DiskUsage(ctx context.Context) ([]*docker.DiskUsage, error) {
+	result := make([]*docker.DiskUsage, 0, len(b.daemons))
+	for _, name := range b.hosts() {
+		d := b.daemons[name]
+		if !d.client.Connected() {
+			continue
+		}
+		usage, err := d.client.DiskUsage(ctx)
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"docker_host": name,
+				"error":       err.Error(),
+			}).Warn("Failed to get docker disk usage")
+			continue
+		}
+		usage.DockerHost = name
+		result = append(result, usage)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%w: no docker daemon is reachable", ErrDockerUnavailable)
+	}
+	return result, nil
+}
+
+// Prune removes unused data on one daemon, keeping the stopped containers
+// of the services monitored there
+func (b *dockerBackend) Prune(ctx context.Context, dockerHost string, opts docker.PruneOptions) (*docker.PruneReport, error) {
+	d, err := b.daemon(dockerHost)
+	if err != nil {
+		return nil, err
+	}
+	return d.client.Prune(ctx, opts, d.stats.Monitored())
+}
+
+// daemon returns a connected daemon by its docker_hosts key
+func (b *dockerBackend) daemon(dockerHost string) (*dockerDaemon, error) {
+	d, ok := b.daemons[dockerHost]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown docker host '%s'", ErrDockerUnavailable, dockerHost)
+	}
+	if !d.client.Connected() {
+		return nil, fmt.Errorf("%w: %s", ErrDockerUnavailable, d.client.Health().Error)
+	}
+	return d, nil
+}
+
+// Health returns the connection state of every daemon, local first
+func (b *dockerBackend) Health() []models.BackendHealth {
+	result := make([]models.BackendHealth, 0, len(b.daemons))
+	for _, name := range b.hosts() {
+		result = append(result, backendHealth("docker", name, b.daemons[name].client.Health()))
+	}
+	return result
+}
+
+// backendHealth converts the connection state of a Docker API client
+func backendHealth(backend, name string, health docker.Health) models.BackendHealth {
+	return models.BackendHealth{
+		Backend:   backend,
+		Name:      name,
+		Address:   health.Host,
+		Connected: health.Connected,
+		Error:     health.Error,
+		Attempts:  health.Attempts,
+		Since:     health.Since.Format(time.RFC3339),
+		LastCheck: health.LastCheck.Format(time.RFC3339),
+	}
+}
+
+// hosts returns the docker_hosts keys of the daemons, sorted so the local daemon comes first
+func (b *dockerBackend) hosts() []string {
+	hosts := make([]string, 0, len(b.daemons))
+	for name := range b.daemons {
+		hosts = append(hosts, name)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
@@ -0,0 +1,177 @@
+package host
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"home-run-backend/internal/history"
+	"home-run-backend/internal/logger"
+	"home-run-backend/internal/models"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+const (
+	// Interval is how often the host is sampled
+	Interval = 5 * time.Second
+	// Window is how long samples are kept in memory
+	Window = time.Hour
+)
+
+const gb = 1024 * 1024 * 1024
+
+// Collector samples host CPU, memory and storage in the background and keeps
+// the samples of the last Window in a ring buffer. It is the only caller of
+// cpu.Percent, so every CPU reading covers exactly one interval.
+type Collector struct {
+	interval time.Duration
+	ring     *history.Ring
+	latest   models.HostStats
+	mu       sync.RWMutex
+	running  bool
+	stopCh   chan struct{}
+}
+
+// NewCollector creates a collector that samples every interval and keeps
+// window worth of samples
+func NewCollector(interval, window time.Duration) *Collector {
+	return &Collector{
+		interval: interval,
+		ring:     history.NewRing(int(window / interval)),
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start takes a first sample and keeps sampling in the background
+func (c *Collector) Start(ctx context.Context) {
+	c.mu.Lock()
+	if c.running {
+		c.mu.Unlock()
+		return
+	}
+	c.running = true
+	c.mu.Unlock()
+
+	logger.WithField("interval", c.interval).Info("Starting host collector")
+
+	cores, threads := cpuInfo()
+	c.mu.Lock()
+	c.latest.CPU.Cores, c.latest.CPU.Threads = cores, threads
+	c.mu.Unlock()
+	c.collect(time.Now())
+
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.stopCh:
+				return
+			case now := <-ticker.C:
+				c.collect(now)
+			}
+		}
+	}()
+}
+
+// Stop stops sampling
+func (c *Collector) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.running {
+		close(c.stopCh)
+		c.running = false
+	}
+}
+
+// Stats returns the latest sample
+func (c *Collector) Stats() models.HostStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.latest
+}
+
+// History averages a metric of the samples in memory into steps
+func (c *Collector) History(metric history.Metric, span, step time.Duration, to time.Time) (*history.Series, error) {
+	return c.ring.Query(metric, span, step, to)
+}
+
+// Average returns the average of the samples taken since from, and false if
+// there are none
+func (c *Collector) Average(from time.Time) (history.Sample, bool) {
+	samples := c.ring.Since(from)
+	if len(samples) == 0 {
+		return history.Sample{}, false
+	}
+
+	avg := history.Sample{ServiceID: history.HostID, Status: "RUNNING"}
+	for _, sample := range samples {
+		avg.CPU += sample.CPU
+		avg.Memory += sample.Memory
+	}
+	avg.CPU /= float64(len(samples))
+	avg.Memory /= float64(len(samples))
+	return avg, true
+}
+
+// collect takes one sample
+func (c *Collector) collect(now time.Time) {
+	c.mu.RLock()
+	stats := c.latest
+	c.mu.RUnlock()
+
+	if percent, err := cpu.Percent(0, false); err != nil {
+		logger.WithField("error", err.Error()).Warn("Failed to get CPU usage")
+	} else if len(percent) > 0 {
+		stats.CPU.Usage = percent[0]
+	}
+
+	var usedMB float64
+	if memInfo, err := mem.VirtualMemory(); err != nil {
+		logger.WithField("error", err.Error()).Warn("Failed to get memory info")
+	} else {
+		stats.Memory.UsedGB = float64(memInfo.Used) / gb
+		stats.Memory.TotalGB = float64(memInfo.Total) / gb
+		usedMB = float64(memInfo.Used) / (1024 * 1024)
+	}
+
+	// Disk usage of the root partition
+	if diskInfo, err := disk.Usage("/"); err != nil {
+		logger.WithField("error", err.Error()).Warn("Failed to get disk info")
+	} else {
+		stats.Storage.UsedGB = float64(diskInfo.Used) / gb
+		stats.Storage.TotalGB = float64(diskInfo.Total) / gb
+	}
+
+	c.mu.Lock()
+	c.latest = stats
+	c.mu.Unlock()
+
+	c.ring.Add(history.Sample{
+		ServiceID: history.HostID,
+		Time:      now,
+		Status:    "RUNNING",
+		CPU:       stats.CPU.Usage,
+		Memory:    usedMB,
+	})
+}
+
+// cpuInfo returns the number of CPUs and of their cores
+func cpuInfo() (int, int) {
+	info, err := cpu.Info()
+	if err != nil {
+		logger.WithField("error", err.Error()).Warn("Failed to get CPU info")
+		return 0, 0
+	}
+	threads := 0
+	for _, i := range info {
+		threads += int(i.Cores)
+	}
+	return len(info), threads
+}
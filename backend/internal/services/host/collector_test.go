@@ -0,0 +1,50 @@
+package host
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"home-run-backend/internal/history"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector_Start(t *testing.T) {
+	c := NewCollector(time.Hour, 2*time.Hour)
+	c.Start(context.Background())
+	defer c.Stop()
+
+	// The first sample is taken right away
+	stats := c.Stats()
+	assert.Greater(t, stats.Memory.TotalGB, 0.0)
+	assert.Greater(t, stats.CPU.Cores, 0)
+
+	avg, ok := c.Average(time.Now().Add(-time.Minute))
+	require.True(t, ok)
+	assert.Equal(t, history.HostID, avg.ServiceID)
+	assert.Greater(t, avg.Memory, 0.0)
+}
+
+func TestCollector_Average(t *testing.T) {
+	c := NewCollector(time.Second, 3*time.Second)
+	now := time.Now()
+
+	_, ok := c.Average(now.Add(-time.Minute))
+	assert.False(t, ok)
+
+	for i, cpu := range []float64{90, 10, 20, 30} {
+		c.ring.Add(history.Sample{Time: now.Add(time.Duration(i-3) * time.Second), CPU: cpu, Memory: 100 * cpu})
+	}
+
+	avg, ok := c.Average(now.Add(-time.Minute))
+	require.True(t, ok)
+	assert.Equal(t, 20.0, avg.CPU) // 90 no longer fits in the window
+	assert.Equal(t, 2000.0, avg.Memory)
+	assert.Equal(t, "RUNNING", avg.Status)
+
+	avg, ok = c.Average(now.Add(-time.Second))
+	require.True(t, ok)
+	assert.Equal(t, 25.0, avg.CPU)
+}
@@ -1,20 +1,17 @@
 package kuma
 
 import (
-	"bufio"
 	"context"
 	"fmt"
-	"io"
 	"net/http"
-	"regexp"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"home-run-backend/internal/config"
 	"home-run-backend/internal/logger"
-
-	"github.com/sirupsen/logrus"
 )
 
 // MonitorStatus holds the status of an Uptime Kuma monitor
@@ -22,10 +19,20 @@ type MonitorStatus struct {
 	ID      int
 	Name    string
 	Status  string  // RUNNING, STOPPED, ERROR, MAINTENANCE
-	Uptime  float64 // percentage (0-100)
 	Latency float64 // ms
 }
 
+// Monitor describes an Uptime Kuma monitor as exposed by its metrics labels
+type Monitor struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`               // http, keyword, port, ping, dns, ...
+	URL      string `json:"url,omitempty"`      // http and keyword monitors
+	Hostname string `json:"hostname,omitempty"` // port, ping and dns monitors
+	Port     int    `json:"port,omitempty"`
+	Service  string `json:"service,omitempty"` // configured service with this kuma_monitor_id
+}
+
 // Client is the Uptime Kuma API client
 type Client struct {
 	baseURL    string
@@ -48,131 +55,138 @@ func NewClient(cfg *config.UptimeKumaConfig) *Client {
 	}
 }
 
-// GetMonitorStatus fetches the status of a specific monitor from the /metrics endpoint
-func (c *Client) GetMonitorStatus(ctx context.Context, monitorID int) (*MonitorStatus, error) {
-	url := fmt.Sprintf("%s/metrics", c.baseURL)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// Scrape fetches the /metrics endpoint once and returns the status of every
+// monitor in it, by monitor ID
+func (c *Client) Scrape(ctx context.Context) (map[int]*MonitorStatus, error) {
+	samples, err := c.fetchMetrics(ctx)
 	if err != nil {
-		logger.WithFields(logrus.Fields{
-			"monitor_id": monitorID,
-			"error":      err.Error(),
-		}).Error("Failed to create Kuma request")
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	// Set authentication
-	if c.apiKey != "" {
-		req.SetBasicAuth("", c.apiKey)
-	} else if c.username != "" && c.password != "" {
-		req.SetBasicAuth(c.username, c.password)
+	monitors := monitorsFromSamples(samples)
+	logger.WithField("monitors", len(monitors)).Debug("Scraped Uptime Kuma metrics")
+	return monitors, nil
+}
+
+// Monitors lists every monitor in the /metrics endpoint, sorted by ID
+func (c *Client) Monitors(ctx context.Context) ([]Monitor, error) {
+	samples, err := c.fetchMetrics(ctx)
+	if err != nil {
+		return nil, err
 	}
+	return monitorsFromLabels(samples), nil
+}
 
-	logger.WithField("monitor_id", monitorID).Debug("Fetching monitor status from Uptime Kuma")
+// fetchMetrics downloads and parses the /metrics endpoint
+func (c *Client) fetchMetrics(ctx context.Context) ([]Sample, error) {
+	req, err := c.newRequest(ctx, "/metrics")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
 
+	logger.Log.Debug("Scraping Uptime Kuma metrics")
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		logger.WithFields(logrus.Fields{
-			"monitor_id": monitorID,
-			"error":      err.Error(),
-		}).Warn("Failed to fetch Kuma metrics")
+		logger.WithField("error", err.Error()).Warn("Failed to fetch Kuma metrics")
 		return nil, fmt.Errorf("failed to fetch metrics: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		logger.WithFields(logrus.Fields{
-			"monitor_id": monitorID,
-			"status":     resp.StatusCode,
-		}).Warn("Kuma returned non-200 status")
+		logger.WithField("status", resp.StatusCode).Warn("Kuma returned non-200 status")
 		return nil, fmt.Errorf("kuma returned status %d", resp.StatusCode)
 	}
 
-	status, err := c.parseMetrics(resp.Body, monitorID)
+	samples, err := ParseMetrics(resp.Body)
 	if err != nil {
-		logger.WithFields(logrus.Fields{
-			"monitor_id": monitorID,
-			"error":      err.Error(),
-		}).Error("Failed to parse Kuma metrics")
+		logger.WithField("error", err.Error()).Error("Failed to parse Kuma metrics")
 		return nil, err
 	}
-
-	logger.WithFields(logrus.Fields{
-		"monitor_id": monitorID,
-		"status":     status.Status,
-	}).Debug("Retrieved monitor status from Kuma")
-
-	return status, nil
+	return samples, nil
 }
 
-// parseMetrics parses the Prometheus format metrics response
-func (c *Client) parseMetrics(body io.Reader, targetID int) (*MonitorStatus, error) {
-	scanner := bufio.NewScanner(body)
-	result := &MonitorStatus{ID: targetID}
-	found := false
-
-	// Regex to extract monitor_id from metric lines
-	// Example: monitor_status{monitor_name="Nginx",monitor_type="http",monitor_url="https://example.com",monitor_hostname="null",monitor_port="null"} 1
-	statusRegex := regexp.MustCompile(`monitor_status\{[^}]*monitor_name="([^"]*)"[^}]*\}\s+(\d+)`)
-	responseTimeRegex := regexp.MustCompile(`monitor_response_time\{[^}]*\}\s+([\d.]+)`)
-
-	// Uptime Kuma uses a different format - need to match by order or specific attributes
-	// Let's use a simpler approach: look for lines containing the monitor ID
-	monitorIDStr := strconv.Itoa(targetID)
-	idRegex := regexp.MustCompile(fmt.Sprintf(`monitor_\w+\{[^}]*monitor_id="%s"[^}]*\}`, monitorIDStr))
-
-	var statusValue int = -1
+// monitorsFromSamples collects the monitor_* samples of each monitor by its
+// monitor_id label. Example:
+// monitor_status{monitor_id="1",monitor_name="Nginx",monitor_type="http",monitor_url="https://example.com"} 1
+func monitorsFromSamples(samples []Sample) map[int]*MonitorStatus {
+	monitors := make(map[int]*MonitorStatus)
+	statusValues := make(map[int]int)
 
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		// Skip comments and empty lines
-		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+	for _, sample := range samples {
+		if !strings.HasPrefix(sample.Name, "monitor_") {
+			continue
+		}
+		id, err := strconv.Atoi(sample.Labels["monitor_id"])
+		if err != nil {
 			continue
 		}
 
-		// Check if this line is for our monitor
-		if idRegex.MatchString(line) {
-			found = true
-
-			// Extract monitor status
-			if strings.HasPrefix(line, "monitor_status{") {
-				parts := strings.Fields(line)
-				if len(parts) >= 2 {
-					if val, err := strconv.Atoi(parts[len(parts)-1]); err == nil {
-						statusValue = val
-					}
-				}
-				// Extract name from the line
-				if matches := statusRegex.FindStringSubmatch(line); len(matches) > 1 {
-					result.Name = matches[1]
-				}
-			}
+		monitor, ok := monitors[id]
+		if !ok {
+			monitor = &MonitorStatus{ID: id, Name: sample.Labels["monitor_name"]}
+			monitors[id] = monitor
+			statusValues[id] = -1
+		}
 
-			// Extract response time
-			if strings.HasPrefix(line, "monitor_response_time{") {
-				if matches := responseTimeRegex.FindStringSubmatch(line); len(matches) > 1 {
-					if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
-						result.Latency = val
-					}
-				}
+		switch sample.Name {
+		case "monitor_status":
+			statusValues[id] = int(sample.Value)
+		case "monitor_response_time":
+			// Monitors without a response yet report -1
+			if sample.Value > 0 {
+				monitor.Latency = sample.Value
 			}
-
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading metrics: %w", err)
+	for id, monitor := range monitors {
+		monitor.Status = mapKumaStatus(statusValues[id])
 	}
+	return monitors
+}
 
-	if !found {
-		return nil, fmt.Errorf("monitor ID %d not found in metrics", targetID)
+// monitorsFromLabels describes each monitor from the labels of its first
+// sample. Kuma fills labels that don't apply to a monitor type with "null",
+// and the URL of non-http monitors with "https://".
+func monitorsFromLabels(samples []Sample) []Monitor {
+	seen := make(map[int]bool)
+	monitors := []Monitor{}
+
+	for _, sample := range samples {
+		if !strings.HasPrefix(sample.Name, "monitor_") {
+			continue
+		}
+		id, err := strconv.Atoi(sample.Labels["monitor_id"])
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		monitor := Monitor{
+			ID:       id,
+			Name:     sample.Labels["monitor_name"],
+			Type:     labelValue(sample.Labels["monitor_type"]),
+			Hostname: labelValue(sample.Labels["monitor_hostname"]),
+		}
+		if u, err := url.Parse(labelValue(sample.Labels["monitor_url"])); err == nil && u.Host != "" {
+			monitor.URL = u.String()
+		}
+		if port, err := strconv.Atoi(sample.Labels["monitor_port"]); err == nil {
+			monitor.Port = port
+		}
+		monitors = append(monitors, monitor)
 	}
 
-	// Map status value to our status enum
-	result.Status = mapKumaStatus(statusValue)
+	sort.Slice(monitors, func(i, j int) bool { return monitors[i].ID < monitors[j].ID })
+	return monitors
+}
 
-	return result, nil
+// labelValue maps Kuma's "null" placeholder to an empty string
+func labelValue(value string) string {
+	if value == "null" {
+		return ""
+	}
+	return value
 }
 
 // mapKumaStatus converts Uptime Kuma status values to our status enum
@@ -198,19 +212,11 @@ func mapKumaStatus(value int) string {
 
 // Ping tests connectivity to the Uptime Kuma instance
 func (c *Client) Ping(ctx context.Context) error {
-	url := fmt.Sprintf("%s/metrics", c.baseURL)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	req, err := c.newRequest(ctx, "/metrics")
 	if err != nil {
 		return err
 	}
 
-	if c.apiKey != "" {
-		req.SetBasicAuth("", c.apiKey)
-	} else if c.username != "" && c.password != "" {
-		req.SetBasicAuth(c.username, c.password)
-	}
-
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
@@ -223,3 +229,19 @@ func (c *Client) Ping(ctx context.Context) error {
 
 	return nil
 }
+
+// newRequest creates an authenticated GET request for a path
+func (c *Client) newRequest(ctx context.Context, path string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set authentication
+	if c.apiKey != "" {
+		req.SetBasicAuth("", c.apiKey)
+	} else if c.username != "" && c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	return req, nil
+}
@@ -1,9 +1,17 @@
 package kuma
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"home-run-backend/internal/config"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMapKumaStatus(t *testing.T) {
@@ -26,3 +34,94 @@ func TestMapKumaStatus(t *testing.T) {
 		})
 	}
 }
+
+// kumaMetrics is a /metrics page as served by Uptime Kuma 2
+const kumaMetrics = `# HELP monitor_cert_days_remaining The number of days remaining until the certificate expires
+# TYPE monitor_cert_days_remaining gauge
+monitor_cert_days_remaining{monitor_id="1",monitor_name="Nginx",monitor_type="http",monitor_url="https://example.com",monitor_hostname="null",monitor_port="null"} 79
+# HELP monitor_response_time Monitor Response Time (ms)
+# TYPE monitor_response_time gauge
+monitor_response_time{monitor_id="1",monitor_name="Nginx",monitor_type="http",monitor_url="https://example.com",monitor_hostname="null",monitor_port="null"} 57
+monitor_response_time{monitor_id="2",monitor_name="Plex \"Media\" Server",monitor_type="port",monitor_url="https://",monitor_hostname="nas.lan",monitor_port="32400"} -1
+# HELP monitor_status Monitor Status (1 = UP, 0= DOWN, 2= PENDING, 3= MAINTENANCE)
+# TYPE monitor_status gauge
+monitor_status{monitor_id="1",monitor_name="Nginx",monitor_type="http",monitor_url="https://example.com",monitor_hostname="null",monitor_port="null"} 1
+monitor_status{monitor_id="2",monitor_name="Plex \"Media\" Server",monitor_type="port",monitor_url="https://",monitor_hostname="nas.lan",monitor_port="32400"} 0
+process_cpu_seconds_total 12.5
+`
+
+func TestClient_Scrape(t *testing.T) {
+	var scrapes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scrapes.Add(1)
+		_, pass, ok := r.BasicAuth()
+		if !ok || pass != "uk_key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(kumaMetrics))
+	}))
+	defer server.Close()
+
+	monitors, err := NewClient(&config.UptimeKumaConfig{URL: server.URL + "/", APIKey: "uk_key"}).Scrape(context.Background())
+	require.NoError(t, err)
+	require.Len(t, monitors, 2)
+
+	assert.Equal(t, MonitorStatus{ID: 1, Name: "Nginx", Status: "RUNNING", Latency: 57}, *monitors[1])
+	assert.Equal(t, MonitorStatus{ID: 2, Name: `Plex "Media" Server`, Status: "STOPPED"}, *monitors[2])
+	assert.EqualValues(t, 1, scrapes.Load())
+
+	_, err = NewClient(&config.UptimeKumaConfig{URL: server.URL, APIKey: "wrong"}).Scrape(context.Background())
+	assert.ErrorContains(t, err, "status 401")
+}
+
+func TestPoller(t *testing.T) {
+	var scrapes atomic.Int32
+	var down atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scrapes.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(kumaMetrics))
+	}))
+	defer server.Close()
+
+	p := NewPoller(NewClient(&config.UptimeKumaConfig{URL: server.URL}), time.Hour)
+	_, err := p.Get(1)
+	assert.ErrorIs(t, err, ErrNoSnapshot)
+
+	p.Start(context.Background())
+	defer p.Stop()
+
+	// Every monitor is served from one scrape
+	for _, id := range []int{1, 2, 1, 2} {
+		_, err := p.Get(id)
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 1, scrapes.Load())
+
+	_, err = p.Get(3)
+	assert.ErrorContains(t, err, "monitor ID 3 not found")
+
+	down.Store(true)
+	p.scrape(context.Background())
+	_, err = p.Get(1)
+	assert.ErrorContains(t, err, "status 502")
+}
+
+func TestClient_Monitors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(kumaMetrics))
+	}))
+	defer server.Close()
+
+	monitors, err := NewClient(&config.UptimeKumaConfig{URL: server.URL}).Monitors(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []Monitor{
+		{ID: 1, Name: "Nginx", Type: "http", URL: "https://example.com"},
+		{ID: 2, Name: `Plex "Media" Server`, Type: "port", Hostname: "nas.lan", Port: 32400},
+	}, monitors)
+}
@@ -0,0 +1,178 @@
+package kuma
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Sample is one sample of the Prometheus text exposition format
+type Sample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// ParseMetrics reads samples in the Prometheus text exposition format.
+// Comments, HELP and TYPE lines are skipped and timestamps are ignored.
+func ParseMetrics(r io.Reader) ([]Sample, error) {
+	scanner := bufio.NewScanner(r)
+	// Monitor URLs end up in labels, so lines can be long
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var samples []Sample
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		sample, err := parseSample(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metrics line %d: %w", lineNo, err)
+		}
+		samples = append(samples, sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading metrics: %w", err)
+	}
+	return samples, nil
+}
+
+// parseSample parses `name{label="value",...} value [timestamp]`
+func parseSample(line string) (Sample, error) {
+	p := &lineParser{s: line}
+
+	name := p.name()
+	if name == "" {
+		return Sample{}, errors.New("missing metric name")
+	}
+	sample := Sample{Name: name, Labels: map[string]string{}}
+
+	p.skipSpace()
+	if p.peek() == '{' {
+		p.pos++
+		if err := p.labels(sample.Labels); err != nil {
+			return Sample{}, err
+		}
+	}
+
+	fields := strings.Fields(p.s[p.pos:])
+	if len(fields) == 0 || len(fields) > 2 {
+		return Sample{}, fmt.Errorf("expected a value after %s", name)
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return Sample{}, fmt.Errorf("invalid value '%s' for %s", fields[0], name)
+	}
+	sample.Value = value
+	return sample, nil
+}
+
+// lineParser reads one exposition line
+type lineParser struct {
+	s   string
+	pos int
+}
+
+func (p *lineParser) peek() byte {
+	if p.pos >= len(p.s) {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *lineParser) skipSpace() {
+	for p.peek() == ' ' || p.peek() == '\t' {
+		p.pos++
+	}
+}
+
+// name reads a metric or label name
+func (p *lineParser) name() string {
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		isLetter := c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		isDigit := c >= '0' && c <= '9'
+		if !isLetter && !(isDigit && p.pos > start) {
+			break
+		}
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+// labels reads label pairs up to and including the closing brace
+func (p *lineParser) labels(into map[string]string) error {
+	for {
+		p.skipSpace()
+		if p.peek() == '}' {
+			p.pos++
+			return nil
+		}
+
+		name := p.name()
+		if name == "" {
+			return fmt.Errorf("expected a label name at column %d", p.pos+1)
+		}
+		p.skipSpace()
+		if p.peek() != '=' {
+			return fmt.Errorf("expected '=' after label %s", name)
+		}
+		p.pos++
+		p.skipSpace()
+		if p.peek() != '"' {
+			return fmt.Errorf("expected a quoted value for label %s", name)
+		}
+		p.pos++
+		value, err := p.quoted()
+		if err != nil {
+			return fmt.Errorf("label %s: %w", name, err)
+		}
+		into[name] = value
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+		default:
+			return fmt.Errorf("expected ',' or '}' after label %s", name)
+		}
+	}
+}
+
+// quoted reads a label value after its opening quote, unescaping \\, \" and \n
+func (p *lineParser) quoted() (string, error) {
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if p.pos >= len(p.s) {
+				return "", errors.New("unterminated label value")
+			}
+			next := p.s[p.pos]
+			p.pos++
+			switch next {
+			case 'n':
+				b.WriteByte('\n')
+			case '\\', '"':
+				b.WriteByte(next)
+			default:
+				// Not a valid escape; keep it as written
+				b.WriteByte('\\')
+				b.WriteByte(next)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", errors.New("unterminated label value")
+}
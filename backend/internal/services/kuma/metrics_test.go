@@ -0,0 +1,67 @@
+package kuma
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMetrics(t *testing.T) {
+	input := `# HELP up Whether the target is up
+# TYPE up gauge
+
+up 1
+http_requests_total{method="post",code="200"} 1027 1395066363000
+  spaced_labels { a = "1" , b="2", } 3
+escaped{path="C:\\DIR\\FILE.TXT",msg="line1\nline2",quote="say \"hi\"",brace="}{,="} 1.5e3
+weird_escape{v="\d"} 0
+special{kind="nan"} NaN
+special{kind="inf"} +Inf
+special{kind="-inf"} -Inf
+`
+
+	samples, err := ParseMetrics(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, samples, 8)
+
+	assert.Equal(t, Sample{Name: "up", Labels: map[string]string{}, Value: 1}, samples[0])
+	assert.Equal(t, Sample{Name: "http_requests_total", Labels: map[string]string{"method": "post", "code": "200"}, Value: 1027}, samples[1])
+	assert.Equal(t, Sample{Name: "spaced_labels", Labels: map[string]string{"a": "1", "b": "2"}, Value: 3}, samples[2])
+	assert.Equal(t, map[string]string{
+		"path":  `C:\DIR\FILE.TXT`,
+		"msg":   "line1\nline2",
+		"quote": `say "hi"`,
+		"brace": "}{,=",
+	}, samples[3].Labels)
+	assert.Equal(t, 1500.0, samples[3].Value)
+	assert.Equal(t, `\d`, samples[4].Labels["v"])
+	assert.True(t, math.IsNaN(samples[5].Value))
+	assert.True(t, math.IsInf(samples[6].Value, 1))
+	assert.True(t, math.IsInf(samples[7].Value, -1))
+}
+
+func TestParseMetrics_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{"no value", `up`, "expected a value"},
+		{"bad value", `up one`, "invalid value"},
+		{"unterminated", `up{a="1} 1`, "unterminated label value"},
+		{"unquoted", `up{a=1} 1`, "expected a quoted value"},
+		{"missing comma", `up{a="1" b="2"} 1`, "expected ',' or '}'"},
+		{"no name", `{a="1"} 1`, "missing metric name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMetrics(strings.NewReader("ok 1\n" + tt.input))
+			assert.ErrorContains(t, err, "line 2")
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
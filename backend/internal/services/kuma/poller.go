@@ -0,0 +1,106 @@
+package kuma
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"home-run-backend/internal/logger"
+)
+
+// ErrNoSnapshot is returned before the first scrape has completed
+var ErrNoSnapshot = errors.New("uptime kuma has not been scraped yet")
+
+// Poller scrapes Uptime Kuma once per interval and serves every monitor from
+// the latest snapshot
+type Poller struct {
+	client   *Client
+	interval time.Duration
+	monitors map[int]*MonitorStatus
+	err      error // of the last scrape
+	mu       sync.RWMutex
+	running  bool
+	stopCh   chan struct{}
+}
+
+// NewPoller creates a poller that scrapes every interval
+func NewPoller(client *Client, interval time.Duration) *Poller {
+	return &Poller{
+		client:   client,
+		interval: interval,
+		err:      ErrNoSnapshot,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start scrapes once, then keeps scraping in the background
+func (p *Poller) Start(ctx context.Context) {
+	p.mu.Lock()
+	if p.running {
+		p.mu.Unlock()
+		return
+	}
+	p.running = true
+	p.mu.Unlock()
+
+	logger.WithField("interval", p.interval).Info("Starting Uptime Kuma poller")
+
+	// Scrape immediately so the first request has data
+	p.scrape(ctx)
+
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.stopCh:
+				return
+			case <-ticker.C:
+				p.scrape(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the poller
+func (p *Poller) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.running {
+		close(p.stopCh)
+		p.running = false
+	}
+}
+
+// Get returns a monitor from the latest snapshot. While Uptime Kuma can't be
+// scraped, the error of the last attempt is returned for every monitor.
+func (p *Poller) Get(monitorID int) (MonitorStatus, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.err != nil {
+		return MonitorStatus{}, p.err
+	}
+	monitor, ok := p.monitors[monitorID]
+	if !ok {
+		return MonitorStatus{}, fmt.Errorf("monitor ID %d not found in metrics", monitorID)
+	}
+	return *monitor, nil
+}
+
+// scrape replaces the snapshot with a fresh scrape
+func (p *Poller) scrape(ctx context.Context) {
+	monitors, err := p.client.Scrape(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+	if err == nil {
+		p.monitors = monitors
+	}
+}
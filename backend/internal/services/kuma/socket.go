@@ -0,0 +1,410 @@
+package kuma
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"home-run-backend/internal/backoff"
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// heartbeatHistory is the number of heartbeats kept per monitor, as many
+	// as Uptime Kuma sends on login
+	heartbeatHistory = 100
+	// loginTimeout bounds connecting and logging in
+	loginTimeout = 30 * time.Second
+)
+
+// reconnectBackoff spaces out failed sessions
+var reconnectBackoff = backoff.Exponential{Min: time.Second, Max: time.Minute}
+
+// ErrNotLoggedIn is returned before the first login has completed
+var ErrNotLoggedIn = errors.New("uptime kuma socket is not logged in yet")
+
+// Heartbeat is one check of a monitor
+type Heartbeat struct {
+	Time    time.Time
+	Status  string  // RUNNING, STOPPED, ERROR, MAINTENANCE
+	Ping    float64 // ms, 0 if none
+	Message string
+}
+
+// Certificate is the certificate Uptime Kuma saw on an https monitor
+type Certificate struct {
+	Valid         bool
+	DaysRemaining int
+	ValidTo       string
+	Issuer        string
+}
+
+// MonitorStats are the statistics Uptime Kuma pushes for a monitor
+type MonitorStats struct {
+	Uptime24h   *float64 // percentage (0-100), nil until reported
+	Uptime30d   *float64
+	AvgPing     float64     // ms over 24h
+	Heartbeats  []Heartbeat // oldest first
+	Certificate *Certificate
+}
+
+// Socket keeps a Socket.IO session with Uptime Kuma open and collects the
+// uptime, ping, heartbeat and certificate updates it pushes for every
+// monitor. The Socket.IO API only accepts username and password logins; API
+// keys are limited to /metrics.
+type Socket struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+	stats      map[int]*MonitorStats
+	err        error // why the last session ended, nil while logged in
+	mu         sync.RWMutex
+	running    bool
+	stopCh     chan struct{}
+}
+
+// NewSocket creates a Socket.IO client for an Uptime Kuma instance
+func NewSocket(cfg *config.UptimeKumaConfig) *Socket {
+	return &Socket{
+		baseURL:  strings.TrimSuffix(cfg.URL, "/"),
+		username: cfg.Username,
+		password: cfg.Password,
+		// Long-polls are bounded per request by the session's ping settings
+		httpClient: &http.Client{},
+		stats:      make(map[int]*MonitorStats),
+		err:        ErrNotLoggedIn,
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// Start connects in the background and reconnects with exponential backoff
+// whenever the session ends
+func (s *Socket) Start(ctx context.Context) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-s.stopCh:
+			cancel()
+		}
+	}()
+
+	logger.WithField("url", s.baseURL).Info("Starting Uptime Kuma socket")
+
+	go func() {
+		attempts := 0
+		for {
+			start := time.Now()
+			err := s.session(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+
+			s.mu.Lock()
+			s.err = err
+			s.mu.Unlock()
+
+			// A session that lasted a while was a success; start over
+			if time.Since(start) > reconnectBackoff.Max {
+				attempts = 0
+			}
+			attempts++
+			delay := reconnectBackoff.Delay(attempts)
+			logger.WithFields(logrus.Fields{
+				"error": err.Error(),
+				"retry": delay,
+			}).Warn("Uptime Kuma socket disconnected")
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+		}
+	}()
+}
+
+// Stop closes the session
+func (s *Socket) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		close(s.stopCh)
+		s.running = false
+	}
+}
+
+// Get returns the statistics of a monitor. While no session is logged in,
+// the error that ended the last one is returned.
+func (s *Socket) Get(monitorID int) (MonitorStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.err != nil {
+		return MonitorStats{}, s.err
+	}
+	stats, ok := s.stats[monitorID]
+	if !ok {
+		return MonitorStats{}, fmt.Errorf("monitor ID %d has no statistics", monitorID)
+	}
+
+	result := *stats
+	result.Heartbeats = append([]Heartbeat(nil), stats.Heartbeats...)
+	return result, nil
+}
+
+// session connects, logs in and handles events until the session ends
+func (s *Socket) session(ctx context.Context) error {
+	loginCtx, cancel := context.WithTimeout(ctx, loginTimeout)
+	defer cancel()
+
+	conn, err := dialSocket(loginCtx, s.httpClient, s.baseURL)
+	if err != nil {
+		return err
+	}
+	defer conn.close()
+
+	// Kuma pushes the full state around the login acknowledgement, so stale
+	// stats can go now
+	s.mu.Lock()
+	s.stats = make(map[int]*MonitorStats)
+	s.mu.Unlock()
+
+	result, err := conn.call(loginCtx, s.handleEvent, "login", map[string]string{
+		"username": s.username,
+		"password": s.password,
+		"token":    "", // 2FA is not supported
+	})
+	if err != nil {
+		return fmt.Errorf("uptime kuma login failed: %w", err)
+	}
+	var login struct {
+		OK  bool   `json:"ok"`
+		Msg string `json:"msg"`
+	}
+	if len(result) == 0 || json.Unmarshal(result[0], &login) != nil {
+		return errors.New("uptime kuma login failed: invalid response")
+	}
+	if !login.OK {
+		return fmt.Errorf("uptime kuma login failed: %s", login.Msg)
+	}
+
+	s.mu.Lock()
+	s.err = nil
+	s.mu.Unlock()
+	logger.WithField("url", s.baseURL).Info("Logged in to Uptime Kuma socket")
+
+	return conn.listen(ctx, s.handleEvent)
+}
+
+// handleEvent records the statistics in an event. Events and arguments:
+//
+//	heartbeatList(monitorID, heartbeats, overwrite)
+//	heartbeat(heartbeat)
+//	uptime(monitorID, period, ratio)  period 24 (hours) or 720 (30 days)
+//	avgPing(monitorID, ms)
+//	certInfo(monitorID, tlsInfoJSON)
+func (s *Socket) handleEvent(name string, args []json.RawMessage) {
+	switch name {
+	case "heartbeatList":
+		if len(args) < 2 {
+			return
+		}
+		id, ok := intArg(args[0])
+		var beats []kumaHeartbeat
+		if !ok || json.Unmarshal(args[1], &beats) != nil {
+			return
+		}
+		overwrite := len(args) > 2 && string(args[2]) == "true"
+
+		history := make([]Heartbeat, 0, len(beats))
+		for _, beat := range beats {
+			history = append(history, beat.heartbeat())
+		}
+
+		s.mu.Lock()
+		stats := s.statsFor(id)
+		if !overwrite {
+			history = append(history, stats.Heartbeats...)
+		}
+		stats.Heartbeats = trimHeartbeats(history)
+		s.mu.Unlock()
+
+	case "heartbeat":
+		var beat kumaHeartbeat
+		if len(args) < 1 || json.Unmarshal(args[0], &beat) != nil {
+			return
+		}
+		id, ok := intArg(beat.MonitorID)
+		if !ok {
+			return
+		}
+		s.mu.Lock()
+		stats := s.statsFor(id)
+		stats.Heartbeats = trimHeartbeats(append(stats.Heartbeats, beat.heartbeat()))
+		s.mu.Unlock()
+
+	case "uptime":
+		if len(args) < 3 {
+			return
+		}
+		id, ok := intArg(args[0])
+		period, _ := intArg(args[1])
+		var ratio float64
+		if !ok || json.Unmarshal(args[2], &ratio) != nil {
+			return
+		}
+		percent := ratio * 100
+		s.mu.Lock()
+		switch period {
+		case 24:
+			s.statsFor(id).Uptime24h = &percent
+		case 720:
+			s.statsFor(id).Uptime30d = &percent
+		}
+		s.mu.Unlock()
+
+	case "avgPing":
+		if len(args) < 2 {
+			return
+		}
+		id, ok := intArg(args[0])
+		var ping *float64
+		if !ok || json.Unmarshal(args[1], &ping) != nil {
+			return
+		}
+		s.mu.Lock()
+		s.statsFor(id).AvgPing = 0
+		if ping != nil {
+			s.statsFor(id).AvgPing = *ping
+		}
+		s.mu.Unlock()
+
+	case "certInfo":
+		if len(args) < 2 {
+			return
+		}
+		id, ok := intArg(args[0])
+		var raw string
+		if !ok || json.Unmarshal(args[1], &raw) != nil {
+			return
+		}
+		cert, err := parseCertInfo(raw)
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"monitor_id": id,
+				"error":      err.Error(),
+			}).Debug("Ignoring invalid Uptime Kuma certificate info")
+			return
+		}
+		s.mu.Lock()
+		s.statsFor(id).Certificate = cert
+		s.mu.Unlock()
+	}
+}
+
+// statsFor returns a monitor's statistics, creating them if needed. Callers hold mu.
+func (s *Socket) statsFor(id int) *MonitorStats {
+	stats, ok := s.stats[id]
+	if !ok {
+		stats = &MonitorStats{}
+		s.stats[id] = stats
+	}
+	return stats
+}
+
+// kumaHeartbeat is a heartbeat as sent by Uptime Kuma
+type kumaHeartbeat struct {
+	MonitorID json.RawMessage `json:"monitorID"`
+	Status    int             `json:"status"`
+	Time      string          `json:"time"` // UTC, e.g. 2024-05-01 12:00:00.123
+	Msg       string          `json:"msg"`
+	Ping      *float64        `json:"ping"`
+}
+
+func (b kumaHeartbeat) heartbeat() Heartbeat {
+	beat := Heartbeat{
+		Status:  mapKumaStatus(b.Status),
+		Message: b.Msg,
+	}
+	if b.Ping != nil {
+		beat.Ping = *b.Ping
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05.999", time.RFC3339Nano} {
+		if t, err := time.Parse(layout, b.Time); err == nil {
+			beat.Time = t
+			break
+		}
+	}
+	return beat
+}
+
+// trimHeartbeats keeps the last heartbeatHistory heartbeats
+func trimHeartbeats(beats []Heartbeat) []Heartbeat {
+	if len(beats) > heartbeatHistory {
+		return beats[len(beats)-heartbeatHistory:]
+	}
+	return beats
+}
+
+// parseCertInfo decodes the TLS info Uptime Kuma sends as a JSON string:
+// {"valid":true,"certInfo":{"validTo":"...","daysRemaining":79,"issuer":{"O":"..."}}}
+func parseCertInfo(raw string) (*Certificate, error) {
+	var info struct {
+		Valid    bool `json:"valid"`
+		CertInfo *struct {
+			ValidTo       string            `json:"validTo"`
+			DaysRemaining int               `json:"daysRemaining"`
+			Issuer        map[string]string `json:"issuer"`
+		} `json:"certInfo"`
+	}
+	if err := json.Unmarshal([]byte(raw), &info); err != nil {
+		return nil, err
+	}
+	if info.CertInfo == nil {
+		return nil, errors.New("no certificate")
+	}
+
+	issuer := info.CertInfo.Issuer["O"]
+	if issuer == "" {
+		issuer = info.CertInfo.Issuer["CN"]
+	}
+	return &Certificate{
+		Valid:         info.Valid,
+		DaysRemaining: info.CertInfo.DaysRemaining,
+		ValidTo:       info.CertInfo.ValidTo,
+		Issuer:        issuer,
+	}, nil
+}
+
+// intArg decodes an ID sent either as a number or as a string
+func intArg(raw json.RawMessage) (int, bool) {
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err != nil {
+		var s string
+		if json.Unmarshal(raw, &s) != nil {
+			return 0, false
+		}
+		n = json.Number(s)
+	}
+	id, err := strconv.Atoi(n.String())
+	return id, err == nil
+}
@@ -0,0 +1,231 @@
+package kuma
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"home-run-backend/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKuma is a Socket.IO server over Engine.IO long-polling that accepts one
+// login and pushes statistics like Uptime Kuma does after it
+type fakeKuma struct {
+	password string
+	mu       sync.Mutex
+	outbox   []string // Engine.IO packets waiting for the next poll
+	pongs    int
+	logins   int
+	notify   chan struct{}
+}
+
+func newFakeKuma(password string) *fakeKuma {
+	return &fakeKuma{password: password, notify: make(chan struct{}, 1)}
+}
+
+func (f *fakeKuma) send(packets ...string) {
+	f.mu.Lock()
+	f.outbox = append(f.outbox, packets...)
+	f.mu.Unlock()
+	select {
+	case f.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (f *fakeKuma) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/socket.io/" || r.URL.Query().Get("EIO") != "4" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	sid := r.URL.Query().Get("sid")
+
+	switch {
+	case r.Method == "GET" && sid == "":
+		_, _ = io.WriteString(w, `0{"sid":"engine1","upgrades":["websocket"],"pingInterval":200,"pingTimeout":200,"maxPayload":1000000}`)
+
+	case r.Method == "GET":
+		// Long-poll: answer with queued packets, or a ping after a while
+		select {
+		case <-f.notify:
+		case <-time.After(100 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		f.mu.Lock()
+		packets := f.outbox
+		f.outbox = nil
+		f.mu.Unlock()
+		if len(packets) == 0 {
+			packets = []string{"2"}
+		}
+		_, _ = io.WriteString(w, strings.Join(packets, "\x1e"))
+
+	case r.Method == "POST":
+		body, _ := io.ReadAll(r.Body)
+		for _, packet := range strings.Split(string(body), "\x1e") {
+			f.receive(packet)
+		}
+		_, _ = io.WriteString(w, "ok")
+	}
+}
+
+func (f *fakeKuma) receive(packet string) {
+	switch {
+	case packet == "3":
+		f.mu.Lock()
+		f.pongs++
+		f.mu.Unlock()
+	case packet == "40":
+		f.send(`40{"sid":"socket1"}`, `42["loginRequired"]`)
+	case strings.HasPrefix(packet, "42"):
+		// 42<id>["login",{...}]
+		open := strings.IndexByte(packet, '[')
+		id := packet[2:open]
+		var payload []json.RawMessage
+		_ = json.Unmarshal([]byte(packet[open:]), &payload)
+		var login struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		}
+		_ = json.Unmarshal(payload[1], &login)
+
+		if login.Username != "admin" || login.Password != f.password {
+			f.send(`43` + id + `[{"ok":false,"msg":"Incorrect username or password."}]`)
+			return
+		}
+		f.mu.Lock()
+		f.logins++
+		f.mu.Unlock()
+
+		// Kuma starts pushing state before acknowledging the login
+		f.send(
+			`42["monitorList",{"1":{"id":1,"name":"Nginx"}}]`,
+			`42["heartbeatList",1,[{"monitorID":1,"status":1,"time":"2024-05-01 12:00:00.000","msg":"200 - OK","ping":40},{"monitorID":1,"status":0,"time":"2024-05-01 12:01:00.000","msg":"timeout","ping":null}],true]`,
+			`43`+id+`[{"ok":true,"token":"jwt"}]`,
+			`42["avgPing",1,52.5]`,
+			`42["uptime",1,24,0.995]`,
+			`42["uptime",1,720,0.9]`,
+			`42["uptime",1,"1y",0.8]`,
+			`42["certInfo",1,"{\"valid\":true,\"certInfo\":{\"validTo\":\"Jul 30 12:00:00 2024 GMT\",\"daysRemaining\":79,\"issuer\":{\"O\":\"Let's Encrypt\",\"CN\":\"R3\"}}}"]`,
+			`42["heartbeat",{"monitorID":"1","status":1,"time":"2024-05-01 12:02:00.000","msg":"200 - OK","ping":35}]`,
+		)
+	}
+}
+
+func TestSocket(t *testing.T) {
+	fake := newFakeKuma("secret")
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	socket := NewSocket(&config.UptimeKumaConfig{URL: server.URL + "/", Username: "admin", Password: "secret"})
+	_, err := socket.Get(1)
+	assert.ErrorIs(t, err, ErrNotLoggedIn)
+
+	socket.Start(context.Background())
+	defer socket.Stop()
+
+	var stats MonitorStats
+	require.Eventually(t, func() bool {
+		stats, err = socket.Get(1)
+		return err == nil && len(stats.Heartbeats) == 3 && stats.Certificate != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NotNil(t, stats.Uptime24h)
+	require.NotNil(t, stats.Uptime30d)
+	assert.InDelta(t, 99.5, *stats.Uptime24h, 0.001)
+	assert.InDelta(t, 90, *stats.Uptime30d, 0.001)
+	assert.Equal(t, 52.5, stats.AvgPing)
+
+	assert.Equal(t, Heartbeat{
+		Time:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Status:  "RUNNING",
+		Ping:    40,
+		Message: "200 - OK",
+	}, stats.Heartbeats[0])
+	assert.Equal(t, "STOPPED", stats.Heartbeats[1].Status)
+	assert.Zero(t, stats.Heartbeats[1].Ping)
+	assert.Equal(t, 35.0, stats.Heartbeats[2].Ping)
+
+	assert.Equal(t, &Certificate{
+		Valid:         true,
+		DaysRemaining: 79,
+		ValidTo:       "Jul 30 12:00:00 2024 GMT",
+		Issuer:        "Let's Encrypt",
+	}, stats.Certificate)
+
+	_, err = socket.Get(2)
+	assert.ErrorContains(t, err, "no statistics")
+
+	// The session stays open, answering pings
+	require.Eventually(t, func() bool {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		return fake.pongs > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	fake.mu.Lock()
+	assert.Equal(t, 1, fake.logins)
+	fake.mu.Unlock()
+}
+
+func TestSocket_LoginFailed(t *testing.T) {
+	server := httptest.NewServer(newFakeKuma("secret"))
+	defer server.Close()
+
+	socket := NewSocket(&config.UptimeKumaConfig{URL: server.URL, Username: "admin", Password: "wrong"})
+	socket.Start(context.Background())
+	defer socket.Stop()
+
+	require.Eventually(t, func() bool {
+		_, err := socket.Get(1)
+		return err != nil && err != ErrNotLoggedIn
+	}, 5*time.Second, 10*time.Millisecond)
+	_, err := socket.Get(1)
+	assert.ErrorContains(t, err, "Incorrect username or password")
+}
+
+func TestParseSocketPacket(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected socketPacket
+	}{
+		{`0{"sid":"a"}`, socketPacket{Type: socketConnect, ID: -1, Data: json.RawMessage(`{"sid":"a"}`)}},
+		{`2["uptime",1,24,1]`, socketPacket{Type: socketEvent, ID: -1, Data: json.RawMessage(`["uptime",1,24,1]`)}},
+		{`312[{"ok":true}]`, socketPacket{Type: socketAck, ID: 12, Data: json.RawMessage(`[{"ok":true}]`)}},
+		{`2/,3["x"]`, socketPacket{Type: socketEvent, ID: 3, Data: json.RawMessage(`["x"]`)}},
+		{`2/admin,["x"]`, socketPacket{Type: 0, ID: -1, Data: json.RawMessage(`["x"]`)}},
+		{`1`, socketPacket{Type: socketDisconnect, ID: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			packet, err := parseSocketPacket(tt.raw)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, packet)
+		})
+	}
+
+	_, err := parseSocketPacket("2/admin")
+	assert.Error(t, err)
+}
+
+func TestIntArg(t *testing.T) {
+	for raw, expected := range map[string]int{`1`: 1, `"12"`: 12} {
+		id, ok := intArg(json.RawMessage(raw))
+		assert.True(t, ok, raw)
+		assert.Equal(t, expected, id, raw)
+	}
+	for _, raw := range []string{`"1y"`, `null`, `{}`} {
+		_, ok := intArg(json.RawMessage(raw))
+		assert.False(t, ok, raw)
+	}
+}
@@ -0,0 +1,314 @@
+package kuma
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Uptime Kuma serves Socket.IO 4, which runs the Socket.IO v5 protocol over
+// Engine.IO v4. Only the HTTP long-polling transport is implemented: the
+// client sends packets with POST requests and receives them by keeping a GET
+// request open until the server has something to say.
+
+// Engine.IO packet types
+const (
+	engineOpen    = '0'
+	engineClose   = '1'
+	enginePing    = '2'
+	enginePong    = '3'
+	engineMessage = '4'
+)
+
+// Socket.IO packet types
+const (
+	socketConnect      = '0'
+	socketDisconnect   = '1'
+	socketEvent        = '2'
+	socketAck          = '3'
+	socketConnectError = '4'
+)
+
+// packetSeparator separates Engine.IO packets in a polling payload
+const packetSeparator = "\x1e"
+
+// errSessionClosed is returned when the server ends the session
+var errSessionClosed = errors.New("socket.io session closed by server")
+
+// socketPacket is a decoded Socket.IO packet of the main namespace
+type socketPacket struct {
+	Type byte
+	ID   int // ack ID, -1 if none
+	Data json.RawMessage
+}
+
+// socketConn is a Socket.IO connection to the main namespace over Engine.IO
+// long-polling. It is not safe for concurrent use.
+type socketConn struct {
+	httpClient   *http.Client
+	endpoint     string // .../socket.io/
+	sid          string // Engine.IO session
+	pingInterval time.Duration
+	pingTimeout  time.Duration
+	pending      []string // Engine.IO packets received but not read yet
+	nextAck      int
+}
+
+// openHandshake is the payload of the Engine.IO open packet
+type openHandshake struct {
+	SID          string `json:"sid"`
+	PingInterval int    `json:"pingInterval"` // ms
+	PingTimeout  int    `json:"pingTimeout"`  // ms
+}
+
+// dialSocket opens an Engine.IO session and connects to the main namespace
+func dialSocket(ctx context.Context, httpClient *http.Client, baseURL string) (*socketConn, error) {
+	conn := &socketConn{
+		httpClient:   httpClient,
+		endpoint:     baseURL + "/socket.io/",
+		pingInterval: 25 * time.Second,
+		pingTimeout:  20 * time.Second,
+	}
+
+	packets, err := conn.get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("engine.io handshake failed: %w", err)
+	}
+	if len(packets) == 0 || packets[0] == "" || packets[0][0] != engineOpen {
+		return nil, errors.New("engine.io handshake failed: no open packet")
+	}
+	var open openHandshake
+	if err := json.Unmarshal([]byte(packets[0][1:]), &open); err != nil || open.SID == "" {
+		return nil, errors.New("engine.io handshake failed: invalid open packet")
+	}
+	conn.sid = open.SID
+	if open.PingInterval > 0 {
+		conn.pingInterval = time.Duration(open.PingInterval) * time.Millisecond
+	}
+	if open.PingTimeout > 0 {
+		conn.pingTimeout = time.Duration(open.PingTimeout) * time.Millisecond
+	}
+	conn.pending = packets[1:]
+
+	if err := conn.post(ctx, string(engineMessage)+string(socketConnect)); err != nil {
+		return nil, fmt.Errorf("socket.io connect failed: %w", err)
+	}
+	for {
+		packet, err := conn.read(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("socket.io connect failed: %w", err)
+		}
+		switch packet.Type {
+		case socketConnect:
+			return conn, nil
+		case socketConnectError:
+			return nil, fmt.Errorf("socket.io connect refused: %s", packet.Data)
+		}
+	}
+}
+
+// call sends an event and reads packets until the server acknowledges it.
+// Events received meanwhile are passed to onEvent.
+func (c *socketConn) call(ctx context.Context, onEvent func(string, []json.RawMessage), event string, args ...any) ([]json.RawMessage, error) {
+	payload, err := json.Marshal(append([]any{event}, args...))
+	if err != nil {
+		return nil, err
+	}
+	id := c.nextAck
+	c.nextAck++
+	if err := c.post(ctx, string(engineMessage)+string(socketEvent)+strconv.Itoa(id)+string(payload)); err != nil {
+		return nil, err
+	}
+
+	for {
+		packet, err := c.read(ctx)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case packet.Type == socketAck && packet.ID == id:
+			var result []json.RawMessage
+			if err := json.Unmarshal(packet.Data, &result); err != nil {
+				return nil, fmt.Errorf("invalid acknowledgement of %s: %w", event, err)
+			}
+			return result, nil
+		case packet.Type == socketEvent:
+			if name, args, ok := eventArgs(packet); ok {
+				onEvent(name, args)
+			}
+		}
+	}
+}
+
+// listen passes every event to onEvent until ctx is cancelled or the session ends
+func (c *socketConn) listen(ctx context.Context, onEvent func(string, []json.RawMessage)) error {
+	for {
+		packet, err := c.read(ctx)
+		if err != nil {
+			return err
+		}
+		if packet.Type != socketEvent {
+			continue
+		}
+		if name, args, ok := eventArgs(packet); ok {
+			onEvent(name, args)
+		}
+	}
+}
+
+// close ends the session, best effort
+func (c *socketConn) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = c.post(ctx, string(engineClose))
+}
+
+// read returns the next Socket.IO packet of the main namespace, polling for
+// more and answering pings as needed
+func (c *socketConn) read(ctx context.Context) (socketPacket, error) {
+	for {
+		for len(c.pending) > 0 {
+			raw := c.pending[0]
+			c.pending = c.pending[1:]
+			if raw == "" {
+				continue
+			}
+
+			switch raw[0] {
+			case enginePing:
+				if err := c.post(ctx, string(enginePong)); err != nil {
+					return socketPacket{}, err
+				}
+			case engineClose:
+				return socketPacket{}, errSessionClosed
+			case engineMessage:
+				packet, err := parseSocketPacket(raw[1:])
+				if err != nil {
+					return socketPacket{}, err
+				}
+				if packet.Type == socketDisconnect {
+					return socketPacket{}, errSessionClosed
+				}
+				return packet, nil
+			}
+			// Noop, upgrade and binary packets don't concern polling clients
+		}
+
+		packets, err := c.get(ctx)
+		if err != nil {
+			return socketPacket{}, err
+		}
+		c.pending = packets
+	}
+}
+
+// get long-polls for packets. The server answers at least every pingInterval.
+func (c *socketConn) get(ctx context.Context) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.pingInterval+c.pingTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.url(), nil)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(string(body), packetSeparator), nil
+}
+
+// post sends packets in one payload
+func (c *socketConn) post(ctx context.Context, packets ...string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.pingTimeout)
+	defer cancel()
+
+	body := strings.Join(packets, packetSeparator)
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url(), bytes.NewBufferString(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain;charset=UTF-8")
+	_, err = c.do(req)
+	return err
+}
+
+func (c *socketConn) do(req *http.Request) ([]byte, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 16*1024*1024))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("socket.io returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+func (c *socketConn) url() string {
+	query := url.Values{"EIO": {"4"}, "transport": {"polling"}}
+	if c.sid != "" {
+		query.Set("sid", c.sid)
+	}
+	return c.endpoint + "?" + query.Encode()
+}
+
+// parseSocketPacket decodes `<type>[<namespace>,][<ack id>][<json>]`
+func parseSocketPacket(raw string) (socketPacket, error) {
+	if raw == "" {
+		return socketPacket{}, errors.New("empty socket.io packet")
+	}
+	packet := socketPacket{Type: raw[0], ID: -1}
+	rest := raw[1:]
+
+	// Events of other namespaces are not ours
+	if strings.HasPrefix(rest, "/") {
+		comma := strings.IndexByte(rest, ',')
+		if comma < 0 {
+			return socketPacket{}, fmt.Errorf("invalid socket.io packet %q", raw)
+		}
+		if rest[:comma] != "/" {
+			packet.Type = 0 // matches no packet type, so it is skipped
+		}
+		rest = rest[comma+1:]
+	}
+
+	digits := 0
+	for digits < len(rest) && rest[digits] >= '0' && rest[digits] <= '9' {
+		digits++
+	}
+	if digits > 0 {
+		packet.ID, _ = strconv.Atoi(rest[:digits])
+		rest = rest[digits:]
+	}
+	if rest != "" {
+		packet.Data = json.RawMessage(rest)
+	}
+	return packet, nil
+}
+
+// eventArgs splits an event packet into its name and arguments
+func eventArgs(packet socketPacket) (string, []json.RawMessage, bool) {
+	var payload []json.RawMessage
+	if err := json.Unmarshal(packet.Data, &payload); err != nil || len(payload) == 0 {
+		return "", nil, false
+	}
+	var name string
+	if err := json.Unmarshal(payload[0], &name); err != nil {
+		return "", nil, false
+	}
+	return name, payload[1:], true
+}
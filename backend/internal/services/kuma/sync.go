@@ -0,0 +1,430 @@
+package kuma
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"home-run-backend/internal/logger"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// SyncResult describes the changes a sync makes to config.yml
+type SyncResult struct {
+	Added   []Monitor `json:"added"`   // monitors appended as new services
+	Updated []Monitor `json:"updated"` // services whose url or port changed
+	Missing []int     `json:"missing"` // kuma_monitor_id values Kuma no longer has
+	Diff    string    `json:"diff"`    // unified diff of config.yml, empty if unchanged
+	Written bool      `json:"written"`
+}
+
+// SyncConfig brings the uptime_kuma services in the config file at path in
+// line with monitors and, unless dryRun is set, writes the file back
+func SyncConfig(path string, monitors []Monitor, dryRun bool) (*SyncResult, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	updated, result, err := PlanSync(content, monitors)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(content)),
+		B:        difflib.SplitLines(string(updated)),
+		FromFile: path,
+		ToFile:   path,
+		Context:  3,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff config file: %w", err)
+	}
+
+	if dryRun || result.Diff == "" {
+		return result, nil
+	}
+	if err := writeFileAtomic(path, updated); err != nil {
+		return nil, fmt.Errorf("failed to write config file: %w", err)
+	}
+	result.Written = true
+	return result, nil
+}
+
+// PlanSync returns content with every uptime_kuma service matched to a
+// monitor by kuma_monitor_id, and a service appended for every monitor that
+// has none. Matched services keep their name, since service IDs derive from
+// it; only url and port are updated. Services whose monitor is gone are
+// reported in Missing, never removed. The file is edited line by line, so
+// comments and formatting are kept.
+func PlanSync(content []byte, monitors []Monitor) ([]byte, *SyncResult, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, errors.New("config is not a YAML mapping")
+	}
+	root := doc.Content[0]
+
+	e := &lineEditor{
+		lines:    strings.Split(string(content), "\n"),
+		replaced: make(map[int]string),
+		inserted: make(map[int][]string),
+	}
+	result := &SyncResult{Added: []Monitor{}, Updated: []Monitor{}, Missing: []int{}}
+
+	byID := make(map[int]Monitor, len(monitors))
+	for _, monitor := range monitors {
+		byID[monitor.ID] = monitor
+	}
+
+	servicesKey, services := mappingValue(root, "services")
+	if services != nil && services.Kind != yaml.SequenceNode && services.Tag != "!!null" {
+		return nil, nil, errors.New("services is not a list")
+	}
+
+	names := make(map[string]bool)
+	configured := make(map[int]bool)
+	var items []*yaml.Node
+	if services != nil && services.Kind == yaml.SequenceNode {
+		items = services.Content
+	}
+	for _, item := range items {
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+		if _, name := mappingValue(item, "name"); name != nil {
+			names[name.Value] = true
+		}
+		if _, backend := mappingValue(item, "backend"); backend == nil || backend.Value != "uptime_kuma" {
+			continue
+		}
+		_, idNode := mappingValue(item, "kuma_monitor_id")
+		if idNode == nil {
+			continue
+		}
+		id, err := strconv.Atoi(idNode.Value)
+		if err != nil {
+			continue
+		}
+		configured[id] = true
+
+		monitor, ok := byID[id]
+		if !ok {
+			result.Missing = append(result.Missing, id)
+			continue
+		}
+		if e.updateService(item, monitor) {
+			result.Updated = append(result.Updated, monitor)
+		}
+	}
+
+	var entries []string
+	for _, monitor := range monitors {
+		if configured[monitor.ID] {
+			continue
+		}
+		configured[monitor.ID] = true
+		monitor.Name = uniqueName(monitor.Name, monitor.ID, names)
+		entries = append(entries, newServiceLines(monitor)...)
+		result.Added = append(result.Added, monitor)
+	}
+	if len(entries) > 0 {
+		e.appendServices(root, servicesKey, services, entries)
+	}
+
+	sort.Ints(result.Missing)
+	return []byte(e.String()), result, nil
+}
+
+// serviceAddress returns the url and port a service gets from a monitor.
+// The frontend links to url:port, so paths are dropped and the port is split off.
+func serviceAddress(monitor Monitor) (string, int) {
+	if u, err := url.Parse(monitor.URL); err == nil && u.Hostname() != "" {
+		port, _ := strconv.Atoi(u.Port())
+		if port == 0 && u.Scheme == "https" {
+			port = 443
+		} else if port == 0 {
+			port = 80
+		}
+		return u.Scheme + "://" + u.Hostname(), port
+	}
+	if monitor.Hostname != "" {
+		return "http://" + monitor.Hostname, monitor.Port
+	}
+	return "", 0
+}
+
+// newServiceLines renders a service entry for a monitor, with list item
+// indentation added by the caller
+func newServiceLines(monitor Monitor) []string {
+	lines := []string{"- name: " + yamlScalar(monitor.Name)}
+	address, port := serviceAddress(monitor)
+	if address != "" {
+		lines = append(lines, "  url: "+yamlScalar(address))
+	}
+	if port > 0 {
+		lines = append(lines, "  port: "+strconv.Itoa(port))
+	}
+	return append(lines,
+		"  backend: uptime_kuma",
+		"  kuma_monitor_id: "+strconv.Itoa(monitor.ID),
+	)
+}
+
+// uniqueName returns a service name that isn't taken yet and reserves it
+func uniqueName(name string, id int, taken map[string]bool) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		name = fmt.Sprintf("Kuma monitor %d", id)
+	}
+	candidate := name
+	for i := 2; taken[candidate]; i++ {
+		candidate = fmt.Sprintf("%s (%d)", name, i)
+	}
+	taken[candidate] = true
+	return candidate
+}
+
+// yamlScalar renders a string as a YAML scalar, quoting it only if needed
+func yamlScalar(value string) string {
+	out, err := yaml.Marshal(value)
+	if err != nil {
+		return strconv.Quote(value)
+	}
+	return strings.TrimSuffix(string(out), "\n")
+}
+
+// mappingValue returns the key and value nodes of a key in a mapping
+func mappingValue(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// lastLine returns the last line number a node or its children start on
+func lastLine(node *yaml.Node) int {
+	line := node.Line
+	for _, child := range node.Content {
+		line = max(line, lastLine(child))
+	}
+	return line
+}
+
+// lineEditor collects edits to the lines of a file. Line indexes refer to
+// the original content, so edits don't shift each other.
+type lineEditor struct {
+	lines    []string
+	replaced map[int]string   // new text of a line
+	inserted map[int][]string // lines inserted before a line; len(lines) appends
+}
+
+// updateService sets the url and port of a service entry to its monitor's,
+// reporting whether anything changed. Flow style entries are left alone.
+func (e *lineEditor) updateService(item *yaml.Node, monitor Monitor) bool {
+	if item.Style&yaml.FlowStyle != 0 {
+		return false
+	}
+	address, port := serviceAddress(monitor)
+	changed := false
+	if address != "" {
+		changed = e.setField(item, "url", yamlScalar(address)) || changed
+	}
+	if port > 0 {
+		changed = e.setField(item, "port", strconv.Itoa(port)) || changed
+	}
+	return changed
+}
+
+// setField replaces the value of a scalar field of a block mapping, or adds
+// the field after the mapping's last line
+func (e *lineEditor) setField(mapping *yaml.Node, key, value string) bool {
+	_, node := mappingValue(mapping, key)
+	if node == nil {
+		indent := strings.Repeat(" ", mapping.Column-1)
+		at := e.endLine(mapping)
+		e.inserted[at] = append(e.inserted[at], indent+key+": "+value)
+		return true
+	}
+
+	if node.Kind != yaml.ScalarNode || node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return false
+	}
+	var decoded, wanted string
+	if node.Decode(&decoded) == nil && yaml.Unmarshal([]byte(value), &wanted) == nil && decoded == wanted {
+		return false
+	}
+
+	index := node.Line - 1
+	line := e.lines[index]
+	text := line[:node.Column-1] + value
+	if node.LineComment != "" {
+		text += "  " + node.LineComment
+	}
+	e.replaced[index] = text
+	return true
+}
+
+// endLine returns the index of the line after a block mapping. A value that
+// ends the mapping may continue over lines indented deeper than its keys
+// (block scalars, flow collections), or close a flow collection at their
+// indentation, and those lines are skipped.
+func (e *lineEditor) endLine(mapping *yaml.Node) int {
+	end := lastLine(mapping)
+	for i := end; i < len(e.lines); i++ {
+		trimmed := strings.TrimLeft(e.lines[i], " ")
+		if strings.TrimSpace(trimmed) == "" {
+			continue
+		}
+		indent := len(e.lines[i]) - len(trimmed)
+		closing := indent == mapping.Column-1 && strings.ContainsAny(trimmed[:1], "]}")
+		if indent < mapping.Column && !closing {
+			break
+		}
+		end = i + 1
+	}
+	return end
+}
+
+// appendServices adds entries at the end of the services list, creating the
+// list if the config has none
+func (e *lineEditor) appendServices(root, key, services *yaml.Node, entries []string) {
+	// No services key: add one at the end of the file
+	if key == nil {
+		at := len(e.lines)
+		if at > 0 && e.lines[at-1] == "" {
+			at-- // before the final newline
+		}
+		lines := []string{"", "services:"}
+		for _, line := range entries {
+			lines = append(lines, "  "+line)
+		}
+		e.inserted[at] = append(e.inserted[at], lines...)
+		return
+	}
+
+	// Empty list (services: or services: []): start a block list under the key
+	if services.Kind != yaml.SequenceNode || len(services.Content) == 0 {
+		index := key.Line - 1
+		text := e.lines[index][:key.Column-1] + "services:"
+		if services.LineComment != "" {
+			text += "  " + services.LineComment
+		}
+		e.replaced[index] = text
+		indent := strings.Repeat(" ", key.Column+1)
+		for _, line := range entries {
+			e.inserted[index+1] = append(e.inserted[index+1], indent+line)
+		}
+		return
+	}
+
+	items := services.Content
+	indent := strings.Repeat(" ", items[0].Column-3) // items start after "- "
+	separated := len(items) > 1 && strings.TrimSpace(e.lines[items[1].Line-2]) == ""
+
+	// Append after the last item, before the blank lines and comments that
+	// lead into the next top-level key
+	end := lastLine(services)
+	at := len(e.lines)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i] == key && i+2 < len(root.Content) {
+			at = root.Content[i+2].Line - 1
+		}
+	}
+	for at > end {
+		trimmed := strings.TrimSpace(e.lines[at-1])
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		at--
+	}
+
+	var lines []string
+	for _, line := range entries {
+		if separated && strings.HasPrefix(line, "- ") {
+			lines = append(lines, "")
+		}
+		lines = append(lines, indent+line)
+	}
+	e.inserted[at] = append(e.inserted[at], lines...)
+}
+
+// String returns the edited content
+func (e *lineEditor) String() string {
+	var out []string
+	for i, line := range e.lines {
+		out = append(out, e.inserted[i]...)
+		if text, ok := e.replaced[i]; ok {
+			line = text
+		}
+		out = append(out, line)
+	}
+	out = append(out, e.inserted[len(e.lines)]...)
+	return strings.Join(out, "\n")
+}
+
+// rename is os.Rename, replaced in tests
+var rename = os.Rename
+
+// writeFileAtomic replaces a file through a temporary file in the same
+// directory, keeping its permissions. A file that can't be renamed over, such
+// as a single-file bind mount in a container (EBUSY), or in a directory that
+// isn't writable, is rewritten in place instead.
+func writeFileAtomic(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return writeFileInPlace(path, data)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := rename(tmp.Name(), path); err != nil {
+		logger.WithFields(logrus.Fields{
+			"path":  path,
+			"error": err.Error(),
+		}).Debug("Rename failed, rewriting config file in place")
+		return writeFileInPlace(path, data)
+	}
+	return nil
+}
+
+// writeFileInPlace truncates a file and writes data to it
+func writeFileInPlace(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,234 @@
+package kuma
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const syncConfig = `server:
+  port: 8085
+
+# Services to monitor
+services:
+  - name: Bazarr
+    url: http://localhost
+    port: 6767
+    backend: docker
+    container_name: bazarr
+
+  - name: Nginx
+    url: http://old.example.com  # public site
+    port: 80
+    backend: uptime_kuma
+    kuma_monitor_id: 1
+
+  - name: Gone
+    backend: uptime_kuma
+    kuma_monitor_id: 7
+
+# Remote hosts (optional)
+# remote_hosts: []
+`
+
+func TestPlanSync(t *testing.T) {
+	monitors := []Monitor{
+		{ID: 1, Name: "Nginx", Type: "http", URL: "https://example.com/health"},
+		{ID: 2, Name: "Bazarr", Type: "port", Hostname: "nas.lan", Port: 6767},
+		{ID: 3, Name: "DNS: Pi-hole", Type: "dns"},
+	}
+
+	updated, result, err := PlanSync([]byte(syncConfig), monitors)
+	require.NoError(t, err)
+
+	assert.Equal(t, `server:
+  port: 8085
+
+# Services to monitor
+services:
+  - name: Bazarr
+    url: http://localhost
+    port: 6767
+    backend: docker
+    container_name: bazarr
+
+  - name: Nginx
+    url: https://example.com  # public site
+    port: 443
+    backend: uptime_kuma
+    kuma_monitor_id: 1
+
+  - name: Gone
+    backend: uptime_kuma
+    kuma_monitor_id: 7
+
+  - name: Bazarr (2)
+    url: http://nas.lan
+    port: 6767
+    backend: uptime_kuma
+    kuma_monitor_id: 2
+
+  - name: 'DNS: Pi-hole'
+    backend: uptime_kuma
+    kuma_monitor_id: 3
+
+# Remote hosts (optional)
+# remote_hosts: []
+`, string(updated))
+
+	assert.Equal(t, []int{1}, ids(result.Updated))
+	assert.Equal(t, []int{2, 3}, ids(result.Added))
+	assert.Equal(t, "Bazarr (2)", result.Added[0].Name)
+	assert.Equal(t, []int{7}, result.Missing)
+
+	// Syncing the result again changes nothing
+	again, result, err := PlanSync(updated, monitors)
+	require.NoError(t, err)
+	assert.Equal(t, string(updated), string(again))
+	assert.Empty(t, result.Added)
+	assert.Empty(t, result.Updated)
+}
+
+func TestPlanSync_NoServices(t *testing.T) {
+	monitors := []Monitor{{ID: 4, Name: "Plex", Hostname: "nas.lan", Port: 32400}}
+	entry := `
+  - name: Plex
+    url: http://nas.lan
+    port: 32400
+    backend: uptime_kuma
+    kuma_monitor_id: 4
+`
+
+	tests := []struct {
+		name     string
+		config   string
+		expected string
+	}{
+		{
+			name:     "missing key",
+			config:   "server:\n  port: 8085\n",
+			expected: "server:\n  port: 8085\n\nservices:" + entry,
+		},
+		{
+			name:     "null",
+			config:   "services:\nauth:\n  username: admin\n",
+			expected: "services:" + entry + "auth:\n  username: admin\n",
+		},
+		{
+			name:     "empty list",
+			config:   "services: []  # none yet\n",
+			expected: "services:  # none yet" + entry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, result, err := PlanSync([]byte(tt.config), monitors)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(updated))
+			assert.Len(t, result.Added, 1)
+		})
+	}
+}
+
+func TestPlanSync_MultiLineValues(t *testing.T) {
+	monitors := []Monitor{{ID: 1, Name: "Nginx", Hostname: "nas.lan", Port: 8080}}
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"block scalar", "    description: |\n      Reverse proxy\n\n      # not a comment\n"},
+		{"flow sequence", "    tags: [web,\n      proxy]\n"},
+		{"flow mapping closed at key indentation", "    labels: {\n      tier: edge\n    }\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := "services:\n  - name: Nginx\n    backend: uptime_kuma\n    kuma_monitor_id: 1\n" + tt.value +
+				"\n  - name: Plex\n    backend: docker\n    container_name: plex\n"
+			expected := "services:\n  - name: Nginx\n    backend: uptime_kuma\n    kuma_monitor_id: 1\n" + tt.value +
+				"    url: http://nas.lan\n    port: 8080\n" +
+				"\n  - name: Plex\n    backend: docker\n    container_name: plex\n"
+
+			updated, result, err := PlanSync([]byte(config), monitors)
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(updated))
+			assert.Equal(t, []int{1}, ids(result.Updated))
+		})
+	}
+}
+
+func TestPlanSync_Invalid(t *testing.T) {
+	_, _, err := PlanSync([]byte("services: {name: x}\n"), nil)
+	assert.ErrorContains(t, err, "services is not a list")
+
+	_, _, err = PlanSync([]byte("- a\n"), nil)
+	assert.ErrorContains(t, err, "not a YAML mapping")
+}
+
+func TestSyncConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte(syncConfig), 0600))
+	monitors := []Monitor{{ID: 1, Name: "Nginx", URL: "http://old.example.com"}, {ID: 7, Name: "Gone"}}
+
+	// Nothing to change
+	result, err := SyncConfig(path, monitors, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Diff)
+	assert.False(t, result.Written)
+
+	monitors = append(monitors, Monitor{ID: 9, Name: "Jellyfin", URL: "http://media.lan:8096"})
+
+	// Dry run shows the diff without writing
+	result, err = SyncConfig(path, monitors, true)
+	require.NoError(t, err)
+	assert.Contains(t, result.Diff, "+  - name: Jellyfin\n+    url: http://media.lan\n+    port: 8096\n")
+	assert.False(t, result.Written)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, syncConfig, string(content))
+
+	result, err = SyncConfig(path, monitors, false)
+	require.NoError(t, err)
+	assert.True(t, result.Written)
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "kuma_monitor_id: 9\n")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestWriteFileAtomic_BindMount(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte("a long original file\n"), 0640))
+
+	// Renaming over a single-file bind mount fails with EBUSY
+	rename = func(string, string) error { return syscall.EBUSY }
+	defer func() { rename = os.Rename }()
+
+	require.NoError(t, writeFileAtomic(path, []byte("short\n")))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "short\n", string(content))
+
+	// The temporary file is cleaned up
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func ids(monitors []Monitor) []int {
+	result := []int{}
+	for _, monitor := range monitors {
+		result = append(result, monitor.ID)
+	}
+	return result
+}
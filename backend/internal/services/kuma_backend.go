@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/models"
+	"home-run-backend/internal/services/certs"
+	"home-run-backend/internal/services/kuma"
+)
+
+func init() {
+	RegisterBackend("uptime_kuma", newKumaBackend, validateKumaService)
+}
+
+// kumaBackend reports status of Uptime Kuma monitors from a shared snapshot
+// of the /metrics endpoint. With a username and password, uptime, average
+// ping, heartbeats and certificates come from Kuma's Socket.IO API as well.
+type kumaBackend struct {
+	client      *kuma.Client
+	poller      *kuma.Poller
+	socket      *kuma.Socket // nil without username and password
+	warningDays int
+}
+
+func newKumaBackend(cfg *config.Config, _ []config.ServiceConfig) (StatusBackend, error) {
+	if cfg.UptimeKuma == nil {
+		return nil, errors.New("uptime_kuma is not configured")
+	}
+	client := kuma.NewClient(cfg.UptimeKuma)
+	b := &kumaBackend{
+		client:      client,
+		poller:      kuma.NewPoller(client, cfg.UptimeKuma.Interval),
+		warningDays: warningDays(cfg),
+	}
+	if cfg.UptimeKuma.Username != "" && cfg.UptimeKuma.Password != "" {
+		b.socket = kuma.NewSocket(cfg.UptimeKuma)
+	}
+	return b, nil
+}
+
+// validateKumaService checks that an uptime_kuma service has a monitor and a configured instance
+func validateKumaService(cfg *config.Config, index int, svc config.ServiceConfig) error {
+	if cfg.UptimeKuma == nil {
+		return fmt.Errorf("services[%d] uses uptime_kuma backend but uptime_kuma config is missing", index)
+	}
+	if svc.KumaMonitorID == 0 {
+		return fmt.Errorf("services[%d].kuma_monitor_id is required for uptime_kuma backend", index)
+	}
+	return nil
+}
+
+// Start starts scraping Uptime Kuma and connects its socket
+func (b *kumaBackend) Start(ctx context.Context) {
+	b.poller.Start(ctx)
+	if b.socket != nil {
+		b.socket.Start(ctx)
+	}
+}
+
+// Stop stops scraping Uptime Kuma and closes its socket
+func (b *kumaBackend) Stop() {
+	b.poller.Stop()
+	if b.socket != nil {
+		b.socket.Stop()
+	}
+}
+
+// Monitors lists every monitor of the Uptime Kuma instance
+func (b *kumaBackend) Monitors(ctx context.Context) ([]kuma.Monitor, error) {
+	return b.client.Monitors(ctx)
+}
+
+// Populate fills in status and latency from the latest scrape, and the
+// statistics pushed over the socket when it is logged in
+func (b *kumaBackend) Populate(_ context.Context, svc *models.Service, cfg config.ServiceConfig) {
+	status, err := b.poller.Get(cfg.KumaMonitorID)
+	if err != nil {
+		svc.Status = "ERROR"
+		return
+	}
+
+	svc.Status = status.Status
+	svc.Latency = status.Latency
+
+	if b.socket == nil {
+		return
+	}
+	stats, err := b.socket.Get(cfg.KumaMonitorID)
+	if err != nil {
+		return
+	}
+
+	svc.Uptime24h = stats.Uptime24h
+	svc.Uptime30d = stats.Uptime30d
+	svc.AvgLatency = stats.AvgPing
+	if stats.Uptime24h != nil {
+		svc.Uptime = fmt.Sprintf("%.1f%% uptime", *stats.Uptime24h)
+	}
+	for _, beat := range stats.Heartbeats {
+		heartbeat := models.Heartbeat{
+			Status:  beat.Status,
+			Latency: beat.Ping,
+			Message: beat.Message,
+		}
+		if !beat.Time.IsZero() {
+			heartbeat.Time = beat.Time.Format(time.RFC3339)
+		}
+		svc.Heartbeats = append(svc.Heartbeats, heartbeat)
+	}
+	b.applyCertificate(svc, stats.Certificate)
+}
+
+// applyCertificate records the expiry of the certificate Kuma checks and
+// degrades a running service like the certificate monitor does: WARNING
+// within certificates.warning_days, ERROR once invalid or expired
+func (b *kumaBackend) applyCertificate(svc *models.Service, cert *kuma.Certificate) {
+	if cert == nil {
+		return
+	}
+	days := cert.DaysRemaining
+	svc.CertExpiresInDays = &days
+
+	if svc.Status != "RUNNING" {
+		return
+	}
+	switch {
+	case !cert.Valid || days < 0:
+		svc.Status = "ERROR"
+	case certs.IsExpiring(days, b.warningDays):
+		svc.Status = "WARNING"
+	}
+}
@@ -3,70 +3,151 @@ package services
 import (
 	"context"
 	"crypto/md5"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
-	"home-run-backend/internal/cache"
 	"home-run-backend/internal/config"
+	"home-run-backend/internal/history"
+	"home-run-backend/internal/logger"
 	"home-run-backend/internal/models"
+	"home-run-backend/internal/services/certs"
 	"home-run-backend/internal/services/docker"
+	"home-run-backend/internal/services/host"
 	"home-run-backend/internal/services/kuma"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrServiceNotFound is returned when no local service has the requested ID
+	ErrServiceNotFound = errors.New("service not found")
+	// ErrActionsNotAllowed is returned for actions on services without allow_actions
+	ErrActionsNotAllowed = errors.New("actions are not allowed for this service")
+	// ErrNotContainer is returned for container operations on services that don't run in one
+	ErrNotContainer = errors.New("service does not run in a docker container")
+	// ErrBackendUnavailable is returned for services whose backend failed to start
+	ErrBackendUnavailable = errors.New("backend is unavailable")
+	// ErrDockerUnavailable is returned for daemon operations without a connected docker daemon
+	ErrDockerUnavailable = errors.New("docker is unavailable")
+	// ErrPruneNotAllowed is returned for prunes unless server.allow_prune is set
+	ErrPruneNotAllowed = errors.New("pruning is not allowed")
+	// ErrKumaNotConfigured is returned for Uptime Kuma operations without an uptime_kuma section
+	ErrKumaNotConfigured = errors.New("uptime kuma is not configured")
+	// ErrKumaSyncNotAllowed is returned for syncs that write unless server.allow_kuma_sync is set
+	ErrKumaSyncNotAllowed = errors.New("writing the config file is not allowed")
+	// ErrHistoryDisabled is returned for history queries without history.path
+	ErrHistoryDisabled = errors.New("history is not enabled")
 )
 
 // Manager manages local services and their status
 type Manager struct {
-	cfg            *config.Config
-	dockerClient   *docker.Client
-	dockerStats    *docker.StatsCollector
-	kumaClient     *kuma.Client
-	statsCache     *cache.Cache
-	dockerDisabled bool
+	cfg         *config.Config
+	backends    map[string]StatusBackend
+	unavailable map[string]error // backends that failed to start, by name
+	certs       *certs.Monitor
+	host        *host.Collector
+	history     *history.Store // nil unless history.path is set
+	recorder    *history.Recorder
 }
 
 // NewManager creates a new service manager
 func NewManager(cfg *config.Config) (*Manager, error) {
 	m := &Manager{
-		cfg:        cfg,
-		statsCache: cache.New(30 * time.Second),
+		cfg:         cfg,
+		backends:    make(map[string]StatusBackend),
+		unavailable: make(map[string]error),
 	}
 
-	// Initialize Docker client (optional - may not be available)
-	dockerClient, err := docker.NewClient()
-	if err != nil {
-		// Docker not available, disable Docker backend
-		m.dockerDisabled = true
-	} else {
-		m.dockerClient = dockerClient
+	// Open the history store first so a failure doesn't leak backend clients
+	if cfg.History.Path != "" {
+		store, err := history.Open(cfg.History.Path)
+		if err != nil {
+			return nil, err
+		}
+		m.history = store
+		m.recorder = history.NewRecorder(store, cfg.History.Interval, m.historySamples)
+	}
 
-		// Initialize stats collector
-		m.dockerStats = docker.NewStatsCollector(dockerClient, m.statsCache, cfg.Services, 10*time.Second)
+	// Group services by backend type so each backend only sees its own services
+	byBackend := make(map[string][]config.ServiceConfig)
+	var order []string
+	for _, svc := range cfg.Services {
+		if _, seen := byBackend[svc.Backend]; !seen {
+			order = append(order, svc.Backend)
+		}
+		byBackend[svc.Backend] = append(byBackend[svc.Backend], svc)
+	}
+	if _, seen := byBackend["docker"]; cfg.DockerDiscovery.Enabled && !seen {
+		order = append(order, "docker")
+	}
+	// Monitors can be imported before any service uses them
+	if _, seen := byBackend["uptime_kuma"]; cfg.UptimeKuma != nil && !seen {
+		order = append(order, "uptime_kuma")
 	}
 
-	// Initialize Uptime Kuma client if configured
-	if cfg.UptimeKuma != nil {
-		m.kumaClient = kuma.NewClient(cfg.UptimeKuma)
+	// Initialize backends (optional - a backend may not be available)
+	for _, name := range order {
+		factory, ok := lookupBackend(name)
+		if !ok {
+			logger.WithField("backend", name).Warn("Unknown backend, services will report ERROR")
+			continue
+		}
+
+		backend, err := factory(cfg, byBackend[name])
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"backend": name,
+				"error":   err.Error(),
+			}).Warn("Backend unavailable, services will report ERROR")
+			m.unavailable[name] = err
+			continue
+		}
+		m.backends[name] = backend
 	}
 
+	m.certs = certs.NewMonitor(m.certTargets, cfg.Certificates.Interval, warningDays(cfg))
+	m.host = host.NewCollector(host.Interval, host.Window)
+
 	return m, nil
 }
 
 // Start starts background processes (stats collection)
 func (m *Manager) Start(ctx context.Context) {
-	if m.dockerStats != nil {
-		m.dockerStats.Start(ctx)
+	for _, backend := range m.backends {
+		if bg, ok := backend.(BackgroundBackend); ok {
+			bg.Start(ctx)
+		}
+	}
+	m.certs.Start(ctx)
+	m.host.Start(ctx)
+	if m.recorder != nil {
+		m.recorder.Start(ctx)
 	}
 }
 
 // Stop stops background processes
 func (m *Manager) Stop() {
-	if m.dockerStats != nil {
-		m.dockerStats.Stop()
+	for _, backend := range m.backends {
+		if bg, ok := backend.(BackgroundBackend); ok {
+			bg.Stop()
+		}
 	}
-	if m.dockerClient != nil {
-		m.dockerClient.Close()
+	m.certs.Stop()
+	m.host.Stop()
+	if m.recorder != nil {
+		m.recorder.Stop()
+		if err := m.history.Close(); err != nil {
+			logger.WithField("error", err.Error()).Warn("Failed to close history database")
+		}
 	}
 }
 
@@ -74,7 +155,7 @@ func (m *Manager) Stop() {
 func (m *Manager) GetAll(ctx context.Context) []models.Service {
 	var result []models.Service
 
-	for _, svcCfg := range m.cfg.Services {
+	for _, svcCfg := range m.services() {
 		svc := m.buildService(ctx, svcCfg)
 		result = append(result, svc)
 	}
@@ -84,27 +165,28 @@ func (m *Manager) GetAll(ctx context.Context) []models.Service {
 
 // GetByID returns a single service by ID
 func (m *Manager) GetByID(ctx context.Context, id string) (*models.Service, error) {
-	for _, svcCfg := range m.cfg.Services {
+	for _, svcCfg := range m.services() {
 		if generateID(svcCfg.Name) == id {
 			svc := m.buildService(ctx, svcCfg)
 			return &svc, nil
 		}
 	}
-	return nil, fmt.Errorf("service not found: %s", id)
+	return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, id)
 }
 
 // GetConfigContent returns the content of a service's config file
 func (m *Manager) GetConfigContent(ctx context.Context, serviceID string, configIndex int) (*models.ServiceConfig, error) {
-	for _, svcCfg := range m.cfg.Services {
+	for _, svcCfg := range m.services() {
 		if generateID(svcCfg.Name) != serviceID {
 			continue
 		}
 
-		if configIndex < 0 || configIndex >= len(svcCfg.Configs) {
+		configs := m.configFiles(svcCfg)
+		if configIndex < 0 || configIndex >= len(configs) {
 			return nil, fmt.Errorf("config index out of range")
 		}
 
-		configPath := svcCfg.Configs[configIndex]
+		configPath := configs[configIndex]
 		content, err := os.ReadFile(configPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read config file: %w", err)
@@ -118,20 +200,339 @@ func (m *Manager) GetConfigContent(ctx context.Context, serviceID string, config
 		}, nil
 	}
 
-	return nil, fmt.Errorf("service not found: %s", serviceID)
+	return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, serviceID)
+}
+
+// ServiceAction runs a container lifecycle action (see docker.Actions) on a
+// service and returns the container's new state. Container operations are
+// specific to the docker backend; other services return ErrNotContainer.
+func (m *Manager) ServiceAction(ctx context.Context, serviceID, action string) (*docker.ContainerInfo, error) {
+	if !slices.Contains(docker.Actions, action) {
+		return nil, fmt.Errorf("%w: %s", docker.ErrUnknownAction, action)
+	}
+
+	for _, svcCfg := range m.services() {
+		if generateID(svcCfg.Name) != serviceID {
+			continue
+		}
+
+		if !svcCfg.AllowActions {
+			return nil, ErrActionsNotAllowed
+		}
+		backend, err := m.backend(svcCfg)
+		if err != nil {
+			return nil, err
+		}
+		containers, ok := backend.(*dockerBackend)
+		if !ok {
+			return nil, ErrNotContainer
+		}
+		return containers.Act(ctx, svcCfg, action)
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, serviceID)
+}
+
+// ServiceLogs opens the container log of a service. Close the stream when done.
+func (m *Manager) ServiceLogs(ctx context.Context, serviceID string, opts docker.LogOptions) (*docker.LogStream, error) {
+	for _, svcCfg := range m.services() {
+		if generateID(svcCfg.Name) != serviceID {
+			continue
+		}
+
+		backend, err := m.backend(svcCfg)
+		if err != nil {
+			return nil, err
+		}
+		containers, ok := backend.(*dockerBackend)
+		if !ok {
+			return nil, ErrNotContainer
+		}
+		return containers.Logs(ctx, svcCfg, opts)
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, serviceID)
+}
+
+// ServiceInspect returns details of a service's container
+func (m *Manager) ServiceInspect(ctx context.Context, serviceID string) (*docker.ContainerDetails, error) {
+	for _, svcCfg := range m.services() {
+		if generateID(svcCfg.Name) != serviceID {
+			continue
+		}
+
+		backend, err := m.backend(svcCfg)
+		if err != nil {
+			return nil, err
+		}
+		containers, ok := backend.(*dockerBackend)
+		if !ok {
+			return nil, ErrNotContainer
+		}
+		return containers.Inspect(ctx, svcCfg)
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, serviceID)
+}
+
+// backend returns the backend of a service. Container backends that failed
+// to start, such as docker without a reachable daemon, are unavailable.
+func (m *Manager) backend(svcCfg config.ServiceConfig) (StatusBackend, error) {
+	backend, ok := m.backends[svcCfg.Backend]
+	if !ok {
+		return nil, fmt.Errorf("%s %w", svcCfg.Backend, ErrBackendUnavailable)
+	}
+	return backend, nil
+}
+
+// AutoHealEvents returns the automatic restarts of auto_heal services, newest first
+func (m *Manager) AutoHealEvents() []docker.HealEvent {
+	backend, ok := m.backends["docker"].(*dockerBackend)
+	if !ok {
+		return []docker.HealEvent{}
+	}
+	return backend.HealEvents()
+}
+
+// DockerDiskUsage returns the disk usage of every connected docker daemon
+func (m *Manager) DockerDiskUsage(ctx context.Context) ([]*docker.DiskUsage, error) {
+	backend, ok := m.backends["docker"].(*dockerBackend)
+	if !ok {
+		return nil, ErrDockerUnavailable
+	}
+	return backend.DiskUsage(ctx)
+}
+
+// DockerPrune removes unused data on a docker daemon ("" for the local one)
+func (m *Manager) DockerPrune(ctx context.Context, dockerHost string, opts docker.PruneOptions) (*docker.PruneReport, error) {
+	if !m.cfg.Server.AllowPrune {
+		return nil, ErrPruneNotAllowed
+	}
+	backend, ok := m.backends["docker"].(*dockerBackend)
+	if !ok {
+		return nil, ErrDockerUnavailable
+	}
+	return backend.Prune(ctx, dockerHost, opts)
+}
+
+// KumaMonitors lists the monitors of the Uptime Kuma instance, each with the
+// configured service that uses it. Services are matched against the running
+// configuration, so monitors added by a written sync show as unconfigured
+// until the restart that applies it.
+func (m *Manager) KumaMonitors(ctx context.Context) ([]kuma.Monitor, error) {
+	if m.cfg.UptimeKuma == nil {
+		return nil, ErrKumaNotConfigured
+	}
+	backend, ok := m.backends["uptime_kuma"].(*kumaBackend)
+	if !ok {
+		return nil, fmt.Errorf("uptime_kuma %w", ErrBackendUnavailable)
+	}
+	monitors, err := backend.Monitors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kuma monitors: %w", err)
+	}
+
+	configured := make(map[int]string)
+	for _, svc := range m.cfg.Services {
+		if svc.Backend == "uptime_kuma" {
+			configured[svc.KumaMonitorID] = svc.Name
+		}
+	}
+	for i := range monitors {
+		monitors[i].Service = configured[monitors[i].ID]
+	}
+	return monitors, nil
+}
+
+// SyncKumaMonitors adds or updates the uptime_kuma services in the config file
+// to match the Uptime Kuma instance. Written changes apply after a restart.
+func (m *Manager) SyncKumaMonitors(ctx context.Context, dryRun bool) (*kuma.SyncResult, error) {
+	if !dryRun && !m.cfg.Server.AllowKumaSync {
+		return nil, ErrKumaSyncNotAllowed
+	}
+	if m.cfg.Path == "" {
+		return nil, errors.New("configuration was not loaded from a file")
+	}
+	monitors, err := m.KumaMonitors(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := kuma.SyncConfig(m.cfg.Path, monitors, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithFields(logrus.Fields{
+		"added":   len(result.Added),
+		"updated": len(result.Updated),
+		"missing": len(result.Missing),
+		"dry_run": dryRun,
+		"written": result.Written,
+	}).Info("Synced Uptime Kuma monitors")
+	return result, nil
+}
+
+// ServiceHistory returns a metric of a local service over the span up to now
+func (m *Manager) ServiceHistory(serviceID string, metric history.Metric, span, step time.Duration) (*history.Series, error) {
+	if m.history == nil {
+		return nil, ErrHistoryDisabled
+	}
+	found := false
+	for _, svc := range m.services() {
+		if generateID(svc.Name) == serviceID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, serviceID)
+	}
+	return m.history.Query(serviceID, metric, span, step, time.Now())
+}
+
+// HostStats returns the latest host sample
+func (m *Manager) HostStats() models.HostStats {
+	return m.host.Stats()
+}
+
+// HostHistory queries the cpu or memory history of the host. Ranges within
+// host.Window with steps finer than history.interval are served from memory,
+// everything else from the history database.
+func (m *Manager) HostHistory(metric history.Metric, span, step time.Duration) (*history.Series, error) {
+	if metric != history.MetricCPU && metric != history.MetricMemory {
+		return nil, fmt.Errorf("%w: the host only has cpu and memory history", history.ErrInvalidQuery)
+	}
+	now := time.Now()
+	if m.history != nil && (span > host.Window || step >= m.cfg.History.Interval) {
+		return m.history.Query(history.HostID, metric, span, step, now)
+	}
+	if span > host.Window {
+		return nil, fmt.Errorf("%w: only the last %s is kept without history.path", ErrHistoryDisabled, host.Window)
+	}
+	return m.host.History(metric, span, step, now)
+}
+
+// historySamples returns the current status, CPU, memory and latency of
+// every local service, and the host's average since the last recording, for
+// the history recorder
+func (m *Manager) historySamples(ctx context.Context) []history.Sample {
+	services := m.GetAll(ctx)
+	samples := make([]history.Sample, 0, len(services)+1)
+	if avg, ok := m.host.Average(time.Now().Add(-m.cfg.History.Interval)); ok {
+		samples = append(samples, avg)
+	}
+	for _, svc := range services {
+		samples = append(samples, history.Sample{
+			ServiceID: svc.ID,
+			Status:    svc.Status,
+			CPU:       svc.CPUUsage,
+			Memory:    svc.MemoryUsage,
+			Latency:   svc.Latency,
+		})
+	}
+	return samples
+}
+
+// BackendHealth returns the connection state of every backend that depends on
+// a daemon, including backends that could not be started
+func (m *Manager) BackendHealth() []models.BackendHealth {
+	names := make([]string, 0, len(m.backends)+len(m.unavailable))
+	for name := range m.backends {
+		names = append(names, name)
+	}
+	for name := range m.unavailable {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := []models.BackendHealth{}
+	for _, name := range names {
+		if err, failed := m.unavailable[name]; failed {
+			result = append(result, models.BackendHealth{
+				Backend: name,
+				Error:   err.Error(),
+			})
+			continue
+		}
+		if backend, ok := m.backends[name].(HealthBackend); ok {
+			result = append(result, backend.Health()...)
+		}
+	}
+	return result
+}
+
+// services returns the configured services followed by discovered ones.
+// Discovered services are skipped if a configured service has the same name
+// or the same container.
+func (m *Manager) services() []config.ServiceConfig {
+	result := append([]config.ServiceConfig(nil), m.cfg.Services...)
+
+	seen := make(map[string]bool, len(result))
+	containers := make(map[string]bool, len(result))
+	for _, svc := range result {
+		seen[svc.Name] = true
+		if svc.ContainerName != "" {
+			containers[containerKey(svc)] = true
+		}
+	}
+
+	// Sort backend names so discovered services keep a stable order
+	names := make([]string, 0, len(m.backends))
+	for name := range m.backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		discovery, ok := m.backends[name].(DiscoveryBackend)
+		if !ok {
+			continue
+		}
+		for _, svc := range discovery.Discovered() {
+			if !seen[svc.Name] && !containers[containerKey(svc)] {
+				seen[svc.Name] = true
+				result = append(result, svc)
+			}
+		}
+	}
+
+	return result
+}
+
+// containerKey identifies the container behind a service across backends and daemons
+func containerKey(svc config.ServiceConfig) string {
+	return svc.Backend + "/" + svc.DockerHost + "/" + svc.ContainerName
+}
+
+// configFiles returns a service's configured config files followed by any
+// its backend found, such as the compose file of a compose project
+func (m *Manager) configFiles(cfg config.ServiceConfig) []string {
+	files := append([]string(nil), cfg.Configs...)
+
+	backend, ok := m.backends[cfg.Backend].(ConfigBackend)
+	if !ok {
+		return files
+	}
+	for _, path := range backend.ConfigFiles(cfg) {
+		if !slices.Contains(files, path) {
+			files = append(files, path)
+		}
+	}
+	return files
 }
 
 // buildService constructs a Service model from config and live data
 func (m *Manager) buildService(ctx context.Context, cfg config.ServiceConfig) models.Service {
 	svc := models.Service{
-		ID:   generateID(cfg.Name),
-		Name: cfg.Name,
-		URL:  cfg.URL,
-		Port: cfg.Port,
+		ID:           generateID(cfg.Name),
+		Name:         cfg.Name,
+		URL:          cfg.URL,
+		Port:         cfg.Port,
+		AllowActions: cfg.AllowActions,
 	}
 
 	// Build configs list (without content - lazy loaded)
-	for _, path := range cfg.Configs {
+	for _, path := range m.configFiles(cfg) {
 		svc.Configs = append(svc.Configs, models.ServiceConfig{
 			Path:       path,
 			Type:       detectConfigType(path),
@@ -139,71 +540,88 @@ func (m *Manager) buildService(ctx context.Context, cfg config.ServiceConfig) mo
 		})
 	}
 
-	// Get status based on backend
-	switch cfg.Backend {
-	case "docker":
-		m.populateDockerStatus(ctx, &svc, cfg.ContainerName)
-	case "uptime_kuma":
-		m.populateKumaStatus(ctx, &svc, cfg.KumaMonitorID)
-	default:
+	// Get status from the service's backend
+	backend, ok := m.backends[cfg.Backend]
+	if !ok {
 		svc.Status = "ERROR"
+		return svc
 	}
+	backend.Populate(ctx, &svc, cfg)
+
+	m.applyCertificate(&svc, cfg)
 
 	return svc
 }
 
-// populateDockerStatus fills in status from Docker
-func (m *Manager) populateDockerStatus(ctx context.Context, svc *models.Service, containerName string) {
-	if m.dockerDisabled || m.dockerClient == nil {
-		svc.Status = "ERROR"
+// Certificates returns the latest certificate checks, soonest expiry first
+func (m *Manager) Certificates() []certs.Info {
+	return m.certs.List()
+}
+
+// applyCertificate records certificate expiry on a service and degrades its status
+// when the certificate is expiring (WARNING) or has expired (ERROR)
+func (m *Manager) applyCertificate(svc *models.Service, cfg config.ServiceConfig) {
+	info, ok := m.certs.ForService(generateID(cfg.Name))
+	if !ok || info.NotAfter.IsZero() {
 		return
 	}
 
-	// Try cache first
-	if cached := m.dockerStats.GetCachedStats(containerName); cached != nil {
-		svc.Status = cached.Status
-		svc.CPUUsage = cached.CPUPercent
-		svc.MemoryUsage = cached.MemoryMB
-		svc.Uptime = formatUptime(cached.StartedAt)
+	days := info.DaysUntilExpiry
+	svc.CertExpiresInDays = &days
+
+	if svc.Status != "RUNNING" {
 		return
 	}
-
-	// Fallback to live query
-	info, err := m.dockerClient.GetContainerInfo(ctx, containerName)
-	if err != nil {
+	switch info.Status {
+	case "EXPIRED":
 		svc.Status = "ERROR"
-		return
+	case "EXPIRING":
+		svc.Status = "WARNING"
 	}
+}
 
-	svc.Status = info.Status
-	svc.Uptime = formatUptime(info.StartedAt)
+// certTargets returns the TLS endpoints to track: configured and discovered
+// services with an https url, and the explicit tls_check list
+func (m *Manager) certTargets() []certs.Target {
+	var targets []certs.Target
 
-	// Try to get stats
-	if info.Status == "RUNNING" {
-		if stats, err := m.dockerClient.GetContainerStats(ctx, info.ID); err == nil {
-			svc.CPUUsage = stats.CPUPercent
-			svc.MemoryUsage = stats.MemoryMB
+	for _, svc := range m.services() {
+		u, err := url.Parse(svc.URL)
+		if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+			continue
 		}
-	}
-}
 
-// populateKumaStatus fills in status from Uptime Kuma
-func (m *Manager) populateKumaStatus(ctx context.Context, svc *models.Service, monitorID int) {
-	if m.kumaClient == nil {
-		svc.Status = "ERROR"
-		return
+		port := u.Port()
+		if port == "" {
+			port = "443"
+			if svc.Port > 0 {
+				port = strconv.Itoa(svc.Port)
+			}
+		}
+
+		targets = append(targets, certs.Target{
+			Name:      svc.Name,
+			Address:   net.JoinHostPort(u.Hostname(), port),
+			ServiceID: generateID(svc.Name),
+		})
 	}
 
-	status, err := m.kumaClient.GetMonitorStatus(ctx, monitorID)
-	if err != nil {
-		svc.Status = "ERROR"
-		return
+	for _, check := range m.cfg.Certificates.TLSCheck {
+		targets = append(targets, certs.Target{
+			Name:    check.Name,
+			Address: check.Address,
+		})
 	}
 
-	svc.Status = status.Status
-	if status.Uptime > 0 {
-		svc.Uptime = fmt.Sprintf("%.1f%% uptime", status.Uptime)
+	return targets
+}
+
+// warningDays returns certificates.warning_days, where 0 disables expiry warnings
+func warningDays(cfg *config.Config) int {
+	if cfg.Certificates.WarningDays == nil {
+		return config.DefaultWarningDays
 	}
+	return *cfg.Certificates.WarningDays
 }
 
 // generateID creates a unique ID from a service name
@@ -2,10 +2,17 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"home-run-backend/internal/config"
+	"home-run-backend/internal/models"
+	"home-run-backend/internal/services/docker"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -56,6 +63,427 @@ func TestManager_GetByID_NotFound(t *testing.T) {
 	assert.Contains(t, err.Error(), "service not found")
 }
 
+// fakeBackend is a StatusBackend that reports a fixed status
+type fakeBackend struct {
+	status  string
+	started bool
+	stopped bool
+}
+
+func (f *fakeBackend) Populate(_ context.Context, svc *models.Service, cfg config.ServiceConfig) {
+	svc.Status = f.status
+	svc.Uptime = cfg.ContainerName
+}
+
+func (f *fakeBackend) Start(_ context.Context) { f.started = true }
+
+func (f *fakeBackend) Stop() { f.stopped = true }
+
+func TestManager_DispatchesToRegisteredBackend(t *testing.T) {
+	fake := &fakeBackend{status: "RUNNING"}
+	var routed []config.ServiceConfig
+	RegisterBackend("fake", func(_ *config.Config, services []config.ServiceConfig) (StatusBackend, error) {
+		routed = services
+		return fake, nil
+	}, nil)
+
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{
+			{Name: "Fake Service", Backend: "fake", ContainerName: "fake-1"},
+			{Name: "Unknown Service", Backend: "does-not-exist"},
+		},
+	}
+
+	manager, err := NewManager(cfg)
+	require.NoError(t, err)
+
+	manager.Start(context.Background())
+	assert.True(t, fake.started)
+
+	services := manager.GetAll(context.Background())
+	require.Len(t, services, 2)
+	assert.Equal(t, "RUNNING", services[0].Status)
+	assert.Equal(t, "fake-1", services[0].Uptime)
+	assert.Equal(t, "ERROR", services[1].Status)
+
+	// Only services configured with the backend type are routed to it
+	require.Len(t, routed, 1)
+	assert.Equal(t, "Fake Service", routed[0].Name)
+
+	manager.Stop()
+	assert.True(t, fake.stopped)
+}
+
+// loadConfig loads a config file with the given services section
+func loadConfig(t *testing.T, services string) (*config.Config, error) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	content := "auth:\n  username: admin\n  password: admin\n  api_token: token\n\n" + services
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return config.Load(path)
+}
+
+func TestRegisterBackend_LoadsConfig(t *testing.T) {
+	// An in-house backend registered only with the services registry
+	RegisterBackend("inhouse", func(_ *config.Config, _ []config.ServiceConfig) (StatusBackend, error) {
+		return &fakeBackend{status: "RUNNING"}, nil
+	}, nil)
+
+	cfg, err := loadConfig(t, "services:\n  - name: In-House\n    backend: inhouse\n")
+	require.NoError(t, err)
+
+	manager, err := NewManager(cfg)
+	require.NoError(t, err)
+	defer manager.Stop()
+
+	svc, err := manager.GetByID(context.Background(), generateID("In-House"))
+	require.NoError(t, err)
+	assert.Equal(t, "RUNNING", svc.Status)
+}
+
+func TestRegisterBackend_Validator(t *testing.T) {
+	RegisterBackend("validated", func(_ *config.Config, _ []config.ServiceConfig) (StatusBackend, error) {
+		return &fakeBackend{}, nil
+	}, func(_ *config.Config, index int, svc config.ServiceConfig) error {
+		if svc.URL == "" {
+			return fmt.Errorf("services[%d].url is required", index)
+		}
+		return nil
+	})
+
+	_, err := loadConfig(t, "services:\n  - name: Validated\n    backend: validated\n")
+	assert.ErrorContains(t, err, "services[0].url is required")
+}
+
+func TestManager_UnavailableBackend(t *testing.T) {
+	RegisterBackend("broken", func(_ *config.Config, _ []config.ServiceConfig) (StatusBackend, error) {
+		return nil, assert.AnError
+	}, nil)
+
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{
+			{Name: "Broken Service", Backend: "broken"},
+		},
+	}
+
+	manager, err := NewManager(cfg)
+	require.NoError(t, err)
+	defer manager.Stop()
+
+	svc, err := manager.GetByID(context.Background(), generateID("Broken Service"))
+	require.NoError(t, err)
+	assert.Equal(t, "ERROR", svc.Status)
+
+	health := manager.BackendHealth()
+	require.Len(t, health, 1)
+	assert.Equal(t, "broken", health[0].Backend)
+	assert.False(t, health[0].Connected)
+	assert.Equal(t, assert.AnError.Error(), health[0].Error)
+}
+
+func TestManager_HistoryOpenFails(t *testing.T) {
+	started := false
+	RegisterBackend("tracked", func(_ *config.Config, _ []config.ServiceConfig) (StatusBackend, error) {
+		started = true
+		return &fakeBackend{status: "RUNNING"}, nil
+	}, nil)
+
+	cfg := &config.Config{
+		History:  config.HistoryConfig{Path: t.TempDir()}, // a directory can't be opened as a database
+		Services: []config.ServiceConfig{{Name: "Tracked", Backend: "tracked"}},
+	}
+
+	_, err := NewManager(cfg)
+	assert.Error(t, err)
+	assert.False(t, started, "backends must not be created when the history store fails to open")
+}
+
+func TestManager_ContainerOperations(t *testing.T) {
+	RegisterBackend("broken", func(_ *config.Config, _ []config.ServiceConfig) (StatusBackend, error) {
+		return nil, assert.AnError
+	}, nil)
+	RegisterBackend("fake", func(_ *config.Config, _ []config.ServiceConfig) (StatusBackend, error) {
+		return &fakeBackend{status: "RUNNING"}, nil
+	}, nil)
+
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{
+			{Name: "Broken Service", Backend: "broken", ContainerName: "broken", AllowActions: true},
+			{Name: "Fake Service", Backend: "fake", AllowActions: true},
+		},
+	}
+
+	manager, err := NewManager(cfg)
+	require.NoError(t, err)
+	defer manager.Stop()
+	ctx := context.Background()
+
+	tests := []struct {
+		service  string
+		expected error
+	}{
+		{"Broken Service", ErrBackendUnavailable}, // the backend failed to start
+		{"Fake Service", ErrNotContainer},         // the backend has no containers
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.service, func(t *testing.T) {
+			id := generateID(tt.service)
+			_, err := manager.ServiceAction(ctx, id, "restart")
+			assert.ErrorIs(t, err, tt.expected)
+			_, err = manager.ServiceLogs(ctx, id, docker.LogOptions{})
+			assert.ErrorIs(t, err, tt.expected)
+			_, err = manager.ServiceInspect(ctx, id)
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestManager_ProbeNotRunYet(t *testing.T) {
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{
+			{Name: "Website", Backend: "http", URL: "http://127.0.0.1", Port: 1},
+		},
+	}
+	manager, err := NewManager(cfg)
+	require.NoError(t, err)
+	defer manager.Stop()
+
+	// The runner hasn't started, so the request must not probe on its own
+	svc, err := manager.GetByID(context.Background(), generateID("Website"))
+	require.NoError(t, err)
+	assert.Equal(t, "UNKNOWN", svc.Status)
+}
+
+func TestManager_ProcessNotCollectedYet(t *testing.T) {
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{
+			{Name: "Self", Backend: "process", Process: &config.ProcessMatchConfig{Name: "home-run"}},
+		},
+	}
+	manager, err := NewManager(cfg)
+	require.NoError(t, err)
+	defer manager.Stop()
+
+	// The collector hasn't started, so the request must not list processes itself
+	svc, err := manager.GetByID(context.Background(), generateID("Self"))
+	require.NoError(t, err)
+	assert.Equal(t, "UNKNOWN", svc.Status)
+}
+
+func TestManager_PodmanSocketDown(t *testing.T) {
+	cfg := &config.Config{
+		Podman: &config.PodmanConfig{Socket: "unix://" + filepath.Join(t.TempDir(), "podman.sock")},
+		Services: []config.ServiceConfig{
+			{Name: "Web", Backend: "podman", ContainerName: "web"},
+		},
+	}
+
+	// The socket isn't up yet: the backend is kept and reconnected later
+	manager, err := NewManager(cfg)
+	require.NoError(t, err)
+	defer manager.Stop()
+
+	svc, err := manager.GetByID(context.Background(), generateID("Web"))
+	require.NoError(t, err)
+	assert.Equal(t, "UNKNOWN", svc.Status)
+
+	health := manager.BackendHealth()
+	require.Len(t, health, 1)
+	assert.Equal(t, "podman", health[0].Backend)
+	assert.False(t, health[0].Connected)
+	assert.NotEmpty(t, health[0].Error)
+}
+
+// fakeDiscovery is a backend that also discovers services
+type fakeDiscovery struct {
+	fakeBackend
+	discovered []config.ServiceConfig
+}
+
+func (f *fakeDiscovery) Discovered() []config.ServiceConfig { return f.discovered }
+
+func TestManager_MergesDiscoveredServices(t *testing.T) {
+	discovery := &fakeDiscovery{fakeBackend: fakeBackend{status: "RUNNING"}}
+	RegisterBackend("fake-discovery", func(_ *config.Config, _ []config.ServiceConfig) (StatusBackend, error) {
+		return discovery, nil
+	}, nil)
+
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{
+			{Name: "Static", Backend: "fake-discovery", ContainerName: "static-1"},
+		},
+	}
+
+	manager, err := NewManager(cfg)
+	require.NoError(t, err)
+	defer manager.Stop()
+
+	ctx := context.Background()
+	assert.Len(t, manager.GetAll(ctx), 1)
+
+	// A discovered service appears, one clashing with a configured name is ignored
+	discovery.discovered = []config.ServiceConfig{
+		{Name: "Static", Backend: "fake-discovery", ContainerName: "other"},
+		{Name: "Discovered", Backend: "fake-discovery", ContainerName: "discovered-1"},
+	}
+	services := manager.GetAll(ctx)
+	require.Len(t, services, 2)
+	assert.Equal(t, "static-1", services[0].Uptime)
+	assert.Equal(t, "Discovered", services[1].Name)
+
+	svc, err := manager.GetByID(ctx, generateID("Discovered"))
+	require.NoError(t, err)
+	assert.Equal(t, "RUNNING", svc.Status)
+
+	// And disappears again once the container is gone
+	discovery.discovered = nil
+	assert.Len(t, manager.GetAll(ctx), 1)
+}
+
+func TestManager_SkipsDiscoveredConfiguredContainers(t *testing.T) {
+	discovery := &fakeDiscovery{fakeBackend: fakeBackend{status: "RUNNING"}}
+	RegisterBackend("fake-labelled", func(_ *config.Config, _ []config.ServiceConfig) (StatusBackend, error) {
+		return discovery, nil
+	}, nil)
+
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{
+			{Name: "Bazarr", Backend: "fake-labelled", ContainerName: "bazarr"},
+		},
+	}
+	manager, err := NewManager(cfg)
+	require.NoError(t, err)
+	defer manager.Stop()
+
+	// The configured container is labelled too, without a home-run.name
+	discovery.discovered = []config.ServiceConfig{
+		{Name: "bazarr", Backend: "fake-labelled", ContainerName: "bazarr"},
+		{Name: "bazarr", Backend: "fake-labelled", ContainerName: "bazarr", DockerHost: "nas"},
+	}
+	services := manager.GetAll(context.Background())
+	require.Len(t, services, 2)
+	assert.Equal(t, "Bazarr", services[0].Name)
+	assert.Equal(t, "bazarr", services[1].Name) // same name on another daemon
+}
+
+// fakeConfigs is a backend that finds config files of its services
+type fakeConfigs struct {
+	fakeBackend
+	files []string
+}
+
+func (f *fakeConfigs) ConfigFiles(config.ServiceConfig) []string { return f.files }
+
+func TestManager_AttachesBackendConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	compose := filepath.Join(dir, "compose.yml")
+	require.NoError(t, os.WriteFile(compose, []byte("services: {}\n"), 0o644))
+
+	RegisterBackend("fake-configs", func(_ *config.Config, _ []config.ServiceConfig) (StatusBackend, error) {
+		return &fakeConfigs{fakeBackend: fakeBackend{status: "RUNNING"}, files: []string{"/etc/app.conf", compose}}, nil
+	}, nil)
+
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{
+			{Name: "Maps", Backend: "fake-configs", Configs: []string{"/etc/app.conf"}},
+		},
+	}
+
+	manager, err := NewManager(cfg)
+	require.NoError(t, err)
+	defer manager.Stop()
+
+	ctx := context.Background()
+	svc, err := manager.GetByID(ctx, generateID("Maps"))
+	require.NoError(t, err)
+	require.Len(t, svc.Configs, 2)
+	assert.Equal(t, compose, svc.Configs[1].Path)
+
+	content, err := manager.GetConfigContent(ctx, svc.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "services: {}\n", content.Content)
+	assert.Len(t, cfg.Services[0].Configs, 1)
+}
+
+func TestCertTargets(t *testing.T) {
+	discovery := &fakeDiscovery{fakeBackend: fakeBackend{status: "RUNNING"}}
+	RegisterBackend("fake-https", func(_ *config.Config, _ []config.ServiceConfig) (StatusBackend, error) {
+		return discovery, nil
+	}, nil)
+
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{
+			{Name: "Plain", URL: "http://localhost", Port: 8080, Backend: "fake-https"},
+			{Name: "Default Port", URL: "https://example.com", Backend: "fake-https"},
+			{Name: "Service Port", URL: "https://ha.lan", Port: 8123, Backend: "fake-https"},
+			{Name: "URL Port", URL: "https://nas.lan:5001", Port: 80, Backend: "fake-https"},
+		},
+		Certificates: config.CertificatesConfig{
+			TLSCheck: []config.TLSCheckConfig{{Name: "LDAP", Address: "ldap.lan:636"}},
+		},
+	}
+
+	manager, err := NewManager(cfg)
+	require.NoError(t, err)
+	defer manager.Stop()
+
+	targets := manager.certTargets()
+	require.Len(t, targets, 4)
+	assert.Equal(t, "example.com:443", targets[0].Address)
+	assert.Equal(t, generateID("Default Port"), targets[0].ServiceID)
+	assert.Equal(t, "ha.lan:8123", targets[1].Address)
+	assert.Equal(t, "nas.lan:5001", targets[2].Address)
+	assert.Equal(t, "LDAP", targets[3].Name)
+	assert.Empty(t, targets[3].ServiceID)
+
+	// Services discovered after startup are picked up on the next round
+	discovery.discovered = []config.ServiceConfig{
+		{Name: "Grafana", URL: "https://grafana.lan", Backend: "fake-https", ContainerName: "grafana"},
+	}
+	targets = manager.certTargets()
+	require.Len(t, targets, 5)
+	assert.Equal(t, generateID("Grafana"), targets[3].ServiceID)
+	assert.Equal(t, "grafana.lan:443", targets[3].Address)
+}
+
+func TestManager_ExpiringCertificateWarns(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	RegisterBackend("fake-running", func(_ *config.Config, _ []config.ServiceConfig) (StatusBackend, error) {
+		return &fakeBackend{status: "RUNNING"}, nil
+	}, nil)
+
+	// The test certificate is valid for years, so a huge threshold marks it
+	// as expiring. A failing tls_check of the same name must not replace it.
+	warningDays := 100000
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{
+			{Name: "Secure", URL: server.URL, Backend: "fake-running"},
+		},
+		Certificates: config.CertificatesConfig{
+			WarningDays: &warningDays,
+			Interval:    time.Hour,
+			TLSCheck:    []config.TLSCheckConfig{{Name: "Secure", Address: "127.0.0.1:1"}},
+		},
+	}
+
+	manager, err := NewManager(cfg)
+	require.NoError(t, err)
+	manager.Start(context.Background())
+	defer manager.Stop()
+
+	require.Eventually(t, func() bool {
+		return len(manager.Certificates()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	svc, err := manager.GetByID(context.Background(), generateID("Secure"))
+	require.NoError(t, err)
+	assert.Equal(t, "WARNING", svc.Status)
+	require.NotNil(t, svc.CertExpiresInDays)
+	assert.Positive(t, *svc.CertExpiresInDays)
+}
+
 func TestGenerateID(t *testing.T) {
 	id1 := generateID("test-service")
 	id2 := generateID("test-service")
@@ -112,3 +540,22 @@ func TestFormatUptime(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_KumaMonitorsWithoutServices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`monitor_status{monitor_id="7",monitor_name="Nginx",monitor_type="http",monitor_url="https://example.com",monitor_hostname="null",monitor_port="null"} 1` + "\n"))
+	}))
+	defer server.Close()
+
+	// Importing monitors works before any service uses the uptime_kuma backend
+	cfg := &config.Config{UptimeKuma: &config.UptimeKumaConfig{URL: server.URL, Interval: time.Hour}}
+	manager, err := NewManager(cfg)
+	require.NoError(t, err)
+	defer manager.Stop()
+
+	monitors, err := manager.KumaMonitors(context.Background())
+	require.NoError(t, err)
+	require.Len(t, monitors, 1)
+	assert.Equal(t, 7, monitors[0].ID)
+	assert.Empty(t, monitors[0].Service)
+}
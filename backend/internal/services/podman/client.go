@@ -0,0 +1,182 @@
+package podman
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"home-run-backend/internal/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// apiPrefix is the libpod API version used for pod queries
+const apiPrefix = "/v4.0.0/libpod"
+
+// PodContainer is a container that belongs to a pod
+type PodContainer struct {
+	ID        string
+	Name      string
+	State     string // raw podman state
+	StartedAt time.Time
+}
+
+// PodInfo holds the rolled-up status of a pod
+type PodInfo struct {
+	Name       string
+	Status     string // RUNNING, STOPPED, WARNING (partially running), MAINTENANCE
+	Running    int
+	Total      int
+	StartedAt  time.Time // earliest start among running containers
+	Containers []PodContainer
+}
+
+// Client talks to the Podman libpod API over its socket
+type Client struct {
+	host       string
+	httpClient *http.Client
+}
+
+// DefaultSocket returns the rootless Podman socket of the current user,
+// falling back to the rootful socket when XDG_RUNTIME_DIR is not set
+func DefaultSocket() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return "unix://" + filepath.Join(dir, "podman", "podman.sock")
+	}
+	return "unix:///run/podman/podman.sock"
+}
+
+// NewClient creates a libpod client for a unix:// socket
+func NewClient(host string) (*Client, error) {
+	socket, ok := strings.CutPrefix(host, "unix://")
+	if !ok {
+		return nil, fmt.Errorf("podman host must be a unix:// socket, got '%s'", host)
+	}
+
+	return &Client{
+		host: host,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", socket)
+				},
+			},
+		},
+	}, nil
+}
+
+// listContainer is the subset of the libpod container list entry we use
+type listContainer struct {
+	ID        string   `json:"Id"`
+	Names     []string `json:"Names"`
+	State     string   `json:"State"`
+	StartedAt int64    `json:"StartedAt"`
+	IsInfra   bool     `json:"IsInfra"`
+}
+
+// GetPodInfo retrieves the containers of a pod and rolls up their status
+func (c *Client) GetPodInfo(ctx context.Context, pod string) (*PodInfo, error) {
+	filters, err := json.Marshal(map[string][]string{"pod": {pod}})
+	if err != nil {
+		return nil, err
+	}
+	reqURL := fmt.Sprintf("http://podman%s/containers/json?all=true&filters=%s", apiPrefix, url.QueryEscape(string(filters)))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"pod":   pod,
+			"host":  c.host,
+			"error": err.Error(),
+		}).Warn("Failed to list pod containers")
+		return nil, fmt.Errorf("failed to list pod containers: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("podman returned status %d", resp.StatusCode)
+	}
+
+	var containers []listContainer
+	if err := json.NewDecoder(resp.Body).Decode(&containers); err != nil {
+		return nil, fmt.Errorf("failed to decode container list: %w", err)
+	}
+
+	info := rollUp(pod, containers)
+	if info.Total == 0 {
+		return nil, fmt.Errorf("pod '%s' not found", pod)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"pod":     pod,
+		"status":  info.Status,
+		"running": info.Running,
+		"total":   info.Total,
+	}).Debug("Retrieved pod info")
+
+	return info, nil
+}
+
+// rollUp derives pod status from its containers, ignoring the infra container
+func rollUp(pod string, containers []listContainer) *PodInfo {
+	info := &PodInfo{Name: pod}
+	paused := 0
+
+	for _, ctr := range containers {
+		if ctr.IsInfra {
+			continue
+		}
+
+		member := PodContainer{ID: ctr.ID, State: ctr.State}
+		if len(ctr.Names) > 0 {
+			member.Name = ctr.Names[0]
+		}
+		if ctr.StartedAt > 0 {
+			member.StartedAt = time.Unix(ctr.StartedAt, 0)
+		}
+		info.Containers = append(info.Containers, member)
+		info.Total++
+
+		switch strings.ToLower(ctr.State) {
+		case "running":
+			info.Running++
+			if info.StartedAt.IsZero() || member.StartedAt.Before(info.StartedAt) {
+				info.StartedAt = member.StartedAt
+			}
+		case "paused":
+			paused++
+		}
+	}
+
+	sort.Slice(info.Containers, func(i, j int) bool {
+		return info.Containers[i].Name < info.Containers[j].Name
+	})
+
+	switch {
+	case info.Total > 0 && info.Running == info.Total:
+		info.Status = "RUNNING"
+	case info.Total > 0 && paused == info.Total:
+		info.Status = "MAINTENANCE"
+	case info.Running > 0:
+		info.Status = "WARNING"
+	default:
+		info.Status = "STOPPED"
+	}
+
+	return info
+}
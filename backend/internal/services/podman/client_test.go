@@ -0,0 +1,112 @@
+package podman
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startLibpod serves a fake libpod container list on a unix socket
+func startLibpod(t *testing.T, pods map[string][]listContainer) string {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), "podman.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != apiPrefix+"/containers/json" {
+			http.NotFound(w, r)
+			return
+		}
+		var filters map[string][]string
+		if err := json.Unmarshal([]byte(r.URL.Query().Get("filters")), &filters); err != nil || len(filters["pod"]) != 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		containers := pods[filters["pod"][0]]
+		if containers == nil {
+			containers = []listContainer{}
+		}
+		_ = json.NewEncoder(w).Encode(containers)
+	}))
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return "unix://" + socket
+}
+
+func TestClient_GetPodInfo(t *testing.T) {
+	early := time.Now().Add(-3 * time.Hour).Unix()
+	late := time.Now().Add(-1 * time.Hour).Unix()
+
+	host := startLibpod(t, map[string][]listContainer{
+		"nextcloud": {
+			{ID: "infra", Names: []string{"nextcloud-infra"}, State: "running", StartedAt: early - 10, IsInfra: true},
+			{ID: "app", Names: []string{"nextcloud-app"}, State: "running", StartedAt: late},
+			{ID: "db", Names: []string{"nextcloud-db"}, State: "running", StartedAt: early},
+		},
+		"immich": {
+			{ID: "server", Names: []string{"immich-server"}, State: "running", StartedAt: late},
+			{ID: "ml", Names: []string{"immich-ml"}, State: "exited"},
+		},
+		"paperless": {
+			{ID: "web", Names: []string{"paperless-web"}, State: "exited"},
+		},
+	})
+
+	client, err := NewClient(host)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	info, err := client.GetPodInfo(ctx, "nextcloud")
+	require.NoError(t, err)
+	assert.Equal(t, "RUNNING", info.Status)
+	assert.Equal(t, 2, info.Running)
+	assert.Equal(t, 2, info.Total)
+	assert.Equal(t, time.Unix(early, 0), info.StartedAt)
+	assert.Equal(t, "nextcloud-app", info.Containers[0].Name)
+
+	info, err = client.GetPodInfo(ctx, "immich")
+	require.NoError(t, err)
+	assert.Equal(t, "WARNING", info.Status)
+	assert.Equal(t, 1, info.Running)
+
+	info, err = client.GetPodInfo(ctx, "paperless")
+	require.NoError(t, err)
+	assert.Equal(t, "STOPPED", info.Status)
+	assert.True(t, info.StartedAt.IsZero())
+
+	_, err = client.GetPodInfo(ctx, "missing")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestRollUp_Paused(t *testing.T) {
+	info := rollUp("pod", []listContainer{
+		{ID: "a", State: "paused"},
+		{ID: "b", State: "paused"},
+	})
+	assert.Equal(t, "MAINTENANCE", info.Status)
+}
+
+func TestNewClient_RequiresUnixSocket(t *testing.T) {
+	_, err := NewClient("tcp://127.0.0.1:2375")
+	assert.Error(t, err)
+}
+
+func TestDefaultSocket(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	assert.Equal(t, "unix:///run/user/1000/podman/podman.sock", DefaultSocket())
+
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	assert.Equal(t, "unix:///run/podman/podman.sock", DefaultSocket())
+}
@@ -0,0 +1,134 @@
+package podman
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"home-run-backend/internal/cache"
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CachedPod holds the cached state of a pod
+type CachedPod struct {
+	Status     string
+	StartedAt  time.Time
+	Running    int
+	Total      int
+	LastUpdate time.Time
+}
+
+// StatsCollector collects pod state periodically
+type StatsCollector struct {
+	client   *Client
+	cache    *cache.Cache
+	pods     []string
+	interval time.Duration
+	mu       sync.Mutex
+	running  bool
+	stopCh   chan struct{}
+}
+
+// NewStatsCollector creates a collector for the pods of the given services
+func NewStatsCollector(client *Client, podCache *cache.Cache, services []config.ServiceConfig, interval time.Duration) *StatsCollector {
+	sc := &StatsCollector{
+		client:   client,
+		cache:    podCache,
+		interval: interval,
+		stopCh:   make(chan struct{}),
+	}
+	for _, svc := range services {
+		if svc.Pod != "" {
+			sc.pods = append(sc.pods, svc.Pod)
+		}
+	}
+	return sc
+}
+
+// Start begins collecting pod state in the background
+func (sc *StatsCollector) Start(ctx context.Context) {
+	sc.mu.Lock()
+	if sc.running {
+		sc.mu.Unlock()
+		return
+	}
+	sc.running = true
+	sc.mu.Unlock()
+
+	if len(sc.pods) == 0 {
+		return
+	}
+
+	logger.WithField("interval", sc.interval).Info("Starting podman pod collector")
+
+	// Collect immediately on start
+	sc.collectAll(ctx)
+
+	go func() {
+		ticker := time.NewTicker(sc.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Log.Info("podman pod collector stopped (context cancelled)")
+				return
+			case <-sc.stopCh:
+				logger.Log.Info("podman pod collector stopped")
+				return
+			case <-ticker.C:
+				sc.collectAll(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the pod collector
+func (sc *StatsCollector) Stop() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.running {
+		close(sc.stopCh)
+		sc.running = false
+	}
+}
+
+// collectAll refreshes the cached state of every pod
+func (sc *StatsCollector) collectAll(ctx context.Context) {
+	logger.Log.Debug("Collecting podman pod state")
+	for _, pod := range sc.pods {
+		info, err := sc.client.GetPodInfo(ctx, pod)
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"pod":   pod,
+				"error": err.Error(),
+			}).Warn("Failed to get pod info")
+			sc.cache.Set(pod, &CachedPod{
+				Status:     "ERROR",
+				LastUpdate: time.Now(),
+			})
+			continue
+		}
+
+		sc.cache.Set(pod, &CachedPod{
+			Status:     info.Status,
+			StartedAt:  info.StartedAt,
+			Running:    info.Running,
+			Total:      info.Total,
+			LastUpdate: time.Now(),
+		})
+	}
+}
+
+// GetCachedPod retrieves the cached state of a pod
+func (sc *StatsCollector) GetCachedPod(pod string) *CachedPod {
+	if val, ok := sc.cache.Get(pod); ok {
+		if cached, ok := val.(*CachedPod); ok {
+			return cached
+		}
+	}
+	return nil
+}
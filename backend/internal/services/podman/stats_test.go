@@ -0,0 +1,45 @@
+package podman
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"home-run-backend/internal/cache"
+	"home-run-backend/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsCollector_CachesPods(t *testing.T) {
+	host := startLibpod(t, map[string][]listContainer{
+		"immich": {
+			{ID: "server", Names: []string{"immich-server"}, State: "running", StartedAt: time.Now().Unix()},
+			{ID: "ml", Names: []string{"immich-ml"}, State: "exited"},
+		},
+	})
+	client, err := NewClient(host)
+	require.NoError(t, err)
+
+	sc := NewStatsCollector(client, cache.New(time.Minute), []config.ServiceConfig{
+		{Name: "Immich", Pod: "immich"},
+		{Name: "Gone", Pod: "missing"},
+		{Name: "Plex", ContainerName: "plex"},
+	}, time.Hour)
+	assert.Equal(t, []string{"immich", "missing"}, sc.pods)
+	assert.Nil(t, sc.GetCachedPod("immich"), "nothing is cached before the first collection")
+
+	sc.Start(context.Background())
+	defer sc.Stop()
+
+	cached := sc.GetCachedPod("immich")
+	require.NotNil(t, cached)
+	assert.Equal(t, "WARNING", cached.Status)
+	assert.Equal(t, 1, cached.Running)
+	assert.Equal(t, 2, cached.Total)
+
+	cached = sc.GetCachedPod("missing")
+	require.NotNil(t, cached)
+	assert.Equal(t, "ERROR", cached.Status)
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"home-run-backend/internal/cache"
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/models"
+	"home-run-backend/internal/services/docker"
+	"home-run-backend/internal/services/podman"
+)
+
+func init() {
+	RegisterBackend("podman", newPodmanBackend, validatePodmanService)
+}
+
+// podmanBackend reports status of Podman containers and pods. Containers are
+// read through Podman's Docker-compatible API, pods through the libpod API.
+type podmanBackend struct {
+	containers *dockerDaemon
+	pods       *podman.StatsCollector
+}
+
+func newPodmanBackend(cfg *config.Config, services []config.ServiceConfig) (StatusBackend, error) {
+	host := podman.DefaultSocket()
+	if cfg.Podman != nil && cfg.Podman.Socket != "" {
+		host = cfg.Podman.Socket
+	}
+
+	// A socket that isn't up yet is reconnected by Watch
+	client, err := docker.OpenClientFromConfig(config.DockerHostConfig{Host: host})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := podman.NewClient(host)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	// Single containers go through the docker stats collector, pods through the pod collector
+	var containers []config.ServiceConfig
+	for _, svc := range services {
+		if svc.ContainerName != "" {
+			containers = append(containers, svc)
+		}
+	}
+
+	return &podmanBackend{
+		containers: newDockerDaemon(client, containers),
+		pods:       podman.NewStatsCollector(pods, cache.New(30*time.Second), services, 10*time.Second),
+	}, nil
+}
+
+// validatePodmanService checks that a podman service names a container or a pod
+func validatePodmanService(_ *config.Config, index int, svc config.ServiceConfig) error {
+	if (svc.ContainerName == "") == (svc.Pod == "") {
+		return fmt.Errorf("services[%d] needs exactly one of container_name or pod for podman backend", index)
+	}
+	return nil
+}
+
+// Start starts the health watcher and the container and pod collectors
+func (b *podmanBackend) Start(ctx context.Context) {
+	go b.containers.client.Watch(ctx)
+	b.containers.stats.Start(ctx)
+	b.pods.Start(ctx)
+}
+
+// Stop stops the collectors and closes the client
+func (b *podmanBackend) Stop() {
+	b.pods.Stop()
+	b.containers.stats.Stop()
+	b.containers.client.Close()
+}
+
+// Populate fills in status from the collector caches. Services are UNKNOWN
+// while the socket is unreachable or a pod has not been collected yet.
+func (b *podmanBackend) Populate(ctx context.Context, svc *models.Service, cfg config.ServiceConfig) {
+	if !b.containers.client.Connected() {
+		svc.Status = "UNKNOWN"
+		return
+	}
+	if cfg.Pod == "" {
+		b.containers.populate(ctx, svc, cfg.ContainerName)
+		return
+	}
+
+	cached := b.pods.GetCachedPod(cfg.Pod)
+	if cached == nil {
+		svc.Status = "UNKNOWN"
+		return
+	}
+
+	svc.Status = cached.Status
+	if cached.Status != "ERROR" {
+		svc.Uptime = formatUptime(cached.StartedAt)
+	}
+}
+
+// Health returns the connection state of the Podman socket
+func (b *podmanBackend) Health() []models.BackendHealth {
+	return []models.BackendHealth{backendHealth("podman", "", b.containers.client.Health())}
+}
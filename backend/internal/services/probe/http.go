@@ -0,0 +1,168 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"home-run-backend/internal/config"
+)
+
+// maxBodyBytes limits how much of a response body is read for assertions
+const maxBodyBytes = 1 << 20
+
+// DefaultTimeout is used when a probe has no explicit timeout
+const DefaultTimeout = 10 * time.Second
+
+// HTTPProber checks an HTTP(S) endpoint and asserts on its response
+type HTTPProber struct {
+	target         string
+	method         string
+	headers        map[string]string
+	expectedStatus []int
+	bodyContains   string
+	bodyRegex      *regexp.Regexp
+	httpClient     *http.Client
+}
+
+// NewHTTPProber creates an HTTP prober for a service's url and port
+func NewHTTPProber(rawURL string, port int, cfg *config.HTTPProbeConfig, timeout time.Duration) (*HTTPProber, error) {
+	target, err := TargetURL(rawURL, port)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		cfg = &config.HTTPProbeConfig{}
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	p := &HTTPProber{
+		target:         target,
+		method:         strings.ToUpper(cfg.Method),
+		headers:        cfg.Headers,
+		expectedStatus: cfg.ExpectedStatus,
+		bodyContains:   cfg.BodyContains,
+		httpClient: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify},
+			},
+		},
+	}
+	if p.method == "" {
+		p.method = http.MethodGet
+	}
+	if cfg.BodyRegex != "" {
+		p.bodyRegex, err = regexp.Compile(cfg.BodyRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid body regex: %w", err)
+		}
+	}
+
+	return p, nil
+}
+
+// TargetURL combines a service url with its port, unless the url already has one
+func TargetURL(rawURL string, port int) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("url '%s' has no host", rawURL)
+	}
+	if port > 0 && u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(port))
+	}
+	return u.String(), nil
+}
+
+// Target returns the URL being probed
+func (p *HTTPProber) Target() string {
+	return p.target
+}
+
+// Probe performs a single request and checks the response
+func (p *HTTPProber) Probe(ctx context.Context) Result {
+	result := Result{CheckedAt: time.Now()}
+
+	req, err := http.NewRequestWithContext(ctx, p.method, p.target, nil)
+	if err != nil {
+		result.Status = "ERROR"
+		result.Error = err.Error()
+		return result
+	}
+	for key, value := range p.headers {
+		if strings.EqualFold(key, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(key, value)
+	}
+
+	start := time.Now()
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		result.Latency = time.Since(start)
+		result.Status = "STOPPED"
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+	result.Latency = time.Since(start)
+	result.StatusCode = resp.StatusCode
+	if err != nil {
+		result.Status = "ERROR"
+		result.Error = fmt.Sprintf("failed to read body: %v", err)
+		return result
+	}
+
+	if err := p.check(resp.StatusCode, body); err != nil {
+		result.Status = "ERROR"
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Status = "RUNNING"
+	return result
+}
+
+// check applies the configured assertions to a response
+func (p *HTTPProber) check(statusCode int, body []byte) error {
+	if !p.statusAccepted(statusCode) {
+		return fmt.Errorf("unexpected status code %d", statusCode)
+	}
+	if p.bodyContains != "" && !strings.Contains(string(body), p.bodyContains) {
+		return fmt.Errorf("body does not contain %q", p.bodyContains)
+	}
+	if p.bodyRegex != nil && !p.bodyRegex.Match(body) {
+		return fmt.Errorf("body does not match %q", p.bodyRegex.String())
+	}
+	return nil
+}
+
+// statusAccepted reports whether a status code satisfies the probe
+func (p *HTTPProber) statusAccepted(statusCode int) bool {
+	if len(p.expectedStatus) == 0 {
+		return statusCode >= 200 && statusCode < 400
+	}
+	for _, code := range p.expectedStatus {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
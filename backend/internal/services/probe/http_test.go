@@ -0,0 +1,170 @@
+package probe
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"home-run-backend/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		port     int
+		expected string
+	}{
+		{"http://localhost", 8080, "http://localhost:8080"},
+		{"https://example.com/health", 8443, "https://example.com:8443/health"},
+		{"http://localhost:9000/status", 8080, "http://localhost:9000/status"},
+		{"http://localhost", 0, "http://localhost"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			result, err := TargetURL(tt.url, tt.port)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	_, err := TargetURL("not a url", 80)
+	assert.Error(t, err)
+}
+
+func TestHTTPProber_Probe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			if r.Header.Get("X-Token") != "secret" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":"healthy","version":"1.2.3"}`))
+		case "/created":
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		cfg      *config.HTTPProbeConfig
+		expected string
+	}{
+		{"default assertions", "/ok", &config.HTTPProbeConfig{Headers: map[string]string{"X-Token": "secret"}}, "RUNNING"},
+		{"missing header", "/ok", nil, "ERROR"},
+		{"body contains", "/ok", &config.HTTPProbeConfig{Headers: map[string]string{"X-Token": "secret"}, BodyContains: "healthy"}, "RUNNING"},
+		{"body contains mismatch", "/ok", &config.HTTPProbeConfig{Headers: map[string]string{"X-Token": "secret"}, BodyContains: "degraded"}, "ERROR"},
+		{"body regex", "/ok", &config.HTTPProbeConfig{Headers: map[string]string{"X-Token": "secret"}, BodyRegex: `"version":"1\.\d+`}, "RUNNING"},
+		{"expected status", "/created", &config.HTTPProbeConfig{ExpectedStatus: []int{201}}, "RUNNING"},
+		{"unexpected status", "/created", &config.HTTPProbeConfig{ExpectedStatus: []int{200}}, "ERROR"},
+		{"server error", "/fail", nil, "ERROR"},
+		{"head method", "/created", &config.HTTPProbeConfig{Method: "head"}, "RUNNING"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prober, err := NewHTTPProber(server.URL+tt.path, 0, tt.cfg, time.Second)
+			require.NoError(t, err)
+
+			result := prober.Probe(context.Background())
+			assert.Equal(t, tt.expected, result.Status, result.Error)
+			assert.Positive(t, result.Latency)
+		})
+	}
+}
+
+func TestHTTPProber_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	prober, err := NewHTTPProber(url, 0, nil, time.Second)
+	require.NoError(t, err)
+
+	result := prober.Probe(context.Background())
+	assert.Equal(t, "STOPPED", result.Status)
+	assert.NotEmpty(t, result.Error)
+}
+
+func TestHTTPProber_TLSSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	strict, err := NewHTTPProber(server.URL, 0, nil, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "STOPPED", strict.Probe(context.Background()).Status)
+
+	insecure, err := NewHTTPProber(server.URL, 0, &config.HTTPProbeConfig{InsecureSkipVerify: true}, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "RUNNING", insecure.Probe(context.Background()).Status)
+}
+
+// staticProber returns a fixed status that the test can change while the runner probes
+type staticProber struct {
+	status atomic.Value
+}
+
+func newStaticProber(status string) *staticProber {
+	p := &staticProber{}
+	p.status.Store(status)
+	return p
+}
+
+func (p *staticProber) Probe(_ context.Context) Result {
+	return Result{Status: p.status.Load().(string), CheckedAt: time.Now()}
+}
+
+func TestRunner_TracksStatusSince(t *testing.T) {
+	prober := newStaticProber("RUNNING")
+	runner := NewRunner()
+	runner.Add("svc", prober, 10*time.Millisecond)
+	assert.True(t, runner.Scheduled("svc"))
+	assert.False(t, runner.Scheduled("missing"))
+
+	_, ok := runner.Latest("svc")
+	assert.False(t, ok, "no result before the runner starts")
+
+	runner.Start(context.Background())
+	defer runner.Stop()
+
+	var first Result
+	require.Eventually(t, func() bool {
+		first, ok = runner.Latest("svc")
+		return ok
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, "RUNNING", first.Status)
+	assert.Equal(t, first.CheckedAt, first.Since)
+
+	// Later probes with the same status keep Since
+	require.Eventually(t, func() bool {
+		latest, _ := runner.Latest("svc")
+		return latest.CheckedAt.After(first.CheckedAt)
+	}, time.Second, 5*time.Millisecond)
+	latest, _ := runner.Latest("svc")
+	assert.Equal(t, first.Since, latest.Since)
+
+	// A status change resets it
+	prober.status.Store("STOPPED")
+	require.Eventually(t, func() bool {
+		latest, _ = runner.Latest("svc")
+		return latest.Status == "STOPPED"
+	}, time.Second, 5*time.Millisecond)
+	assert.True(t, latest.Since.After(first.Since))
+	assert.False(t, latest.Since.After(latest.CheckedAt))
+
+	_, ok = runner.Latest("missing")
+	assert.False(t, ok)
+}
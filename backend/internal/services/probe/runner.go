@@ -0,0 +1,148 @@
+package probe
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"home-run-backend/internal/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Result holds the outcome of a single probe
+type Result struct {
+	Status     string // RUNNING, STOPPED, ERROR
+	Latency    time.Duration
+	Error      string
+	CheckedAt  time.Time
+	StatusCode int           // HTTP probes only
+	Connect    time.Duration // TCP probes only: time to establish the connection
+	Handshake  time.Duration // TLS TCP probes only: time to complete the handshake
+	Since      time.Time     // when the current status was first observed
+}
+
+// Prober performs a single check against a target
+type Prober interface {
+	Probe(ctx context.Context) Result
+}
+
+// check is a prober scheduled on an interval
+type check struct {
+	prober   Prober
+	interval time.Duration
+}
+
+// Runner runs probers on their intervals and keeps the latest result of each
+type Runner struct {
+	checks  map[string]check
+	results map[string]Result
+	mu      sync.RWMutex
+	running bool
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewRunner creates a new probe runner
+func NewRunner() *Runner {
+	return &Runner{
+		checks:  make(map[string]check),
+		results: make(map[string]Result),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Add schedules a prober under the given key. It must be called before Start.
+func (r *Runner) Add(key string, prober Prober, interval time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[key] = check{prober: prober, interval: interval}
+}
+
+// Start begins probing in the background
+func (r *Runner) Start(ctx context.Context) {
+	r.mu.Lock()
+	if r.running {
+		r.mu.Unlock()
+		return
+	}
+	r.running = true
+	r.mu.Unlock()
+
+	logger.WithField("checks", len(r.checks)).Info("Starting probe runner")
+
+	for key, c := range r.checks {
+		r.wg.Add(1)
+		go r.loop(ctx, key, c)
+	}
+}
+
+// Stop stops all probes and waits for in-flight checks to finish
+func (r *Runner) Stop() {
+	r.mu.Lock()
+	if r.running {
+		close(r.stopCh)
+		r.running = false
+	}
+	r.mu.Unlock()
+	r.wg.Wait()
+}
+
+// Latest returns the most recent result for a key
+func (r *Runner) Latest(key string) (Result, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result, ok := r.results[key]
+	return result, ok
+}
+
+// Scheduled reports whether a prober was added under the key
+func (r *Runner) Scheduled(key string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.checks[key]
+	return ok
+}
+
+// loop probes a single check until stopped
+func (r *Runner) loop(ctx context.Context, key string, c check) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		r.record(key, c.prober.Probe(ctx))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// record stores a result, carrying over Since while the status is unchanged
+func (r *Runner) record(key string, result Result) Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prev, ok := r.results[key]
+	if ok && prev.Status == result.Status {
+		result.Since = prev.Since
+	} else {
+		result.Since = result.CheckedAt
+		if ok {
+			logger.WithFields(logrus.Fields{
+				"check":  key,
+				"from":   prev.Status,
+				"to":     result.Status,
+				"reason": result.Error,
+			}).Info("Probe status changed")
+		}
+	}
+	r.results[key] = result
+	return result
+}
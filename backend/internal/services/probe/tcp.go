@@ -0,0 +1,109 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"home-run-backend/internal/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TCPProber checks that a TCP port accepts connections, optionally completing a TLS handshake
+type TCPProber struct {
+	address    string
+	serverName string
+	useTLS     bool
+	timeout    time.Duration
+}
+
+// NewTCPProber creates a TCP prober for the host of a service's url and its port
+func NewTCPProber(rawURL string, port int, useTLS bool, timeout time.Duration) (*TCPProber, error) {
+	host, err := HostFromURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if port <= 0 || port > 65535 {
+		return nil, fmt.Errorf("invalid port %d", port)
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &TCPProber{
+		address:    net.JoinHostPort(host, strconv.Itoa(port)),
+		serverName: host,
+		useTLS:     useTLS,
+		timeout:    timeout,
+	}, nil
+}
+
+// HostFromURL extracts the host name from a url, accepting bare host names too
+func HostFromURL(rawURL string) (string, error) {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "tcp://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("url '%s' has no host", rawURL)
+	}
+	return u.Hostname(), nil
+}
+
+// Address returns the host:port being probed
+func (p *TCPProber) Address() string {
+	return p.address
+}
+
+// Probe connects to the port and, for TLS probes, completes a handshake
+func (p *TCPProber) Probe(ctx context.Context) Result {
+	result := Result{CheckedAt: time.Now()}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	start := time.Now()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", p.address)
+	if err != nil {
+		result.Latency = time.Since(start)
+		result.Status = "STOPPED"
+		result.Error = err.Error()
+		return result
+	}
+	defer conn.Close()
+	result.Connect = time.Since(start)
+
+	if p.useTLS {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: p.serverName})
+		handshakeStart := time.Now()
+		err := tlsConn.HandshakeContext(ctx)
+		result.Handshake = time.Since(handshakeStart)
+		if err != nil {
+			result.Latency = time.Since(start)
+			result.Status = "ERROR"
+			result.Error = fmt.Sprintf("tls handshake failed: %v", err)
+			return result
+		}
+	}
+
+	result.Latency = time.Since(start)
+	result.Status = "RUNNING"
+
+	logger.WithFields(logrus.Fields{
+		"address":   p.address,
+		"connect":   result.Connect,
+		"handshake": result.Handshake,
+	}).Debug("TCP probe succeeded")
+
+	return result
+}
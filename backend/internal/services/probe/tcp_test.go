@@ -0,0 +1,99 @@
+package probe
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostFromURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"http://localhost", "localhost"},
+		{"https://db.lan:5432/path", "db.lan"},
+		{"redis.lan", "redis.lan"},
+		{"10.0.0.5", "10.0.0.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			host, err := HostFromURL(tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, host)
+		})
+	}
+
+	_, err := HostFromURL("")
+	assert.Error(t, err)
+}
+
+func TestTCPProber_Probe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	prober, err := NewTCPProber("http://127.0.0.1", port, false, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:"+strconv.Itoa(port), prober.Address())
+
+	result := prober.Probe(context.Background())
+	assert.Equal(t, "RUNNING", result.Status, result.Error)
+	assert.Positive(t, result.Latency)
+	assert.Positive(t, result.Connect)
+	assert.LessOrEqual(t, result.Connect, result.Latency)
+	assert.Zero(t, result.Handshake)
+
+	// Closed port reports STOPPED
+	listener.Close()
+	result = prober.Probe(context.Background())
+	assert.Equal(t, "STOPPED", result.Status)
+}
+
+func TestTCPProber_TLSHandshake(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+
+	// Plain TCP connect succeeds
+	plain, err := NewTCPProber("127.0.0.1", port, false, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "RUNNING", plain.Probe(context.Background()).Status)
+
+	// The test certificate is not trusted, so the handshake fails
+	secure, err := NewTCPProber("127.0.0.1", port, true, time.Second)
+	require.NoError(t, err)
+	result := secure.Probe(context.Background())
+	assert.Equal(t, "ERROR", result.Status)
+	assert.Contains(t, result.Error, "tls handshake failed")
+	assert.Positive(t, result.Connect)
+	assert.Positive(t, result.Handshake)
+}
+
+func TestNewTCPProber_InvalidPort(t *testing.T) {
+	_, err := NewTCPProber("localhost", 0, false, time.Second)
+	assert.Error(t, err)
+}
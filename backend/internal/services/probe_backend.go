@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/logger"
+	"home-run-backend/internal/models"
+	"home-run-backend/internal/services/probe"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultProbeInterval is used when a probed service has no explicit interval
+const defaultProbeInterval = 30 * time.Second
+
+func init() {
+	RegisterBackend("http", newHTTPBackend, validateHTTPService)
+	RegisterBackend("tcp", newTCPBackend, validateTCPService)
+}
+
+// probeBackend reports status from active probes run in the background
+type probeBackend struct {
+	runner *probe.Runner
+}
+
+func newHTTPBackend(_ *config.Config, services []config.ServiceConfig) (StatusBackend, error) {
+	runner := probe.NewRunner()
+	for _, svc := range services {
+		prober, err := probe.NewHTTPProber(svc.URL, svc.Port, svc.HTTP, svc.Timeout)
+		if err != nil {
+			// Leave it unscheduled so the service reports ERROR
+			logger.WithFields(logrus.Fields{
+				"service": svc.Name,
+				"error":   err.Error(),
+			}).Warn("Invalid HTTP probe")
+			continue
+		}
+		runner.Add(svc.Name, prober, probeInterval(svc))
+	}
+	return &probeBackend{runner: runner}, nil
+}
+
+// validateHTTPService checks the url, timing and response assertions of an http service
+func validateHTTPService(_ *config.Config, index int, svc config.ServiceConfig) error {
+	u, err := url.Parse(svc.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("services[%d].url must be an http(s) URL for http backend, got '%s'", index, svc.URL)
+	}
+	if err := validateProbeTiming(index, svc); err != nil {
+		return err
+	}
+	if svc.HTTP == nil {
+		return nil
+	}
+	for _, code := range svc.HTTP.ExpectedStatus {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("services[%d].http.expected_status contains invalid code %d", index, code)
+		}
+	}
+	if svc.HTTP.BodyRegex != "" {
+		if _, err := regexp.Compile(svc.HTTP.BodyRegex); err != nil {
+			return fmt.Errorf("services[%d].http.body_regex is invalid: %w", index, err)
+		}
+	}
+	return nil
+}
+
+func newTCPBackend(_ *config.Config, services []config.ServiceConfig) (StatusBackend, error) {
+	runner := probe.NewRunner()
+	for _, svc := range services {
+		prober, err := probe.NewTCPProber(svc.URL, svc.Port, svc.TLS, svc.Timeout)
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"service": svc.Name,
+				"error":   err.Error(),
+			}).Warn("Invalid TCP probe")
+			continue
+		}
+		runner.Add(svc.Name, prober, probeInterval(svc))
+	}
+	return &probeBackend{runner: runner}, nil
+}
+
+// validateTCPService checks the host, port and timing of a tcp service
+func validateTCPService(_ *config.Config, index int, svc config.ServiceConfig) error {
+	host := svc.URL
+	if !strings.Contains(host, "://") {
+		host = "tcp://" + host
+	}
+	if u, err := url.Parse(host); err != nil || u.Hostname() == "" {
+		return fmt.Errorf("services[%d].url must contain a host for tcp backend, got '%s'", index, svc.URL)
+	}
+	if svc.Port <= 0 || svc.Port > 65535 {
+		return fmt.Errorf("services[%d].port must be between 1 and 65535 for tcp backend", index)
+	}
+	return validateProbeTiming(index, svc)
+}
+
+// Start starts the probe runner
+func (b *probeBackend) Start(ctx context.Context) {
+	b.runner.Start(ctx)
+}
+
+// Stop stops the probe runner
+func (b *probeBackend) Stop() {
+	b.runner.Stop()
+}
+
+// Populate fills in status and latency from the latest probe result. Services
+// are UNKNOWN until the runner has probed them, so requests never wait on a probe.
+func (b *probeBackend) Populate(_ context.Context, svc *models.Service, cfg config.ServiceConfig) {
+	result, ok := b.runner.Latest(cfg.Name)
+	if !ok {
+		if b.runner.Scheduled(cfg.Name) {
+			svc.Status = "UNKNOWN"
+		} else {
+			svc.Status = "ERROR"
+		}
+		return
+	}
+
+	svc.Status = result.Status
+	svc.Latency = milliseconds(result.Latency)
+	svc.ConnectLatency = milliseconds(result.Connect)
+	svc.HandshakeLatency = milliseconds(result.Handshake)
+	if result.Status == "RUNNING" {
+		svc.Uptime = formatUptime(result.Since)
+	}
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// probeInterval returns the configured probe interval or the default
+func probeInterval(svc config.ServiceConfig) time.Duration {
+	if svc.Interval > 0 {
+		return svc.Interval
+	}
+	return defaultProbeInterval
+}
+
+// validateProbeTiming checks the interval and timeout shared by http and tcp probes
+func validateProbeTiming(index int, svc config.ServiceConfig) error {
+	if svc.Interval < 0 || svc.Timeout < 0 {
+		return fmt.Errorf("services[%d].interval and timeout must not be negative", index)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"home-run-backend/internal/config"
+
+	ps "github.com/shirou/gopsutil/v3/process"
+)
+
+// Matcher selects the processes that make up a service
+type Matcher struct {
+	name    string
+	cmdline *regexp.Regexp
+	pidFile string
+}
+
+// NewMatcher creates a matcher from a service's process settings.
+// A pidfile takes precedence; otherwise name and cmdline regex must both match if set.
+func NewMatcher(cfg config.ProcessMatchConfig) (*Matcher, error) {
+	m := &Matcher{
+		name:    cfg.Name,
+		pidFile: cfg.PIDFile,
+	}
+	if cfg.CmdlineRegex != "" {
+		re, err := regexp.Compile(cfg.CmdlineRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cmdline regex: %w", err)
+		}
+		m.cmdline = re
+	}
+	if m.name == "" && m.cmdline == nil && m.pidFile == "" {
+		return nil, fmt.Errorf("process matcher needs a name, cmdline_regex or pidfile")
+	}
+	return m, nil
+}
+
+// Match returns the processes from procs that belong to the service
+func (m *Matcher) Match(ctx context.Context, procs []*ps.Process) []*ps.Process {
+	if m.pidFile != "" {
+		if p := m.fromPIDFile(ctx); p != nil {
+			return []*ps.Process{p}
+		}
+		return nil
+	}
+
+	var matched []*ps.Process
+	for _, p := range procs {
+		if m.name != "" {
+			name, err := p.NameWithContext(ctx)
+			if err != nil || name != m.name {
+				continue
+			}
+		}
+		if m.cmdline != nil {
+			cmdline, err := p.CmdlineWithContext(ctx)
+			if err != nil || !m.cmdline.MatchString(cmdline) {
+				continue
+			}
+		}
+		matched = append(matched, p)
+	}
+	return matched
+}
+
+// fromPIDFile returns the process whose PID is in the pidfile, if it is running
+func (m *Matcher) fromPIDFile(ctx context.Context) *ps.Process {
+	data, err := os.ReadFile(m.pidFile)
+	if err != nil {
+		return nil
+	}
+	pid, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 32)
+	if err != nil || pid <= 0 {
+		return nil
+	}
+	p, err := ps.NewProcessWithContext(ctx, int32(pid))
+	if err != nil {
+		return nil
+	}
+	return p
+}
+
+// Usage is the combined resource usage of a set of processes
+type Usage struct {
+	PIDs       []int32
+	CPUSeconds map[int32]float64 // user+system time per PID
+	Created    map[int32]int64   // creation time per PID (ms), to detect PID reuse
+	RSSBytes   uint64
+	StartedAt  time.Time // start time of the oldest process
+}
+
+// Measure reads CPU times, RSS and start time of the given processes
+func Measure(ctx context.Context, procs []*ps.Process) Usage {
+	usage := Usage{
+		CPUSeconds: make(map[int32]float64),
+		Created:    make(map[int32]int64),
+	}
+
+	for _, p := range procs {
+		created, err := p.CreateTimeWithContext(ctx)
+		if err != nil {
+			// Process exited between listing and measuring
+			continue
+		}
+
+		usage.PIDs = append(usage.PIDs, p.Pid)
+		usage.Created[p.Pid] = created
+		startedAt := time.UnixMilli(created)
+		if usage.StartedAt.IsZero() || startedAt.Before(usage.StartedAt) {
+			usage.StartedAt = startedAt
+		}
+
+		if times, err := p.TimesWithContext(ctx); err == nil {
+			usage.CPUSeconds[p.Pid] = times.User + times.System
+		}
+		if mem, err := p.MemoryInfoWithContext(ctx); err == nil {
+			usage.RSSBytes += mem.RSS
+		}
+	}
+
+	return usage
+}
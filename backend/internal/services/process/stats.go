@@ -0,0 +1,194 @@
+package process
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"home-run-backend/internal/cache"
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/logger"
+
+	ps "github.com/shirou/gopsutil/v3/process"
+	"github.com/sirupsen/logrus"
+)
+
+// CachedStats holds cached process stats
+type CachedStats struct {
+	Status     string
+	StartedAt  time.Time
+	CPUPercent float64
+	MemoryMB   float64
+	PIDs       []int32
+	LastUpdate time.Time
+}
+
+// sample is the previous measurement of a service, used to compute CPU usage between polls
+type sample struct {
+	usage Usage
+	at    time.Time
+}
+
+// StatsCollector collects process stats periodically
+type StatsCollector struct {
+	cache    *cache.Cache
+	matchers map[string]*Matcher // keyed by service name
+	interval time.Duration
+	samples  map[string]sample
+	mu       sync.RWMutex
+	running  bool
+	stopCh   chan struct{}
+}
+
+// NewStatsCollector creates a new stats collector
+func NewStatsCollector(statsCache *cache.Cache, services []config.ServiceConfig, interval time.Duration) *StatsCollector {
+	sc := &StatsCollector{
+		cache:    statsCache,
+		matchers: make(map[string]*Matcher),
+		interval: interval,
+		samples:  make(map[string]sample),
+		stopCh:   make(chan struct{}),
+	}
+
+	for _, svc := range services {
+		if svc.Process == nil {
+			continue
+		}
+		matcher, err := NewMatcher(*svc.Process)
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"service": svc.Name,
+				"error":   err.Error(),
+			}).Warn("Invalid process matcher")
+			continue
+		}
+		sc.matchers[svc.Name] = matcher
+	}
+
+	return sc
+}
+
+// Start begins collecting stats in the background
+func (sc *StatsCollector) Start(ctx context.Context) {
+	sc.mu.Lock()
+	if sc.running {
+		sc.mu.Unlock()
+		return
+	}
+	sc.running = true
+	sc.mu.Unlock()
+
+	logger.WithField("interval", sc.interval).Info("Starting process stats collector")
+
+	// Collect immediately on start
+	sc.collectAll(ctx)
+
+	go func() {
+		ticker := time.NewTicker(sc.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Log.Info("Process stats collector stopped (context cancelled)")
+				return
+			case <-sc.stopCh:
+				logger.Log.Info("Process stats collector stopped")
+				return
+			case <-ticker.C:
+				sc.collectAll(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the stats collector
+func (sc *StatsCollector) Stop() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.running {
+		close(sc.stopCh)
+		sc.running = false
+	}
+}
+
+// collectAll lists processes once and collects stats for every service
+func (sc *StatsCollector) collectAll(ctx context.Context) {
+	logger.Log.Debug("Collecting process stats for all services")
+
+	procs, err := ps.ProcessesWithContext(ctx)
+	if err != nil {
+		logger.WithField("error", err.Error()).Warn("Failed to list processes")
+		return
+	}
+
+	for name := range sc.matchers {
+		sc.cache.Set(name, sc.Collect(ctx, name, procs))
+	}
+}
+
+// Collect measures a single service against a process listing
+func (sc *StatsCollector) Collect(ctx context.Context, name string, procs []*ps.Process) *CachedStats {
+	matcher, ok := sc.matchers[name]
+	if !ok {
+		return &CachedStats{Status: "ERROR", LastUpdate: time.Now()}
+	}
+
+	now := time.Now()
+	usage := Measure(ctx, matcher.Match(ctx, procs))
+	if len(usage.PIDs) == 0 {
+		return &CachedStats{Status: "STOPPED", LastUpdate: now}
+	}
+
+	return &CachedStats{
+		Status:     "RUNNING",
+		StartedAt:  usage.StartedAt,
+		CPUPercent: sc.cpuPercent(name, usage, now),
+		MemoryMB:   float64(usage.RSSBytes) / (1024 * 1024),
+		PIDs:       usage.PIDs,
+		LastUpdate: now,
+	}
+}
+
+// cpuPercent sums CPU usage since the previous sample over processes present in both
+func (sc *StatsCollector) cpuPercent(name string, usage Usage, now time.Time) float64 {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	prev, ok := sc.samples[name]
+	sc.samples[name] = sample{usage: usage, at: now}
+	if !ok {
+		return 0
+	}
+
+	elapsed := now.Sub(prev.at).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+
+	var delta float64
+	for pid, cpuSeconds := range usage.CPUSeconds {
+		prevSeconds, seen := prev.usage.CPUSeconds[pid]
+		if !seen || prev.usage.Created[pid] != usage.Created[pid] || cpuSeconds < prevSeconds {
+			continue
+		}
+		delta += cpuSeconds - prevSeconds
+	}
+	return delta / elapsed * 100
+}
+
+// Tracks reports whether the service has a valid matcher and is collected
+func (sc *StatsCollector) Tracks(name string) bool {
+	_, ok := sc.matchers[name]
+	return ok
+}
+
+// GetCachedStats retrieves cached stats for a service
+func (sc *StatsCollector) GetCachedStats(name string) *CachedStats {
+	if val, ok := sc.cache.Get(name); ok {
+		if stats, ok := val.(*CachedStats); ok {
+			return stats
+		}
+	}
+	return nil
+}
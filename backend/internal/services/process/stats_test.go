@@ -0,0 +1,119 @@
+package process
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"home-run-backend/internal/cache"
+	"home-run-backend/internal/config"
+
+	ps "github.com/shirou/gopsutil/v3/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// self returns the test process, which the matchers are pointed at
+func self(t *testing.T) *ps.Process {
+	t.Helper()
+	p, err := ps.NewProcess(int32(os.Getpid()))
+	require.NoError(t, err)
+	return p
+}
+
+func TestNewMatcher_Invalid(t *testing.T) {
+	_, err := NewMatcher(config.ProcessMatchConfig{})
+	assert.Error(t, err)
+
+	_, err = NewMatcher(config.ProcessMatchConfig{CmdlineRegex: "("})
+	assert.ErrorContains(t, err, "invalid cmdline regex")
+}
+
+func TestMatcher_Match(t *testing.T) {
+	ctx := context.Background()
+	me := self(t)
+	name, err := me.Name()
+	require.NoError(t, err)
+
+	pidFile := filepath.Join(t.TempDir(), "test.pid")
+	require.NoError(t, os.WriteFile(pidFile, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644))
+
+	tests := []struct {
+		name    string
+		cfg     config.ProcessMatchConfig
+		matched bool
+	}{
+		{"by name", config.ProcessMatchConfig{Name: name}, true},
+		{"by cmdline", config.ProcessMatchConfig{CmdlineRegex: regexp.QuoteMeta(filepath.Base(os.Args[0]))}, true},
+		{"name and cmdline", config.ProcessMatchConfig{Name: name, CmdlineRegex: "no-such-flag"}, false},
+		{"wrong name", config.ProcessMatchConfig{Name: "definitely-not-running"}, false},
+		{"by pidfile", config.ProcessMatchConfig{PIDFile: pidFile}, true},
+		{"missing pidfile", config.ProcessMatchConfig{PIDFile: pidFile + ".missing"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher, err := NewMatcher(tt.cfg)
+			require.NoError(t, err)
+
+			matched := matcher.Match(ctx, []*ps.Process{me})
+			if tt.matched {
+				require.Len(t, matched, 1)
+				assert.Equal(t, me.Pid, matched[0].Pid)
+			} else {
+				assert.Empty(t, matched)
+			}
+		})
+	}
+}
+
+func TestStatsCollector_Collect(t *testing.T) {
+	ctx := context.Background()
+	me := self(t)
+	name, err := me.Name()
+	require.NoError(t, err)
+
+	services := []config.ServiceConfig{
+		{Name: "Self", Process: &config.ProcessMatchConfig{Name: name}},
+		{Name: "Missing", Process: &config.ProcessMatchConfig{Name: "definitely-not-running"}},
+	}
+	sc := NewStatsCollector(cache.New(time.Minute), services, time.Minute)
+
+	stats := sc.Collect(ctx, "Self", []*ps.Process{me})
+	assert.Equal(t, "RUNNING", stats.Status)
+	assert.Equal(t, []int32{me.Pid}, stats.PIDs)
+	assert.Positive(t, stats.MemoryMB)
+	assert.False(t, stats.StartedAt.IsZero())
+	assert.True(t, stats.StartedAt.Before(time.Now()))
+
+	stats = sc.Collect(ctx, "Missing", []*ps.Process{me})
+	assert.Equal(t, "STOPPED", stats.Status)
+
+	stats = sc.Collect(ctx, "Unconfigured", []*ps.Process{me})
+	assert.Equal(t, "ERROR", stats.Status)
+
+	assert.True(t, sc.Tracks("Missing"))
+	assert.False(t, sc.Tracks("Unconfigured"))
+}
+
+func TestStatsCollector_CPUPercent(t *testing.T) {
+	sc := NewStatsCollector(cache.New(time.Minute), nil, time.Minute)
+	now := time.Now()
+
+	first := Usage{
+		CPUSeconds: map[int32]float64{100: 10, 200: 5},
+		Created:    map[int32]int64{100: 1, 200: 1},
+	}
+	assert.Zero(t, sc.cpuPercent("svc", first, now))
+
+	// PID 100 used 1s over 2s, PID 200 was replaced by a new process with the same PID
+	second := Usage{
+		CPUSeconds: map[int32]float64{100: 11, 200: 0.5},
+		Created:    map[int32]int64{100: 1, 200: 2},
+	}
+	assert.InDelta(t, 50.0, sc.cpuPercent("svc", second, now.Add(2*time.Second)), 0.001)
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"home-run-backend/internal/cache"
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/models"
+	"home-run-backend/internal/services/process"
+)
+
+func init() {
+	RegisterBackend("process", newProcessBackend, validateProcessService)
+}
+
+// processBackend reports status of plain processes on the local host
+type processBackend struct {
+	stats *process.StatsCollector
+}
+
+func newProcessBackend(_ *config.Config, services []config.ServiceConfig) (StatusBackend, error) {
+	return &processBackend{
+		stats: process.NewStatsCollector(cache.New(30*time.Second), services, 10*time.Second),
+	}, nil
+}
+
+// validateProcessService checks that a process service has a usable matcher
+func validateProcessService(_ *config.Config, index int, svc config.ServiceConfig) error {
+	if svc.Process == nil || (svc.Process.Name == "" && svc.Process.CmdlineRegex == "" && svc.Process.PIDFile == "") {
+		return fmt.Errorf("services[%d].process needs a name, cmdline_regex or pidfile for process backend", index)
+	}
+	if svc.Process.CmdlineRegex != "" {
+		if _, err := regexp.Compile(svc.Process.CmdlineRegex); err != nil {
+			return fmt.Errorf("services[%d].process.cmdline_regex is invalid: %w", index, err)
+		}
+	}
+	return nil
+}
+
+// Start starts the stats collector
+func (b *processBackend) Start(ctx context.Context) {
+	b.stats.Start(ctx)
+}
+
+// Stop stops the stats collector
+func (b *processBackend) Stop() {
+	b.stats.Stop()
+}
+
+// Populate fills in status from the matching processes. Services are
+// UNKNOWN until the collector has listed processes, so requests never do.
+func (b *processBackend) Populate(_ context.Context, svc *models.Service, cfg config.ServiceConfig) {
+	cached := b.stats.GetCachedStats(cfg.Name)
+	if cached == nil {
+		if b.stats.Tracks(cfg.Name) {
+			svc.Status = "UNKNOWN"
+		} else {
+			svc.Status = "ERROR"
+		}
+		return
+	}
+	svc.Status = cached.Status
+	svc.CPUUsage = cached.CPUPercent
+	svc.MemoryUsage = cached.MemoryMB
+	if cached.Status == "RUNNING" {
+		svc.Uptime = formatUptime(cached.StartedAt)
+	}
+}
@@ -0,0 +1,158 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"home-run-backend/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const manifestDigest = "sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac"
+
+// stubRegistry serves manifests like registry:2 behind token authentication.
+// The token service only hands out tokens for the user/secret credentials.
+func stubRegistry(t *testing.T, sendDigest bool) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != "user" || pass != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Equal(t, "repository:team/app:pull", r.URL.Query().Get("scope"))
+			assert.Equal(t, "stub", r.URL.Query().Get("service"))
+			_, _ = w.Write([]byte(`{"token":"pull-token"}`))
+
+		case r.URL.Path == "/v2/team/app/manifests/1.2":
+			if r.Header.Get("Authorization") != "Bearer pull-token" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="stub",scope="repository:team/app:pull"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Contains(t, r.Header.Get("Accept"), "application/vnd.oci.image.index.v1+json")
+			w.Header().Set("Content-Type", "application/vnd.oci.image.index.v1+json")
+			if sendDigest {
+				w.Header().Set("Docker-Content-Digest", manifestDigest)
+			}
+			if r.Method == http.MethodGet {
+				_, _ = w.Write([]byte(`{"schemaVersion":2}`))
+			}
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_Digest(t *testing.T) {
+	server := stubRegistry(t, true)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	c := NewClient(map[string]config.RegistryConfig{
+		host: {Username: "user", Password: "secret", Insecure: true},
+	})
+
+	digest, err := c.Digest(context.Background(), host+"/team/app:1.2")
+	require.NoError(t, err)
+	assert.Equal(t, manifestDigest, digest)
+
+	_, err = c.Digest(context.Background(), host+"/team/app:missing")
+	assert.ErrorContains(t, err, "status 404")
+}
+
+func TestClient_Digest_HashesManifestWithoutDigestHeader(t *testing.T) {
+	server := stubRegistry(t, false)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	c := NewClient(map[string]config.RegistryConfig{
+		host: {Username: "user", Password: "secret", Insecure: true},
+	})
+
+	digest, err := c.Digest(context.Background(), host+"/team/app:1.2")
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(`{"schemaVersion":2}`))), digest)
+}
+
+func TestClient_Digest_WrongCredentials(t *testing.T) {
+	server := stubRegistry(t, true)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	c := NewClient(map[string]config.RegistryConfig{
+		host: {Username: "user", Password: "wrong", Insecure: true},
+	})
+
+	_, err := c.Digest(context.Background(), host+"/team/app:1.2")
+	assert.ErrorContains(t, err, "token service returned status 401")
+}
+
+func TestClient_Digest_BasicAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", manifestDigest)
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	c := NewClient(map[string]config.RegistryConfig{
+		host: {Username: "user", Password: "secret", Insecure: true},
+	})
+	digest, err := c.Digest(context.Background(), host+"/app")
+	require.NoError(t, err)
+	assert.Equal(t, manifestDigest, digest)
+
+	_, err = NewClient(map[string]config.RegistryConfig{host: {Insecure: true}}).Digest(context.Background(), host+"/app")
+	assert.ErrorContains(t, err, "requires credentials")
+}
+
+func TestClient_Digest_PinnedReference(t *testing.T) {
+	_, err := NewClient(nil).Digest(context.Background(), "nginx@"+manifestDigest)
+	assert.ErrorIs(t, err, ErrNoTag)
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull"`)
+	assert.Equal(t, "Bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:library/nginx:pull",
+	}, params)
+
+	scheme, params = parseChallenge(`Basic realm=registry`)
+	assert.Equal(t, "Basic", scheme)
+	assert.Equal(t, "registry", params["realm"])
+}
+
+func TestRepoDigests(t *testing.T) {
+	repoDigests := []string{
+		"nginx@sha256:1111111111111111111111111111111111111111111111111111111111111111",
+		"ghcr.io/owner/nginx@sha256:2222222222222222222222222222222222222222222222222222222222222222",
+		"docker.io/library/nginx@sha256:3333333333333333333333333333333333333333333333333333333333333333",
+	}
+
+	assert.Equal(t, []string{
+		"sha256:1111111111111111111111111111111111111111111111111111111111111111",
+		"sha256:3333333333333333333333333333333333333333333333333333333333333333",
+	}, RepoDigests("nginx:1.27", repoDigests))
+	assert.Equal(t, []string{
+		"sha256:2222222222222222222222222222222222222222222222222222222222222222",
+	}, RepoDigests("ghcr.io/owner/nginx", repoDigests))
+	assert.Empty(t, RepoDigests("localbuild", repoDigests))
+}
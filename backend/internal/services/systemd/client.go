@@ -0,0 +1,161 @@
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"home-run-backend/internal/logger"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	destination      = "org.freedesktop.systemd1"
+	managerPath      = dbus.ObjectPath("/org/freedesktop/systemd1")
+	managerInterface = "org.freedesktop.systemd1.Manager"
+	unitInterface    = "org.freedesktop.systemd1.Unit"
+	serviceInterface = "org.freedesktop.systemd1.Service"
+	propertiesGetAll = "org.freedesktop.DBus.Properties.GetAll"
+)
+
+// UnitInfo holds status and resource accounting of a systemd unit
+type UnitInfo struct {
+	Unit         string
+	Status       string // RUNNING, STOPPED, ERROR, MAINTENANCE
+	ActiveState  string // raw systemd states
+	SubState     string
+	MainPID      uint32
+	MemoryBytes  uint64
+	CPUUsageNSec uint64
+	StartedAt    time.Time
+}
+
+// Client reads unit state from systemd over D-Bus
+type Client struct {
+	conn *dbus.Conn
+}
+
+// NewClient connects to the system bus
+func NewClient() (*Client, error) {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		logger.WithField("error", err.Error()).Warn("Failed to connect to system D-Bus")
+		return nil, fmt.Errorf("failed to connect to system bus: %w", err)
+	}
+
+	logger.Log.Info("systemd client initialized successfully")
+	return &Client{conn: conn}, nil
+}
+
+// NewClientWithConn creates a client on an existing bus connection
+func NewClientWithConn(conn *dbus.Conn) *Client {
+	return &Client{conn: conn}
+}
+
+// Close closes the bus connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// GetUnitInfo retrieves state and accounting for a unit
+func (c *Client) GetUnitInfo(ctx context.Context, unit string) (*UnitInfo, error) {
+	unit = NormalizeUnit(unit)
+
+	var path dbus.ObjectPath
+	err := c.conn.Object(destination, managerPath).
+		CallWithContext(ctx, managerInterface+".LoadUnit", 0, unit).
+		Store(&path)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"unit":  unit,
+			"error": err.Error(),
+		}).Warn("Failed to load systemd unit")
+		return nil, fmt.Errorf("failed to load unit '%s': %w", unit, err)
+	}
+
+	obj := c.conn.Object(destination, path)
+
+	var unitProps map[string]dbus.Variant
+	if err := obj.CallWithContext(ctx, propertiesGetAll, 0, unitInterface).Store(&unitProps); err != nil {
+		return nil, fmt.Errorf("failed to read unit properties: %w", err)
+	}
+
+	info := &UnitInfo{
+		Unit:        unit,
+		ActiveState: variantString(unitProps["ActiveState"]),
+		SubState:    variantString(unitProps["SubState"]),
+	}
+	if loadState := variantString(unitProps["LoadState"]); loadState == "not-found" {
+		return nil, fmt.Errorf("unit '%s' not found", unit)
+	}
+	info.Status = mapUnitState(info.ActiveState)
+
+	if ts := variantUint64(unitProps["ActiveEnterTimestamp"]); ts > 0 && info.ActiveState == "active" {
+		info.StartedAt = time.UnixMicro(int64(ts))
+	}
+
+	// Service units additionally expose the main PID and resource accounting
+	if strings.HasSuffix(unit, ".service") {
+		var serviceProps map[string]dbus.Variant
+		if err := obj.CallWithContext(ctx, propertiesGetAll, 0, serviceInterface).Store(&serviceProps); err == nil {
+			info.MainPID = variantUint32(serviceProps["MainPID"])
+			info.MemoryBytes = variantUint64(serviceProps["MemoryCurrent"])
+			info.CPUUsageNSec = variantUint64(serviceProps["CPUUsageNSec"])
+		}
+	}
+
+	logger.WithFields(logrus.Fields{
+		"unit":   unit,
+		"state":  info.ActiveState,
+		"status": info.Status,
+	}).Debug("Retrieved unit info")
+
+	return info, nil
+}
+
+// NormalizeUnit appends the .service suffix to bare unit names
+func NormalizeUnit(unit string) string {
+	if !strings.Contains(unit, ".") {
+		return unit + ".service"
+	}
+	return unit
+}
+
+// mapUnitState converts a systemd ActiveState to our status enum
+func mapUnitState(state string) string {
+	switch state {
+	case "active":
+		return "RUNNING"
+	case "inactive":
+		return "STOPPED"
+	case "activating", "deactivating", "reloading":
+		return "MAINTENANCE"
+	case "failed":
+		return "ERROR"
+	default:
+		return "ERROR"
+	}
+}
+
+func variantString(v dbus.Variant) string {
+	s, _ := v.Value().(string)
+	return s
+}
+
+func variantUint32(v dbus.Variant) uint32 {
+	n, _ := v.Value().(uint32)
+	return n
+}
+
+// variantUint64 returns a uint64 property, treating systemd's "unset" marker as zero
+func variantUint64(v dbus.Variant) uint64 {
+	n, _ := v.Value().(uint64)
+	if n == math.MaxUint64 {
+		return 0
+	}
+	return n
+}
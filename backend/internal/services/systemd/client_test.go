@@ -0,0 +1,212 @@
+package systemd
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"home-run-backend/internal/cache"
+	"home-run-backend/internal/config"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/prop"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const busConfig = `<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:path=%s</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>`
+
+// startBus runs a private dbus-daemon and returns its address
+func startBus(t *testing.T) string {
+	t.Helper()
+
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not available")
+	}
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "bus.conf")
+	socket := filepath.Join(dir, "bus.sock")
+	require.NoError(t, os.WriteFile(configPath, []byte(strings.Replace(busConfig, "%s", socket, 1)), 0644))
+
+	cmd := exec.Command(daemon, "--config-file="+configPath, "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	require.NoError(t, err)
+	return strings.TrimSpace(address)
+}
+
+// fakeManager implements the systemd Manager.LoadUnit method
+type fakeManager struct {
+	units map[string]dbus.ObjectPath
+}
+
+func (m *fakeManager) LoadUnit(name string) (dbus.ObjectPath, *dbus.Error) {
+	if path, ok := m.units[name]; ok {
+		return path, nil
+	}
+	return "/org/freedesktop/systemd1/unit/missing", nil
+}
+
+// exportFakeSystemd publishes a fake systemd on the bus with one active service
+// (jellyfin.service), one failed service (samba.service) and a missing unit
+func exportFakeSystemd(t *testing.T, conn *dbus.Conn, startedAt time.Time) {
+	t.Helper()
+
+	manager := &fakeManager{units: map[string]dbus.ObjectPath{
+		"jellyfin.service": "/org/freedesktop/systemd1/unit/jellyfin_2eservice",
+		"samba.service":    "/org/freedesktop/systemd1/unit/samba_2eservice",
+	}}
+	require.NoError(t, conn.Export(manager, managerPath, managerInterface))
+
+	units := []struct {
+		path        dbus.ObjectPath
+		loadState   string
+		activeState string
+		subState    string
+		enteredAt   uint64
+		pid         uint32
+		memory      uint64
+		cpu         uint64
+	}{
+		{"/org/freedesktop/systemd1/unit/jellyfin_2eservice", "loaded", "active", "running", uint64(startedAt.UnixMicro()), 4242, 256 * 1024 * 1024, 5e9},
+		{"/org/freedesktop/systemd1/unit/samba_2eservice", "loaded", "failed", "failed", 0, 0, ^uint64(0), ^uint64(0)},
+		{"/org/freedesktop/systemd1/unit/missing", "not-found", "inactive", "dead", 0, 0, 0, 0},
+	}
+
+	for _, u := range units {
+		_, err := prop.Export(conn, u.path, prop.Map{
+			unitInterface: {
+				"LoadState":            {Value: u.loadState},
+				"ActiveState":          {Value: u.activeState},
+				"SubState":             {Value: u.subState},
+				"ActiveEnterTimestamp": {Value: u.enteredAt},
+			},
+			serviceInterface: {
+				"MainPID":       {Value: u.pid},
+				"MemoryCurrent": {Value: u.memory},
+				"CPUUsageNSec":  {Value: u.cpu},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	reply, err := conn.RequestName(destination, dbus.NameFlagDoNotQueue)
+	require.NoError(t, err)
+	require.Equal(t, dbus.RequestNameReplyPrimaryOwner, reply)
+}
+
+func connect(t *testing.T, address string) *dbus.Conn {
+	t.Helper()
+	conn, err := dbus.Connect(address)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestClient_GetUnitInfo(t *testing.T) {
+	address := startBus(t)
+	startedAt := time.Now().Add(-2 * time.Hour).Truncate(time.Microsecond)
+	exportFakeSystemd(t, connect(t, address), startedAt)
+
+	client := NewClientWithConn(connect(t, address))
+	ctx := context.Background()
+
+	info, err := client.GetUnitInfo(ctx, "jellyfin")
+	require.NoError(t, err)
+	assert.Equal(t, "jellyfin.service", info.Unit)
+	assert.Equal(t, "RUNNING", info.Status)
+	assert.Equal(t, "running", info.SubState)
+	assert.Equal(t, uint32(4242), info.MainPID)
+	assert.Equal(t, uint64(256*1024*1024), info.MemoryBytes)
+	assert.Equal(t, uint64(5e9), info.CPUUsageNSec)
+	assert.True(t, startedAt.Equal(info.StartedAt))
+
+	info, err = client.GetUnitInfo(ctx, "samba.service")
+	require.NoError(t, err)
+	assert.Equal(t, "ERROR", info.Status)
+	assert.Zero(t, info.MemoryBytes) // accounting disabled
+	assert.True(t, info.StartedAt.IsZero())
+
+	_, err = client.GetUnitInfo(ctx, "nonexistent.service")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestMapUnitState(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"active", "RUNNING"},
+		{"inactive", "STOPPED"},
+		{"activating", "MAINTENANCE"},
+		{"deactivating", "MAINTENANCE"},
+		{"reloading", "MAINTENANCE"},
+		{"failed", "ERROR"},
+		{"unknown", "ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mapUnitState(tt.input))
+		})
+	}
+}
+
+func TestStatsCollector_CollectAll(t *testing.T) {
+	address := startBus(t)
+	exportFakeSystemd(t, connect(t, address), time.Now().Add(-time.Hour))
+
+	services := []config.ServiceConfig{{Name: "Jellyfin", Unit: "jellyfin"}, {Name: "Samba", Unit: "samba"}}
+	sc := NewStatsCollector(NewClientWithConn(connect(t, address)), cache.New(time.Minute), services, time.Second)
+	sc.collectAll(context.Background())
+
+	stats := sc.GetCachedStats("jellyfin")
+	require.NotNil(t, stats)
+	assert.Equal(t, "RUNNING", stats.Status)
+	assert.Equal(t, uint32(4242), stats.MainPID)
+	assert.Equal(t, 256.0, stats.MemoryMB)
+
+	stats = sc.GetCachedStats("samba")
+	require.NotNil(t, stats)
+	assert.Equal(t, "ERROR", stats.Status)
+	assert.Zero(t, stats.MainPID)
+}
+
+func TestStatsCollector_CPUPercent(t *testing.T) {
+	sc := NewStatsCollector(nil, nil, nil, time.Second)
+	now := time.Now()
+
+	// First sample has nothing to compare against
+	assert.Zero(t, sc.cpuPercent("jellyfin.service", 1e9, now))
+
+	// 0.5s of CPU time over 1s is 50%
+	assert.InDelta(t, 50.0, sc.cpuPercent("jellyfin.service", 1.5e9, now.Add(time.Second)), 0.001)
+
+	// Counter reset (unit restarted)
+	assert.Zero(t, sc.cpuPercent("jellyfin.service", 1e6, now.Add(2*time.Second)))
+}
@@ -0,0 +1,156 @@
+package systemd
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"home-run-backend/internal/cache"
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CachedStats holds cached unit stats
+type CachedStats struct {
+	Status     string
+	StartedAt  time.Time
+	CPUPercent float64
+	MemoryMB   float64
+	MainPID    uint32
+	LastUpdate time.Time
+}
+
+// cpuSample is a previous CPU reading used to compute usage between polls
+type cpuSample struct {
+	usageNSec uint64
+	at        time.Time
+}
+
+// StatsCollector collects systemd unit stats periodically
+type StatsCollector struct {
+	client   *Client
+	cache    *cache.Cache
+	services []config.ServiceConfig
+	interval time.Duration
+	samples  map[string]cpuSample
+	mu       sync.RWMutex
+	running  bool
+	stopCh   chan struct{}
+}
+
+// NewStatsCollector creates a new stats collector
+func NewStatsCollector(client *Client, statsCache *cache.Cache, services []config.ServiceConfig, interval time.Duration) *StatsCollector {
+	return &StatsCollector{
+		client:   client,
+		cache:    statsCache,
+		services: services,
+		interval: interval,
+		samples:  make(map[string]cpuSample),
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start begins collecting stats in the background
+func (sc *StatsCollector) Start(ctx context.Context) {
+	sc.mu.Lock()
+	if sc.running {
+		sc.mu.Unlock()
+		return
+	}
+	sc.running = true
+	sc.mu.Unlock()
+
+	logger.WithField("interval", sc.interval).Info("Starting systemd stats collector")
+
+	// Collect immediately on start
+	sc.collectAll(ctx)
+
+	go func() {
+		ticker := time.NewTicker(sc.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Log.Info("systemd stats collector stopped (context cancelled)")
+				return
+			case <-sc.stopCh:
+				logger.Log.Info("systemd stats collector stopped")
+				return
+			case <-ticker.C:
+				sc.collectAll(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the stats collector
+func (sc *StatsCollector) Stop() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.running {
+		close(sc.stopCh)
+		sc.running = false
+	}
+}
+
+// collectAll collects stats for all services routed to the collector
+func (sc *StatsCollector) collectAll(ctx context.Context) {
+	logger.Log.Debug("Collecting systemd stats for all units")
+	for _, svc := range sc.services {
+		unit := NormalizeUnit(svc.Unit)
+
+		info, err := sc.client.GetUnitInfo(ctx, unit)
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"unit":  unit,
+				"error": err.Error(),
+			}).Warn("Failed to get unit info")
+			sc.cache.Set(unit, &CachedStats{
+				Status:     "ERROR",
+				LastUpdate: time.Now(),
+			})
+			continue
+		}
+
+		cached := &CachedStats{
+			Status:     info.Status,
+			StartedAt:  info.StartedAt,
+			MemoryMB:   float64(info.MemoryBytes) / (1024 * 1024),
+			MainPID:    info.MainPID,
+			CPUPercent: sc.cpuPercent(unit, info.CPUUsageNSec, time.Now()),
+			LastUpdate: time.Now(),
+		}
+		sc.cache.Set(unit, cached)
+	}
+}
+
+// cpuPercent computes CPU usage since the previous sample of a unit
+func (sc *StatsCollector) cpuPercent(unit string, usageNSec uint64, now time.Time) float64 {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	prev, ok := sc.samples[unit]
+	sc.samples[unit] = cpuSample{usageNSec: usageNSec, at: now}
+
+	if !ok || usageNSec < prev.usageNSec {
+		return 0
+	}
+	elapsed := now.Sub(prev.at)
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(usageNSec-prev.usageNSec) / float64(elapsed.Nanoseconds()) * 100
+}
+
+// GetCachedStats retrieves cached stats for a unit
+func (sc *StatsCollector) GetCachedStats(unit string) *CachedStats {
+	if val, ok := sc.cache.Get(NormalizeUnit(unit)); ok {
+		if stats, ok := val.(*CachedStats); ok {
+			return stats
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"home-run-backend/internal/cache"
+	"home-run-backend/internal/config"
+	"home-run-backend/internal/models"
+	"home-run-backend/internal/services/systemd"
+)
+
+func init() {
+	RegisterBackend("systemd", newSystemdBackend, validateSystemdService)
+}
+
+// systemdBackend reports status of systemd units on the local host
+type systemdBackend struct {
+	client *systemd.Client
+	stats  *systemd.StatsCollector
+}
+
+func newSystemdBackend(_ *config.Config, services []config.ServiceConfig) (StatusBackend, error) {
+	client, err := systemd.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &systemdBackend{
+		client: client,
+		stats:  systemd.NewStatsCollector(client, cache.New(30*time.Second), services, 10*time.Second),
+	}, nil
+}
+
+// validateSystemdService checks that a systemd service names a unit
+func validateSystemdService(_ *config.Config, index int, svc config.ServiceConfig) error {
+	if svc.Unit == "" {
+		return fmt.Errorf("services[%d].unit is required for systemd backend", index)
+	}
+	return nil
+}
+
+// Start starts the stats collector
+func (b *systemdBackend) Start(ctx context.Context) {
+	b.stats.Start(ctx)
+}
+
+// Stop stops the stats collector and closes the bus connection
+func (b *systemdBackend) Stop() {
+	b.stats.Stop()
+	b.client.Close()
+}
+
+// Populate fills in status from systemd
+func (b *systemdBackend) Populate(ctx context.Context, svc *models.Service, cfg config.ServiceConfig) {
+	// Try cache first
+	if cached := b.stats.GetCachedStats(cfg.Unit); cached != nil {
+		svc.Status = cached.Status
+		svc.CPUUsage = cached.CPUPercent
+		svc.MemoryUsage = cached.MemoryMB
+		svc.MainPID = cached.MainPID
+		svc.Uptime = formatUptime(cached.StartedAt)
+		return
+	}
+
+	// Fallback to live query (no CPU usage without a previous sample)
+	info, err := b.client.GetUnitInfo(ctx, cfg.Unit)
+	if err != nil {
+		svc.Status = "ERROR"
+		return
+	}
+
+	svc.Status = info.Status
+	svc.MemoryUsage = float64(info.MemoryBytes) / (1024 * 1024)
+	svc.MainPID = info.MainPID
+	svc.Uptime = formatUptime(info.StartedAt)
+}
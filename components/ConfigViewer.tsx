@@ -1,22 +1,39 @@
 import React, { useState, useEffect } from 'react';
 import { Service, ServiceConfig } from '../types';
 import SimpleHighlighter from './SyntaxHighlighter';
-import { X, FileCode, Cpu, Terminal, Copy, Check, ExternalLink, BarChart3, Settings, FileText, Clock, RefreshCw } from 'lucide-react';
+import { X, FileCode, Cpu, Terminal, Copy, Check, ExternalLink, BarChart3, Settings, FileText, Clock, RefreshCw, Play, Square, RotateCw, Pause, Info } from 'lucide-react';
 import { analyzeConfiguration } from '../services/geminiService';
-import { getServiceConfig } from '../services/api';
+import { getServiceConfig, getServiceHistory, runServiceAction, ServiceAction } from '../services/api';
 import Toast, { ToastType } from './Toast';
+import LogViewer from './LogViewer';
+import ContainerDetails from './ContainerDetails';
 
 interface ConfigViewerProps {
   service: Service;
   onClose: () => void;
 }
 
-type TabMode = 'config' | 'metrics';
+type TabMode = 'config' | 'metrics' | 'logs' | 'details';
+
+const SERVICE_ACTIONS: { action: ServiceAction; label: string; icon: React.FC<{ className?: string }> }[] = [
+  { action: 'start', label: 'Start', icon: Play },
+  { action: 'stop', label: 'Stop', icon: Square },
+  { action: 'restart', label: 'Restart', icon: RotateCw },
+  { action: 'pause', label: 'Pause', icon: Pause },
+  { action: 'unpause', label: 'Unpause', icon: Play },
+];
 type ConfigSubMode = 'code' | 'analysis';
 
+const formatRate = (bytesPerSec?: number) => {
+  const value = bytesPerSec ?? 0;
+  if (value >= 1024 * 1024) return `${(value / (1024 * 1024)).toFixed(1)} MB/s`;
+  if (value >= 1024) return `${(value / 1024).toFixed(1)} KB/s`;
+  return `${value.toFixed(0)} B/s`;
+};
+
 // Helper component for stacked-style bar charts
 const ResourceChart: React.FC<{
-  data: number[];
+  data: (number | null)[]; // null where nothing was recorded
   max: number;
   colorClass: string;
   label: string;
@@ -46,14 +63,14 @@ const ResourceChart: React.FC<{
             {/* Tooltip */}
             <div className="absolute bottom-full left-1/2 -translate-x-1/2 mb-2 hidden group-hover:block z-10">
               <div className="bg-slate-900 text-xs text-white px-2 py-1 rounded border border-slate-700 whitespace-nowrap">
-                {value.toFixed(1)}{unit}
+                {value === null ? 'No data' : `${value.toFixed(1)}${unit}`}
               </div>
             </div>
             {/* Bar - "Stacked" visual by having a background track */}
             <div className="w-full bg-slate-700/30 rounded-t-sm h-full relative overflow-hidden">
                 <div
                   className={`w-full absolute bottom-0 transition-all duration-500 ${colorClass}`}
-                  style={{ height: `${(value / max) * 100}%` }}
+                  style={{ height: `${((value ?? 0) / max) * 100}%` }}
                 ></div>
             </div>
           </div>
@@ -83,12 +100,19 @@ const ConfigViewer: React.FC<ConfigViewerProps> = ({ service, onClose }) => {
 
   const [copied, setCopied] = useState(false);
   const [toast, setToast] = useState<{ message: string; type: ToastType } | null>(null);
+  const [pendingAction, setPendingAction] = useState<ServiceAction | null>(null);
 
-  // Mock metrics data state
+  // Actions only apply to containers on this host, not federated services
+  const canRunActions = service.allowActions && (!service.host || service.host === 'local');
+  // Logs and details are per container; compose projects list several
+  const isLocalContainer = (!service.host || service.host === 'local') && !service.containers;
+
+  // Recorded metrics of the last hour
   const [metricsData, setMetricsData] = useState<{
-    cpu: number[];
-    memory: number[];
+    cpu: (number | null)[];
+    memory: (number | null)[];
   }>({ cpu: [], memory: [] });
+  const [metricsError, setMetricsError] = useState<string | null>(null);
 
   const activeConfig = service.configs?.[selectedFileIndex];
 
@@ -124,24 +148,33 @@ const ConfigViewer: React.FC<ConfigViewerProps> = ({ service, onClose }) => {
     }
   }, [selectedFileIndex, activeTab, service.id, service.configs?.length, activeConfig?.content, loadedConfigs]);
 
-  // Generate mock historical data when service changes
+  // Load the last hour of recorded metrics, in 2.5 minute steps
   useEffect(() => {
-    const dataPoints = 24; // e.g., every 2.5 mins for an hour
-
-    // Generate CPU data with some variance around the current usage
-    const cpuHistory = Array.from({ length: dataPoints }, () => {
-      const variance = Math.random() * 10 - 5;
-      return Math.max(0, Math.min(100, service.cpuUsage + variance));
-    });
+    if (activeTab !== 'metrics') return;
+    let cancelled = false;
 
-    // Generate Memory data (MB)
-    const memHistory = Array.from({ length: dataPoints }, () => {
-      const variance = Math.random() * 200 - 100;
-      return Math.max(0, service.memoryUsage + variance);
-    });
+    const loadMetrics = async () => {
+      try {
+        const [cpu, memory] = await Promise.all([
+          getServiceHistory(service.id, 'cpu', '1h', '150s'),
+          getServiceHistory(service.id, 'memory', '1h', '150s'),
+        ]);
+        if (cancelled) return;
+        setMetricsData({
+          cpu: cpu.points.map(p => p.value),
+          memory: memory.points.map(p => p.value),
+        });
+        setMetricsError(null);
+      } catch (err: any) {
+        if (!cancelled) setMetricsError(err.message);
+      }
+    };
 
-    setMetricsData({ cpu: cpuHistory, memory: memHistory });
-  }, [service]);
+    loadMetrics();
+    return () => {
+      cancelled = true;
+    };
+  }, [activeTab, service.id, service.cpuUsage, service.memoryUsage]);
 
   // Get the config content to display (from loaded or original)
   const displayConfig = loadedConfigs[selectedFileIndex] || activeConfig;
@@ -182,6 +215,18 @@ const ConfigViewer: React.FC<ConfigViewerProps> = ({ service, onClose }) => {
     window.open(service.url, '_blank');
   };
 
+  const handleAction = async (action: ServiceAction) => {
+    setPendingAction(action);
+    try {
+      const info = await runServiceAction(service.id, action);
+      setToast({ message: `${service.name}: ${action} done, now ${info.status}`, type: 'success' });
+    } catch (error: any) {
+      setToast({ message: error.message, type: 'error' });
+    } finally {
+      setPendingAction(null);
+    }
+  };
+
   // Safe navigation between files
   const handleFileSelect = (index: number) => {
     setSelectedFileIndex(index);
@@ -229,12 +274,25 @@ const ConfigViewer: React.FC<ConfigViewerProps> = ({ service, onClose }) => {
               </div>
             </div>
           </div>
-          <button
-            onClick={onClose}
-            className="p-2 hover:bg-slate-800 rounded-lg transition-colors text-slate-400 hover:text-white"
-          >
-            <X className="w-6 h-6" />
-          </button>
+          <div className="flex items-center gap-2">
+            {canRunActions && SERVICE_ACTIONS.map(({ action, label, icon: Icon }) => (
+              <button
+                key={action}
+                onClick={() => handleAction(action)}
+                disabled={pendingAction !== null}
+                className="p-2 bg-slate-800/80 hover:bg-slate-700 disabled:opacity-50 rounded-lg transition-colors text-slate-400 hover:text-white"
+                title={label}
+              >
+                {pendingAction === action ? <RefreshCw className="w-4 h-4 animate-spin" /> : <Icon className="w-4 h-4" />}
+              </button>
+            ))}
+            <button
+              onClick={onClose}
+              className="p-2 hover:bg-slate-800 rounded-lg transition-colors text-slate-400 hover:text-white"
+            >
+              <X className="w-6 h-6" />
+            </button>
+          </div>
         </div>
 
         {/* Top Tab Navigation */}
@@ -264,6 +322,32 @@ const ConfigViewer: React.FC<ConfigViewerProps> = ({ service, onClose }) => {
             <BarChart3 className="w-4 h-4" />
             Metrics
           </button>
+          {isLocalContainer && (
+            <button
+              onClick={() => setActiveTab('logs')}
+              className={`flex items-center gap-2 px-4 py-4 text-sm font-medium border-b-2 transition-all ${
+                activeTab === 'logs'
+                  ? 'border-indigo-500 text-white'
+                  : 'border-transparent text-slate-400 hover:text-slate-200'
+              }`}
+            >
+              <Terminal className="w-4 h-4" />
+              Logs
+            </button>
+          )}
+          {isLocalContainer && (
+            <button
+              onClick={() => setActiveTab('details')}
+              className={`flex items-center gap-2 px-4 py-4 text-sm font-medium border-b-2 transition-all ${
+                activeTab === 'details'
+                  ? 'border-indigo-500 text-white'
+                  : 'border-transparent text-slate-400 hover:text-slate-200'
+              }`}
+            >
+              <Info className="w-4 h-4" />
+              Details
+            </button>
+          )}
         </div>
 
         {/* Content Area */}
@@ -418,15 +502,27 @@ const ConfigViewer: React.FC<ConfigViewerProps> = ({ service, onClose }) => {
             </>
           )}
 
+          {/* LOGS VIEW */}
+          {activeTab === 'logs' && <LogViewer serviceId={service.id} />}
+
+          {/* CONTAINER DETAILS VIEW */}
+          {activeTab === 'details' && <ContainerDetails serviceId={service.id} />}
+
           {/* METRICS VIEW */}
           {activeTab === 'metrics' && (
             <div className="flex-1 p-8 overflow-y-auto custom-scrollbar bg-slate-900">
               <div className="max-w-4xl mx-auto space-y-8">
                 <div className="mb-8">
                   <h2 className="text-2xl font-bold text-white mb-2">Resource Usage</h2>
-                  <p className="text-slate-400">Real-time performance metrics for the last hour.</p>
+                  <p className="text-slate-400">Recorded performance metrics for the last hour.</p>
                 </div>
 
+                {metricsError && (
+                  <div className="p-4 rounded-lg bg-amber-500/10 border border-amber-500/30 text-amber-300 text-sm">
+                    No history available: {metricsError}. Set <code className="font-mono">history.path</code> in config.yml to record metrics.
+                  </div>
+                )}
+
                 <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
                   <ResourceChart
                     label="CPU Usage"
@@ -441,12 +537,32 @@ const ConfigViewer: React.FC<ConfigViewerProps> = ({ service, onClose }) => {
                     label="Memory Usage"
                     unit="MB"
                     data={metricsData.memory}
-                    max={Math.max(...metricsData.memory, 1) * 1.2}
+                    max={Math.max(...metricsData.memory.map(v => v ?? 0), 1) * 1.2}
                     colorClass="bg-emerald-500"
                     average={service.memoryUsage}
                   />
                 </div>
 
+                {/* Container I/O */}
+                {service.memoryLimit !== undefined && (
+                  <div className="grid grid-cols-2 md:grid-cols-4 gap-4">
+                    {[
+                      { label: 'Network In', value: formatRate(service.networkRx) },
+                      { label: 'Network Out', value: formatRate(service.networkTx) },
+                      { label: 'Disk Read', value: formatRate(service.blockRead) },
+                      { label: 'Disk Write', value: formatRate(service.blockWrite) },
+                      { label: 'Memory Limit', value: `${service.memoryLimit.toFixed(0)} MB` },
+                      { label: 'Memory Used', value: `${(service.memoryPercent ?? 0).toFixed(1)}%` },
+                      { label: 'Processes', value: String(service.pids ?? 0) },
+                    ].map(({ label, value }) => (
+                      <div key={label} className="bg-slate-800/50 rounded-xl p-4 border border-slate-700/50">
+                        <p className="text-slate-400 text-xs mb-1">{label}</p>
+                        <p className="text-white font-mono text-sm">{value}</p>
+                      </div>
+                    ))}
+                  </div>
+                )}
+
                 {/* Additional Info Box */}
                 <div className="bg-slate-800/30 border border-slate-800 rounded-xl p-6 flex items-start gap-4">
                   <div className="p-3 bg-slate-800 rounded-lg">
@@ -455,12 +571,29 @@ const ConfigViewer: React.FC<ConfigViewerProps> = ({ service, onClose }) => {
                   <div>
                     <h4 className="text-white font-medium mb-1">Service Health</h4>
                     <p className="text-sm text-slate-400 mb-3">
-                      The service has been running for <span className="text-white font-mono">{service.uptime || 'Unknown'}</span>.
+                      The service has been running for <span className="text-white font-mono">{service.uptime || 'Unknown'}</span>
+                      {service.mainPid ? <> as PID <span className="text-white font-mono">{service.mainPid}</span></> : null}.
                     </p>
                     <div className="flex gap-2 flex-wrap">
                        <span className="text-[10px] px-2 py-1 bg-slate-800 border border-slate-700 rounded text-slate-400">Port: {service.port}</span>
                        <span className="text-[10px] px-2 py-1 bg-slate-800 border border-slate-700 rounded text-slate-400">Status: {service.status}</span>
+                       {service.health && (
+                         <span className={`text-[10px] px-2 py-1 bg-slate-800 border border-slate-700 rounded ${service.health.status === 'unhealthy' ? 'text-orange-400' : 'text-slate-400'}`}>
+                           Health: {service.health.status}{service.health.failingStreak > 0 && ` (${service.health.failingStreak} failing)`}
+                         </span>
+                       )}
+                       {(service.restartCount ?? 0) > 0 && (
+                         <span className="text-[10px] px-2 py-1 bg-slate-800 border border-slate-700 rounded text-slate-400">Restarts: {service.restartCount}</span>
+                       )}
+                       {service.oomKilled && (
+                         <span className="text-[10px] px-2 py-1 bg-slate-800 border border-slate-700 rounded text-rose-400">Out of memory</span>
+                       )}
                     </div>
+                    {service.health?.status === 'unhealthy' && service.health.log?.length ? (
+                      <pre className="mt-3 p-3 bg-slate-900 border border-slate-800 rounded text-[11px] text-slate-400 whitespace-pre-wrap font-mono">
+                        {service.health.log[service.health.log.length - 1].output || `exit code ${service.health.log[service.health.log.length - 1].exitCode}`}
+                      </pre>
+                    ) : null}
                   </div>
                 </div>
               </div>
@@ -0,0 +1,169 @@
+import React, { useState, useEffect } from 'react';
+import { Box, Network, HardDrive, Tag, KeyRound, RefreshCw } from 'lucide-react';
+import { getServiceInspect, ContainerDetails as Details } from '../services/api';
+
+interface ContainerDetailsProps {
+  serviceId: string;
+}
+
+const Section: React.FC<{ title: string; icon: React.FC<{ className?: string }>; children: React.ReactNode }> = ({ title, icon: Icon, children }) => (
+  <div className="bg-slate-800/50 rounded-xl p-5 border border-slate-700/50">
+    <h3 className="text-slate-200 font-medium text-sm flex items-center gap-2 mb-3">
+      <Icon className="w-4 h-4 text-slate-400" />
+      {title}
+    </h3>
+    {children}
+  </div>
+);
+
+const Empty: React.FC<{ text: string }> = ({ text }) => (
+  <p className="text-xs text-slate-500">{text}</p>
+);
+
+const ContainerDetails: React.FC<ContainerDetailsProps> = ({ serviceId }) => {
+  const [details, setDetails] = useState<Details | null>(null);
+  const [error, setError] = useState<string | null>(null);
+  const [isLoading, setIsLoading] = useState(false);
+
+  const load = async () => {
+    setIsLoading(true);
+    setError(null);
+    try {
+      setDetails(await getServiceInspect(serviceId));
+    } catch (err: any) {
+      setError(err.message || 'Failed to inspect container');
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    load();
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [serviceId]);
+
+  if (error) {
+    return (
+      <div className="flex-1 flex items-center justify-center bg-slate-900 text-sm text-red-400">
+        {error}
+      </div>
+    );
+  }
+
+  if (!details) {
+    return (
+      <div className="flex-1 flex items-center justify-center bg-slate-900 text-slate-500">
+        <RefreshCw className="w-5 h-5 animate-spin" />
+      </div>
+    );
+  }
+
+  const labels = Object.entries(details.labels).sort(([a], [b]) => a.localeCompare(b));
+
+  return (
+    <div className="flex-1 p-8 overflow-y-auto custom-scrollbar bg-slate-900">
+      <div className="max-w-4xl mx-auto space-y-6">
+        <div className="flex items-start justify-between">
+          <div>
+            <h2 className="text-xl font-bold text-white">{details.name}</h2>
+            <p className="text-xs text-slate-500 font-mono mt-1">{details.id} · {details.command}</p>
+          </div>
+          <button
+            onClick={load}
+            disabled={isLoading}
+            className="p-2 rounded-lg text-slate-400 hover:text-white hover:bg-slate-800 transition-colors disabled:opacity-50"
+            title="Refresh"
+          >
+            <RefreshCw className={`w-4 h-4 ${isLoading ? 'animate-spin' : ''}`} />
+          </button>
+        </div>
+
+        <Section title="Image" icon={Box}>
+          <dl className="grid grid-cols-[8rem_1fr] gap-y-1 text-xs font-mono">
+            <dt className="text-slate-500">Image</dt>
+            <dd className="text-slate-200 break-all">{details.image}</dd>
+            <dt className="text-slate-500">Digest</dt>
+            <dd className="text-slate-200 break-all">{details.digest || '—'}</dd>
+            <dt className="text-slate-500">Created</dt>
+            <dd className="text-slate-200">{new Date(details.created).toLocaleString()}</dd>
+            <dt className="text-slate-500">Restart policy</dt>
+            <dd className="text-slate-200">
+              {details.restartPolicy.name || 'no'}
+              {details.restartPolicy.maximumRetryCount ? ` (max ${details.restartPolicy.maximumRetryCount})` : ''}
+            </dd>
+          </dl>
+        </Section>
+
+        <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
+          <Section title="Ports" icon={Network}>
+            {details.ports.length === 0 ? <Empty text="No ports exposed" /> : (
+              <ul className="space-y-1 text-xs font-mono text-slate-200">
+                {details.ports.map((p, i) => (
+                  <li key={i}>
+                    {p.hostPort ? `${p.hostIp || '0.0.0.0'}:${p.hostPort} → ` : <span className="text-slate-500">not published → </span>}
+                    {p.containerPort}/{p.protocol}
+                  </li>
+                ))}
+              </ul>
+            )}
+          </Section>
+
+          <Section title="Networks" icon={Network}>
+            {details.networks.length === 0 ? <Empty text="Not attached to any network" /> : (
+              <ul className="space-y-1 text-xs font-mono text-slate-200">
+                {details.networks.map(n => (
+                  <li key={n.name}>
+                    <span className="text-indigo-300">{n.name}</span>
+                    {n.ipAddress && ` ${n.ipAddress}`}
+                    {n.ipv6 && ` ${n.ipv6}`}
+                    {n.aliases?.length ? <span className="text-slate-500"> ({n.aliases.join(', ')})</span> : null}
+                  </li>
+                ))}
+              </ul>
+            )}
+          </Section>
+        </div>
+
+        <Section title="Mounts" icon={HardDrive}>
+          {details.mounts.length === 0 ? <Empty text="No mounts" /> : (
+            <ul className="space-y-1 text-xs font-mono text-slate-200">
+              {details.mounts.map((m, i) => (
+                <li key={i} className="break-all">
+                  <span className="text-slate-500">[{m.type}]</span> {m.name || m.source} → {m.destination}
+                  {m.readOnly && <span className="ml-2 text-amber-400">ro</span>}
+                </li>
+              ))}
+            </ul>
+          )}
+        </Section>
+
+        <Section title="Environment" icon={KeyRound}>
+          {details.env.length === 0 ? <Empty text="No environment variables" /> : (
+            <ul className="space-y-1 text-xs font-mono text-slate-200">
+              {details.env.map((e, i) => (
+                <li key={i} className="break-all">
+                  <span className="text-emerald-300">{e.name}</span>=
+                  <span className={e.redacted ? 'text-slate-500' : ''}>{e.value}</span>
+                </li>
+              ))}
+            </ul>
+          )}
+        </Section>
+
+        <Section title="Labels" icon={Tag}>
+          {labels.length === 0 ? <Empty text="No labels" /> : (
+            <ul className="space-y-1 text-xs font-mono text-slate-200">
+              {labels.map(([key, value]) => (
+                <li key={key} className="break-all">
+                  <span className="text-slate-400">{key}</span>={value}
+                </li>
+              ))}
+            </ul>
+          )}
+        </Section>
+      </div>
+    </div>
+  );
+};
+
+export default ContainerDetails;
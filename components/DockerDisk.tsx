@@ -0,0 +1,123 @@
+import React, { useState, useEffect, useCallback } from 'react';
+import { Container, Trash2 } from 'lucide-react';
+import { getDockerDisk, pruneDocker, DockerDiskUsage, DiskCategory } from '../services/api';
+
+const POLL_INTERVAL = 60000; // 1 minute, system df is expensive
+
+const formatBytes = (bytes: number) => {
+  if (bytes < 0) return 'n/a';
+  const units = ['B', 'KB', 'MB', 'GB', 'TB'];
+  let value = bytes;
+  let unit = 0;
+  while (value >= 1024 && unit < units.length - 1) {
+    value /= 1024;
+    unit++;
+  }
+  return `${value.toFixed(unit === 0 ? 0 : 1)} ${units[unit]}`;
+};
+
+const categories: { key: 'images' | 'containers' | 'volumes' | 'buildCache'; label: string }[] = [
+  { key: 'images', label: 'Images' },
+  { key: 'containers', label: 'Containers' },
+  { key: 'volumes', label: 'Volumes' },
+  { key: 'buildCache', label: 'Build Cache' },
+];
+
+// Docker disk usage per daemon; hidden when no docker daemon is configured
+const DockerDisk: React.FC = () => {
+  const [daemons, setDaemons] = useState<DockerDiskUsage[]>([]);
+  const [pruning, setPruning] = useState<string | null>(null);
+  const [message, setMessage] = useState<string | null>(null);
+
+  const fetchUsage = useCallback(async () => {
+    try {
+      const data = await getDockerDisk();
+      setDaemons(data.daemons);
+    } catch {
+      setDaemons([]);
+    }
+  }, []);
+
+  useEffect(() => {
+    fetchUsage();
+    const interval = setInterval(fetchUsage, POLL_INTERVAL);
+    return () => clearInterval(interval);
+  }, [fetchUsage]);
+
+  const handlePrune = async (dockerHost: string) => {
+    if (!window.confirm('Remove unmonitored stopped containers, dangling images, anonymous volumes and unused build cache?')) {
+      return;
+    }
+    setPruning(dockerHost);
+    setMessage(null);
+    try {
+      const report = await pruneDocker({ dockerHost, containers: true, images: true, volumes: true, buildCache: true });
+      setMessage(`Reclaimed ${formatBytes(report.reclaimedBytes)}`);
+      await fetchUsage();
+    } catch (err: any) {
+      setMessage(err.message || 'Prune failed');
+    } finally {
+      setPruning(null);
+    }
+  };
+
+  if (daemons.length === 0) {
+    return null;
+  }
+
+  const Category = ({ label, usage }: { label: string; usage: DiskCategory }) => (
+    <div>
+      <p className="text-xs text-slate-500">{label}</p>
+      <p className="font-mono text-sm text-white">{formatBytes(usage.sizeBytes)}</p>
+      <p className="text-xs text-slate-500 font-mono">
+        {usage.active}/{usage.count} active, {formatBytes(usage.reclaimableBytes)} free
+      </p>
+    </div>
+  );
+
+  return (
+    <div className="grid grid-cols-1 gap-6 mb-8">
+      {daemons.map((daemon) => (
+        <div
+          key={daemon.dockerHost || 'local'}
+          className="bg-slate-900/40 backdrop-blur-md border border-slate-800 rounded-xl p-5 hover:border-cyan-500/30 transition-all shadow-lg shadow-black/20 group"
+        >
+          <div className="flex items-center justify-between mb-4">
+            <div className="flex items-center gap-3">
+              <div className="p-2.5 bg-slate-800 rounded-lg group-hover:bg-cyan-500/10 transition-colors">
+                <Container className="w-5 h-5 text-cyan-400" />
+              </div>
+              <span className="text-sm font-medium text-slate-300">
+                Docker Disk{daemon.dockerHost && <span className="text-slate-500"> ({daemon.dockerHost})</span>}
+              </span>
+            </div>
+            <button
+              onClick={() => handlePrune(daemon.dockerHost)}
+              disabled={pruning !== null}
+              className="flex items-center gap-1.5 px-3 py-1.5 text-xs text-slate-400 hover:text-rose-400 border border-slate-700 rounded-lg transition-colors disabled:opacity-50"
+              title="Prune unused data"
+            >
+              <Trash2 className="w-3.5 h-3.5" />
+              {pruning === daemon.dockerHost ? 'Pruning...' : 'Prune'}
+            </button>
+          </div>
+          <div className="grid grid-cols-2 md:grid-cols-4 gap-4">
+            {categories.map(({ key, label }) => (
+              <Category key={key} label={label} usage={daemon[key]} />
+            ))}
+          </div>
+          {(daemon.danglingImages.length > 0 || daemon.unusedVolumes.length > 0) && (
+            <p className="text-xs text-slate-500 mt-4">
+              {daemon.danglingImages.length} dangling images, {daemon.unusedVolumes.length} unused volumes
+            </p>
+          )}
+          {message && pruning === null && (
+            <p className="text-xs text-slate-400 mt-2">{message}</p>
+          )}
+        </div>
+      ))}
+    </div>
+  );
+};
+
+export default DockerDisk;
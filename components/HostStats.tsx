@@ -1,12 +1,14 @@
 import React, { useState, useEffect } from 'react';
 import { Cpu, CircuitBoard, HardDrive, AlertCircle } from 'lucide-react';
-import { getHostStats, HostStats as HostStatsType } from '../services/api';
+import { getHostHistory, getHostStats, HostStats as HostStatsType } from '../services/api';
 
 const POLL_INTERVAL = 5000; // 5 seconds
+const HISTORY_POLL_INTERVAL = 60000; // 1 minute
 
 const HostStats: React.FC = () => {
   const [stats, setStats] = useState<HostStatsType | null>(null);
   const [error, setError] = useState<string | null>(null);
+  const [cpuHistory, setCpuHistory] = useState<(number | null)[]>([]);
 
   useEffect(() => {
     const fetchStats = async () => {
@@ -24,6 +26,22 @@ const HostStats: React.FC = () => {
     return () => clearInterval(interval);
   }, []);
 
+  // CPU of the last hour per minute, on the same steps as service history
+  useEffect(() => {
+    const fetchHistory = async () => {
+      try {
+        const series = await getHostHistory('cpu', '1h', '1m');
+        setCpuHistory(series.points.map(p => p.value));
+      } catch {
+        setCpuHistory([]);
+      }
+    };
+
+    fetchHistory();
+    const interval = setInterval(fetchHistory, HISTORY_POLL_INTERVAL);
+    return () => clearInterval(interval);
+  }, []);
+
   const getUsageColor = (percent: number) => {
     if (percent > 80) return 'bg-rose-500';
     if (percent > 60) return 'bg-amber-500';
@@ -93,6 +111,18 @@ const HostStats: React.FC = () => {
             <span className={`font-mono text-xl font-bold ${getTextColor(cpuPercent)}`}>{cpuPercent.toFixed(1)}%</span>
         </div>
         <ProgressBar percent={cpuPercent} colorClass={getUsageColor(cpuPercent)} />
+        {cpuHistory.length > 0 && (
+          <div className="h-8 flex items-end gap-px mt-3" title="CPU over the last hour">
+            {cpuHistory.map((value, i) => (
+              <div
+                key={i}
+                className={`flex-1 rounded-t-sm ${value === null ? 'bg-slate-800' : getUsageColor(value)}`}
+                style={{ height: `${Math.max(value ?? 0, 2)}%` }}
+                title={value === null ? 'No data' : `${value.toFixed(1)}% ${60 - i}m ago`}
+              />
+            ))}
+          </div>
+        )}
         <p className="text-xs text-slate-500 mt-2 font-mono">{stats.cpu.cores} Cores / {stats.cpu.threads} Threads</p>
       </div>
 
@@ -0,0 +1,103 @@
+import React, { useState, useEffect, useRef } from 'react';
+import { Terminal, Search, Pause, Play } from 'lucide-react';
+import { serviceLogsUrl, LogLine } from '../services/api';
+
+const TAIL_LINES = 200;
+const MAX_LINES = 2000; // Keep the DOM small while following busy containers
+
+interface LogViewerProps {
+  serviceId: string;
+}
+
+const LogViewer: React.FC<LogViewerProps> = ({ serviceId }) => {
+  const [lines, setLines] = useState<LogLine[]>([]);
+  const [error, setError] = useState<string | null>(null);
+  const [follow, setFollow] = useState(true);
+  const [grepInput, setGrepInput] = useState('');
+  const [grep, setGrep] = useState('');
+  const bottomRef = useRef<HTMLDivElement>(null);
+
+  useEffect(() => {
+    setLines([]);
+    setError(null);
+
+    const source = new EventSource(
+      serviceLogsUrl(serviceId, { tail: TAIL_LINES, follow, grep }),
+      { withCredentials: true },
+    );
+
+    source.addEventListener('log', (e) => {
+      const line = JSON.parse((e as MessageEvent).data) as LogLine;
+      setLines(prev => [...prev.slice(-(MAX_LINES - 1)), line]);
+    });
+    source.addEventListener('end', () => source.close());
+    source.addEventListener('error', (e) => {
+      // Server-sent error events carry a message; connection failures don't
+      const data = (e as MessageEvent).data;
+      setError(data ? JSON.parse(data).error : 'Logs are not available for this service');
+      source.close();
+    });
+
+    return () => source.close();
+  }, [serviceId, follow, grep]);
+
+  useEffect(() => {
+    if (follow) {
+      bottomRef.current?.scrollIntoView({ block: 'end' });
+    }
+  }, [lines, follow]);
+
+  const handleFilter = (e: React.FormEvent) => {
+    e.preventDefault();
+    setGrep(grepInput.trim());
+  };
+
+  return (
+    <div className="flex-1 flex flex-col overflow-hidden">
+      <div className="flex items-center justify-between gap-4 px-6 py-3 border-b border-slate-800 bg-slate-900">
+        <form onSubmit={handleFilter} className="flex items-center gap-2 flex-1 max-w-md">
+          <Search className="w-4 h-4 text-slate-500" />
+          <input
+            value={grepInput}
+            onChange={(e) => setGrepInput(e.target.value)}
+            placeholder="Filter (regular expression), press Enter"
+            className="flex-1 bg-slate-800/50 border border-slate-700 rounded-lg px-3 py-1.5 text-xs font-mono text-slate-200 placeholder-slate-500 focus:outline-none focus:border-indigo-500"
+          />
+        </form>
+        <button
+          onClick={() => setFollow(!follow)}
+          className="flex items-center gap-2 px-3 py-1.5 text-xs font-medium rounded-lg bg-slate-800 hover:bg-slate-700 text-slate-300 transition-colors"
+        >
+          {follow ? <Pause className="w-3.5 h-3.5" /> : <Play className="w-3.5 h-3.5" />}
+          {follow ? 'Pause' : 'Follow'}
+        </button>
+      </div>
+
+      <div className="flex-1 overflow-auto custom-scrollbar p-4 font-mono text-xs leading-relaxed">
+        {error ? (
+          <div className="h-64 flex flex-col items-center justify-center text-red-400">
+            <Terminal className="w-12 h-12 opacity-50 mb-4" />
+            <p>{error}</p>
+          </div>
+        ) : lines.length === 0 ? (
+          <div className="h-64 flex flex-col items-center justify-center text-slate-500">
+            <Terminal className="w-12 h-12 opacity-20 mb-4" />
+            <p>{grep ? 'No matching log lines' : 'Waiting for log output...'}</p>
+          </div>
+        ) : (
+          lines.map((line, idx) => (
+            <div key={idx} className="flex gap-3 whitespace-pre-wrap break-all">
+              <span className="text-slate-600 shrink-0">
+                {line.time ? new Date(line.time).toLocaleTimeString() : ''}
+              </span>
+              <span className={line.stream === 'stderr' ? 'text-rose-300' : 'text-slate-300'}>{line.text}</span>
+            </div>
+          ))
+        )}
+        <div ref={bottomRef} />
+      </div>
+    </div>
+  );
+};
+
+export default LogViewer;
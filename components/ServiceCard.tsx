@@ -15,10 +15,29 @@ const ServiceCard: React.FC<ServiceCardProps> = ({ service, onClick }) => {
       case ServiceStatus.STOPPED: return 'bg-rose-500 shadow-[0_0_10px_rgba(244,63,94,0.4)]';
       case ServiceStatus.ERROR: return 'bg-red-600 shadow-[0_0_10px_rgba(220,38,38,0.4)]';
       case ServiceStatus.MAINTENANCE: return 'bg-amber-500 shadow-[0_0_10px_rgba(245,158,11,0.4)]';
+      case ServiceStatus.WARNING: return 'bg-orange-500 shadow-[0_0_10px_rgba(249,115,22,0.4)]';
+      case ServiceStatus.UNKNOWN: return 'bg-slate-500';
       default: return 'bg-slate-500';
     }
   };
 
+  const getHeartbeatColor = (status: ServiceStatus) => {
+    switch (status) {
+      case ServiceStatus.RUNNING: return 'bg-emerald-500/80';
+      case ServiceStatus.STOPPED: return 'bg-rose-500/80';
+      case ServiceStatus.MAINTENANCE: return 'bg-amber-500/80';
+      default: return 'bg-slate-600';
+    }
+  };
+
+  const latencyTitle = () => {
+    const parts: string[] = [];
+    if (service.connectLatency) parts.push(`connect ${service.connectLatency.toFixed(1)} ms`);
+    if (service.handshakeLatency) parts.push(`TLS handshake ${service.handshakeLatency.toFixed(1)} ms`);
+    if (service.avgLatency) parts.push(`${service.avgLatency.toFixed(0)} ms average over 24h`);
+    return parts.length > 0 ? parts.join(', ') : undefined;
+  };
+
   const handleOpenService = (e: React.MouseEvent) => {
     e.stopPropagation();
     const fullUrl = `${service.url}:${service.port}`;
@@ -45,14 +64,47 @@ const ServiceCard: React.FC<ServiceCardProps> = ({ service, onClick }) => {
 
       <div className="flex items-center justify-between mb-4">
         <h3 className="text-xl font-bold text-white group-hover:text-indigo-200 transition-colors truncate pr-4">{service.name}</h3>
-        <div className={`w-3 h-3 shrink-0 rounded-full ${getStatusColor(service.status)}`} title={service.status} />
+        <div className="flex items-center gap-2 shrink-0">
+          {service.updateAvailable && (
+            <span className="px-1.5 py-0.5 rounded-md bg-sky-500/10 border border-sky-500/30 text-sky-300 text-[10px] font-medium uppercase tracking-wider" title="A newer image is available in the registry">
+              Update
+            </span>
+          )}
+          <div className={`w-3 h-3 shrink-0 rounded-full ${getStatusColor(service.status)}`} title={service.status} />
+        </div>
       </div>
 
+      {service.heartbeats && service.heartbeats.length > 0 && (
+        <div className="flex items-end gap-px h-4 mb-3" title={service.uptime30d !== undefined ? `${service.uptime30d.toFixed(2)}% uptime over 30 days` : undefined}>
+          {service.heartbeats.slice(-40).map((beat, i) => (
+            <div
+              key={i}
+              className={`flex-1 h-full rounded-sm ${getHeartbeatColor(beat.status)}`}
+              title={`${new Date(beat.time).toLocaleString()}: ${beat.message || beat.status}${beat.latency ? ` (${beat.latency.toFixed(0)} ms)` : ''}`}
+            />
+          ))}
+        </div>
+      )}
+
       <div className="mt-auto pt-4 border-t border-slate-800/50 flex items-center justify-between text-xs text-slate-500">
         <div className="flex flex-col">
            <span className="uppercase tracking-wider text-[10px] opacity-70">Uptime</span>
            <span className="font-mono text-slate-300">{service.uptime}</span>
         </div>
+        {service.containers && (
+          <div className="flex flex-col items-center" title={service.containers.map(c => `${c.name}: ${c.status}`).join('\n')}>
+             <span className="uppercase tracking-wider text-[10px] opacity-70">Containers</span>
+             <span className="font-mono text-slate-300">
+               {service.containers.filter(c => c.status === ServiceStatus.RUNNING || c.status === ServiceStatus.WARNING).length}/{service.containers.length} up
+             </span>
+          </div>
+        )}
+        {service.latency !== undefined && (
+          <div className="flex flex-col items-center">
+             <span className="uppercase tracking-wider text-[10px] opacity-70">Latency</span>
+             <span className="font-mono text-slate-300" title={latencyTitle()}>{service.latency.toFixed(0)} ms</span>
+          </div>
+        )}
         <div className="flex flex-col items-end">
            <span className="uppercase tracking-wider text-[10px] opacity-70">Port</span>
            <span className="font-mono text-slate-300">{service.port}</span>
@@ -64,6 +64,202 @@ export async function getServiceConfig(serviceId: string, configIndex: number):
   return apiFetch<ServiceConfig>(`/services/${serviceId}/configs/${configIndex}`);
 }
 
+export type ServiceAction = 'start' | 'stop' | 'restart' | 'pause' | 'unpause';
+
+export interface ContainerInfo {
+  id: string;
+  status: string;
+  state: string;
+  health?: string;
+  startedAt: string;
+}
+
+export async function runServiceAction(serviceId: string, action: ServiceAction): Promise<ContainerInfo> {
+  return apiFetch<ContainerInfo>(`/services/${serviceId}/${action}`, { method: 'POST' });
+}
+
+export interface LogLine {
+  time: string;
+  stream: 'stdout' | 'stderr';
+  text: string;
+}
+
+export interface LogQuery {
+  tail?: number | 'all';
+  since?: string; // RFC 3339, unix timestamp or duration such as 10m
+  follow?: boolean;
+  grep?: string; // regular expression
+  invert?: boolean;
+}
+
+// URL of a service's log stream, consumed with EventSource ('log', 'end' and 'error' events)
+export function serviceLogsUrl(serviceId: string, query: LogQuery = {}): string {
+  const params = new URLSearchParams();
+  if (query.tail !== undefined) params.set('tail', String(query.tail));
+  if (query.since) params.set('since', query.since);
+  if (query.follow) params.set('follow', 'true');
+  if (query.grep) params.set('grep', query.grep);
+  if (query.invert) params.set('invert', 'true');
+  const qs = params.toString();
+  return `${API_BASE_URL}/services/${serviceId}/logs${qs ? `?${qs}` : ''}`;
+}
+
+export interface ContainerDetails {
+  id: string;
+  name: string;
+  image: string;
+  imageId: string;
+  digest?: string;
+  created: string;
+  command: string;
+  ports: { containerPort: number; protocol: string; hostIp?: string; hostPort?: string }[];
+  mounts: { type: string; name?: string; source: string; destination: string; readOnly: boolean }[];
+  networks: { name: string; ipAddress?: string; ipv6?: string; gateway?: string; macAddress?: string; aliases?: string[] }[];
+  labels: Record<string, string>;
+  restartPolicy: { name: string; maximumRetryCount?: number };
+  env: { name: string; value: string; redacted?: boolean }[];
+}
+
+export async function getServiceInspect(serviceId: string): Promise<ContainerDetails> {
+  return apiFetch<ContainerDetails>(`/services/${serviceId}/inspect`);
+}
+
+export type HistoryMetric = 'cpu' | 'memory' | 'latency' | 'status';
+
+export interface HistoryPoint {
+  time: string;
+  value: number | null; // null when nothing was recorded in the step
+}
+
+export interface HistorySeries {
+  metric: HistoryMetric;
+  unit: string;
+  from: string;
+  to: string;
+  step: string;
+  source: 'raw' | 'rollup_5m' | 'live';
+  points: HistoryPoint[];
+}
+
+// range and step are durations like 15m, 24h or 7d; the step defaults to range/60
+export async function getServiceHistory(
+  serviceId: string,
+  metric: HistoryMetric,
+  range = '1h',
+  step?: string
+): Promise<HistorySeries> {
+  const params = new URLSearchParams({ metric, range });
+  if (step) params.set('step', step);
+  return apiFetch<HistorySeries>(`/services/${serviceId}/history?${params.toString()}`);
+}
+
+// Auto-Heal API
+export interface HealEvent {
+  time: string;
+  service: string;
+  container: string;
+  dockerHost?: string;
+  reason: string; // unhealthy, or exited with a non-zero code
+  action: 'restart' | 'skip'; // skip: max_restarts reached
+  success: boolean;
+  error?: string;
+}
+
+export async function getAutoHealEvents(): Promise<{ events: HealEvent[] }> {
+  return apiFetch<{ events: HealEvent[] }>('/docker/autoheal');
+}
+
+// Backend Health API
+export interface BackendHealth {
+  backend: string;
+  name?: string; // docker_hosts key, empty for the local daemon
+  address?: string;
+  connected: boolean;
+  error?: string;
+  attempts?: number; // failed reconnects since it was last reachable
+  since?: string;
+  lastCheck?: string;
+}
+
+export async function getBackendHealth(): Promise<{ backends: BackendHealth[]; healthy: boolean }> {
+  return apiFetch<{ backends: BackendHealth[]; healthy: boolean }>('/health/backends');
+}
+
+// Uptime Kuma API
+export interface KumaMonitor {
+  id: number;
+  name: string;
+  type: string;
+  url?: string;
+  hostname?: string;
+  port?: number;
+  service?: string; // configured service with this kuma_monitor_id
+}
+
+export interface KumaSyncResult {
+  added: KumaMonitor[];
+  updated: KumaMonitor[];
+  missing: number[]; // kuma_monitor_id values Uptime Kuma no longer has
+  diff: string; // unified diff of config.yml
+  written: boolean;
+}
+
+export async function getKumaMonitors(): Promise<{ monitors: KumaMonitor[] }> {
+  return apiFetch<{ monitors: KumaMonitor[] }>('/kuma/monitors');
+}
+
+export async function syncKumaMonitors(dryRun: boolean): Promise<KumaSyncResult> {
+  return apiFetch<KumaSyncResult>('/kuma/sync', {
+    method: 'POST',
+    body: JSON.stringify({ dryRun }),
+  });
+}
+
+// Docker Disk API
+export interface DiskCategory {
+  count: number;
+  active: number;
+  sizeBytes: number;
+  reclaimableBytes: number;
+}
+
+export interface DockerDiskUsage {
+  dockerHost: string; // empty for the local daemon
+  images: DiskCategory;
+  containers: DiskCategory;
+  volumes: DiskCategory;
+  buildCache: DiskCategory;
+  danglingImages: { id: string; sizeBytes: number; created: string }[];
+  unusedVolumes: { name: string; driver: string; sizeBytes: number; anonymous: boolean }[];
+}
+
+export interface PruneOptions {
+  dockerHost?: string;
+  containers?: boolean;
+  images?: boolean;
+  volumes?: boolean;
+  buildCache?: boolean;
+}
+
+export interface PruneReport {
+  containers: number;
+  images: number;
+  volumes: number;
+  buildCache: number;
+  reclaimedBytes: number;
+}
+
+export async function getDockerDisk(): Promise<{ daemons: DockerDiskUsage[] }> {
+  return apiFetch<{ daemons: DockerDiskUsage[] }>('/docker/disk');
+}
+
+export async function pruneDocker(options: PruneOptions): Promise<PruneReport> {
+  return apiFetch<PruneReport>('/docker/disk/prune', {
+    method: 'POST',
+    body: JSON.stringify(options),
+  });
+}
+
 // Host Stats API
 export interface HostStats {
   cpu: {
@@ -84,3 +280,15 @@ export interface HostStats {
 export async function getHostStats(): Promise<HostStats> {
   return apiFetch<HostStats>('/host/stats');
 }
+
+// The host only has cpu and memory history. Ranges up to 1h with steps under
+// history.interval come from the last hour kept in memory.
+export async function getHostHistory(
+  metric: 'cpu' | 'memory',
+  range = '1h',
+  step?: string
+): Promise<HistorySeries> {
+  const params = new URLSearchParams({ metric, range });
+  if (step) params.set('step', step);
+  return apiFetch<HistorySeries>(`/host/history?${params.toString()}`);
+}
@@ -3,6 +3,8 @@ export enum ServiceStatus {
   STOPPED = 'STOPPED',
   ERROR = 'ERROR',
   MAINTENANCE = 'MAINTENANCE',
+  WARNING = 'WARNING',
+  UNKNOWN = 'UNKNOWN', // backend daemon unreachable
 }
 
 export enum ConfigType {
@@ -19,6 +21,31 @@ export interface ServiceConfig {
   lastEdited: string;
 }
 
+export interface HealthProbe {
+  start: string;
+  exitCode: number;
+  output: string;
+}
+
+export interface ContainerHealth {
+  status: 'healthy' | 'unhealthy' | 'starting';
+  failingStreak: number;
+  log?: HealthProbe[]; // most recent runs, oldest first
+}
+
+export interface ServiceContainer {
+  name: string;
+  service?: string; // compose service
+  status: ServiceStatus;
+}
+
+export interface Heartbeat {
+  time: string;
+  status: ServiceStatus;
+  latency?: number; // ms
+  message?: string;
+}
+
 export interface Service {
   id: string;
   name: string;
@@ -29,6 +56,28 @@ export interface Service {
   uptime: string;
   cpuUsage: number; // Percent
   memoryUsage: number; // MB
+  memoryLimit?: number; // MB, containers
+  memoryPercent?: number; // of the limit, containers
+  networkRx?: number; // bytes/s, containers
+  networkTx?: number; // bytes/s, containers
+  blockRead?: number; // bytes/s, containers
+  blockWrite?: number; // bytes/s, containers
+  pids?: number; // containers
+  mainPid?: number; // systemd units, absent if not running
+  latency?: number; // ms, for probed services and Uptime Kuma monitors
+  connectLatency?: number; // ms, tcp probes
+  handshakeLatency?: number; // ms, tcp probes with tls
+  certExpiresInDays?: number; // for https services
+  uptime24h?: number; // percent, Uptime Kuma monitors
+  uptime30d?: number; // percent, Uptime Kuma monitors
+  avgLatency?: number; // ms over 24h, Uptime Kuma monitors
+  heartbeats?: Heartbeat[]; // recent Uptime Kuma checks, oldest first
+  allowActions?: boolean; // container start/stop/restart/pause/unpause enabled
+  health?: ContainerHealth; // docker healthcheck
+  restartCount?: number; // automatic container restarts
+  oomKilled?: boolean; // last exit was an out-of-memory kill
+  containers?: ServiceContainer[]; // members of a compose project
+  updateAvailable?: boolean; // registry has a newer image for the tag
   host?: string; // For federated services - 'local' or remote host name
 }
 